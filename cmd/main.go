@@ -16,15 +16,21 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
+	"github.com/Adembc/lazyssh/internal/adapters/cli"
 	"github.com/Adembc/lazyssh/internal/adapters/data/ssh_config_file"
 	"github.com/Adembc/lazyssh/internal/logger"
+	"github.com/Adembc/lazyssh/internal/settings"
 
 	"github.com/Adembc/lazyssh/internal/adapters/ui"
+	"github.com/Adembc/lazyssh/internal/core/ports"
 	"github.com/Adembc/lazyssh/internal/core/services"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"go.uber.org/zap"
 )
 
 var (
@@ -33,27 +39,47 @@ var (
 )
 
 func main() {
-	log, err := logger.New("LAZYSSH")
+	home, err := os.UserHomeDir()
 	if err != nil {
-		fmt.Println(err)
+		_, _ = fmt.Fprintln(os.Stderr, "failed to get user home directory:", err)
 		os.Exit(1)
 	}
 
-	//nolint:errcheck // log.Sync may return an error which is safe to ignore here
-	defer log.Sync()
+	var flags settings.Flags
+	parseSettingsFlags(&flags, os.Args[1:])
+	cfg, err := settings.Resolve(home, flags, os.Getenv)
+	if err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 
-	home, err := os.UserHomeDir()
+	log, err := logger.New("LAZYSSH", cfg.Log.Level, cfg.Log.File)
 	if err != nil {
-		log.Errorw("failed to get user home directory", "error", err)
-		//nolint:gocritic // exitAfterDefer: ensure immediate exit on unrecoverable error
+		fmt.Println(err)
 		os.Exit(1)
 	}
-	sshConfigFile := filepath.Join(home, ".ssh", "config")
-	metaDataFile := filepath.Join(home, ".lazyssh", "metadata.json")
 
-	serverRepo := ssh_config_file.NewRepository(log, sshConfigFile, metaDataFile)
-	serverService := services.NewServerService(log, serverRepo)
-	tui := ui.NewTUI(log, serverService, version, gitCommit)
+	//nolint:errcheck // log.Sync may return an error which is safe to ignore here
+	defer log.Sync()
+
+	// Every workspace gets its own repository and service; the CLI uses the selected one.
+	workspaces := make([]ui.Workspace, 0, len(cfg.Workspaces)+1)
+	for _, w := range cfg.AllWorkspaces() {
+		sortMode, err := ui.ParseSortMode(w.Sort)
+		if err != nil {
+			log.Warnw("invalid sort mode", "workspace", w.Name, "error", err)
+		}
+		workspaces = append(workspaces, ui.Workspace{
+			Name:         w.Name,
+			ConfigPath:   w.SSHConfig,
+			MetadataPath: w.Metadata,
+			Service:      newServerService(log, cfg, w, home),
+			SortMode:     sortMode,
+		})
+	}
+	current := cfg.CurrentWorkspace()
+	serverService := workspaces[current].Service
+	tui := ui.NewTUI(log, serverService, version, gitCommit, ui.WithWorkspaces(workspaces, current))
 
 	rootCmd := &cobra.Command{
 		Use:   ui.AppName,
@@ -63,9 +89,51 @@ func main() {
 		},
 	}
 	rootCmd.SilenceUsage = true
+	rootCmd.SilenceErrors = true
+	flags.Register(rootCmd.PersistentFlags())
+	rootCmd.AddCommand(cli.NewCommands(serverService)...)
 
 	if err := rootCmd.Execute(); err != nil {
 		_, _ = fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
+
+// newServerService wires the repository and service of one workspace.
+func newServerService(log *zap.SugaredLogger, cfg settings.Settings, w settings.Workspace, home string) ports.ServerService {
+	repo := ssh_config_file.NewRepository(log, w.SSHConfig, w.Metadata,
+		ssh_config_file.WithFilePolicy(cfg.FilePolicy()),
+		ssh_config_file.WithMaxBackups(cfg.MaxBackups),
+		ssh_config_file.WithBackupRetention(cfg.Backups.MaxAge, int64(cfg.Backups.MaxSize)),
+		ssh_config_file.WithBackupDir(cfg.Backups.Dir),
+		ssh_config_file.WithBackupCompression(cfg.Backups.Compress),
+		ssh_config_file.WithGitVersioning(cfg.Git.Enabled))
+	monitor := services.MonitorConfig{
+		Interval:    cfg.Monitor.Interval,
+		Concurrency: cfg.Monitor.Concurrency,
+		Tags:        cfg.Monitor.Tags,
+		History:     cfg.Monitor.History,
+		Banner:      cfg.Monitor.Probe == "banner",
+	}
+	if !cfg.Monitor.Enabled {
+		monitor.Interval = 0
+	}
+	opts := []services.Option{services.WithPingTimeout(cfg.PingTimeout), services.WithMonitor(monitor)}
+	// Only pass -F for a custom config: it also makes ssh skip /etc/ssh/ssh_config.
+	if filepath.Clean(w.SSHConfig) != settings.Defaults(home).SSHConfig {
+		opts = append(opts, services.WithSSHConfigFile(w.SSHConfig))
+	}
+	return services.NewServerService(log, repo, opts...)
+}
+
+// parseSettingsFlags reads the settings flags before cobra runs, since the logger,
+// repository and service they configure must exist before any command is built.
+// cobra parses them again (with the same targets) and reports unknown flags.
+func parseSettingsFlags(flags *settings.Flags, args []string) {
+	fs := pflag.NewFlagSet(ui.AppName, pflag.ContinueOnError)
+	fs.ParseErrorsWhitelist.UnknownFlags = true
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {}
+	flags.Register(fs)
+	_ = fs.Parse(args)
+}
@@ -0,0 +1,125 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/core/ports"
+	"github.com/spf13/cobra"
+)
+
+func newBackupsCommand(ss ports.ServerService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backups",
+		Short: "List, compare and restore the config backups lazyssh keeps",
+		Long: "lazyssh backs up a config file before each change. Backups are referred to " +
+			"by their number in `lazyssh backups list` or by their file name.",
+	}
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "list",
+			Short: "List backups, newest first for each config file",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				backups, err := ss.ListBackups()
+				if err != nil {
+					return err
+				}
+				return writeBackups(cmd.OutOrStdout(), backups)
+			},
+		},
+		&cobra.Command{
+			Use:     "diff <backup>",
+			Short:   "Show what changed in the config file since the backup",
+			Example: "  lazyssh backups diff 1",
+			Args:    cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				backup, err := findBackup(ss, args[0])
+				if err != nil {
+					return err
+				}
+				diff, err := ss.DiffBackup(backup)
+				if err != nil {
+					return err
+				}
+				if diff == "" {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s is the same as %s\n", backup.ConfigFile, backup.Name())
+					return nil
+				}
+				_, _ = io.WriteString(cmd.OutOrStdout(), diff)
+				return nil
+			},
+		},
+		&cobra.Command{
+			Use:   "restore <backup>",
+			Short: "Replace the config file with the backup (the current content is backed up first)",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				backup, err := findBackup(ss, args[0])
+				if err != nil {
+					return err
+				}
+				if err := ss.RestoreBackup(backup); err != nil {
+					return err
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Restored %s from %s\n", backup.ConfigFile, backup.Name())
+				return nil
+			},
+		},
+	)
+	return cmd
+}
+
+func writeBackups(w io.Writer, backups []domain.Backup) error {
+	if len(backups) == 0 {
+		_, err := fmt.Fprintln(w, "No backups yet")
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "#\tCONFIG\tBACKUP\tTAKEN\tSIZE")
+	for i, b := range backups {
+		taken := b.CreatedAt.Format("2006-01-02 15:04:05")
+		if b.Original {
+			taken += " (original)"
+		}
+		_, _ = fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%d B\n", i+1, b.ConfigFile, b.Name(), taken, b.Size)
+	}
+	return tw.Flush()
+}
+
+// findBackup resolves a backup by its number in `backups list`, its file name or its path.
+func findBackup(ss ports.ServerService, ref string) (domain.Backup, error) {
+	backups, err := ss.ListBackups()
+	if err != nil {
+		return domain.Backup{}, err
+	}
+	if n, err := strconv.Atoi(ref); err == nil {
+		if n < 1 || n > len(backups) {
+			return domain.Backup{}, fmt.Errorf("no backup #%d, there are %d", n, len(backups))
+		}
+		return backups[n-1], nil
+	}
+	for _, b := range backups {
+		if b.Name() == ref || b.Path == ref {
+			return b, nil
+		}
+	}
+	return domain.Backup{}, fmt.Errorf("unknown backup %q, see `lazyssh backups list`", ref)
+}
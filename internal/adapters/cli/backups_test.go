@@ -0,0 +1,56 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestWriteBackups(t *testing.T) {
+	taken := time.Date(2025, 3, 1, 12, 30, 0, 0, time.Local)
+	backups := []domain.Backup{
+		{Path: "/home/u/.ssh/config-1740832200000-lazyssh.backup", ConfigFile: "/home/u/.ssh/config", CreatedAt: taken, Size: 120},
+		{Path: "/home/u/.ssh/config.original.backup", ConfigFile: "/home/u/.ssh/config", Original: true, CreatedAt: taken, Size: 80},
+	}
+	var buf bytes.Buffer
+	if err := writeBackups(&buf, backups); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want a header and 2 rows:\n%s", len(lines), buf.String())
+	}
+	for i, want := range [][]string{
+		{"#", "CONFIG", "BACKUP", "TAKEN", "SIZE"},
+		{"1", "config-1740832200000-lazyssh.backup", "2025-03-01 12:30:00", "120 B"},
+		{"2", "config.original.backup", "(original)", "80 B"},
+	} {
+		for _, w := range want {
+			if !strings.Contains(lines[i], w) {
+				t.Errorf("line %d %q missing %q", i, lines[i], w)
+			}
+		}
+	}
+
+	buf.Reset()
+	if err := writeBackups(&buf, nil); err != nil || !strings.Contains(buf.String(), "No backups") {
+		t.Errorf("writeBackups(nil) = %q, %v", buf.String(), err)
+	}
+}
@@ -0,0 +1,282 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/core/ports"
+	"github.com/spf13/cobra"
+)
+
+// NewCommands returns the non-interactive subcommands that manage the server inventory.
+// Every command goes through the ServerService so validation, backups and metadata
+// updates behave exactly like they do in the TUI.
+func NewCommands(ss ports.ServerService) []*cobra.Command {
+	return []*cobra.Command{
+		newListCommand(ss),
+		newExportCommand(ss),
+		newShowCommand(ss),
+		newAddCommand(ss),
+		newEditCommand(ss),
+		newRemoveCommand(ss),
+		newResolveCommand(ss),
+		newExecCommand(ss),
+		newBackupsCommand(ss),
+	}
+}
+
+func newListCommand(ss ports.ServerService) *cobra.Command {
+	var output string
+	cmd := &cobra.Command{
+		Use:   "list [query]",
+		Short: "List servers, optionally filtered by a fuzzy query",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listServers(cmd, ss, args, output)
+		},
+	}
+	addOutputFlag(cmd, &output, outputTable)
+	return cmd
+}
+
+func newExportCommand(ss ports.ServerService) *cobra.Command {
+	var output string
+	cmd := &cobra.Command{
+		Use:   "export [query]",
+		Short: "Export servers with all settings and lazyssh metadata",
+		Long: "Export servers with every setting plus lazyssh metadata (tags, last seen, pin, " +
+			"SSH count, source file, read-only) for use in scripts, pickers or inventories.",
+		Example: "  lazyssh export -o yaml\n  lazyssh export -o tsv | cut -f1,3 | fzf",
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listServers(cmd, ss, args, output)
+		},
+	}
+	addOutputFlag(cmd, &output, outputJSON)
+	return cmd
+}
+
+func listServers(cmd *cobra.Command, ss ports.ServerService, args []string, output string) error {
+	query := ""
+	if len(args) == 1 {
+		query = args[0]
+	}
+	servers, err := ss.ListServers(query)
+	if err != nil {
+		return err
+	}
+	return writeServers(cmd.OutOrStdout(), servers, output)
+}
+
+func newShowCommand(ss ports.ServerService) *cobra.Command {
+	var output string
+	cmd := &cobra.Command{
+		Use:   "show <alias>",
+		Short: "Show all settings of a server",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			server, err := findServer(ss, args[0])
+			if err != nil {
+				return err
+			}
+			return writeServer(cmd.OutOrStdout(), server, output)
+		},
+	}
+	addOutputFlag(cmd, &output, outputTable)
+	return cmd
+}
+
+func newAddCommand(ss ports.ServerService) *cobra.Command {
+	var (
+		host string
+		user string
+		port int
+		keys []string
+		tags []string
+		sets []string
+		file string
+	)
+	cmd := &cobra.Command{
+		Use:   "add <alias>",
+		Short: "Add a new server to the SSH config",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			server := domain.Server{
+				Alias:         args[0],
+				Host:          host,
+				User:          user,
+				Port:          port,
+				IdentityFiles: keys,
+				Tags:          tags,
+			}
+			if file != "" {
+				abs, err := filepath.Abs(file)
+				if err != nil {
+					return err
+				}
+				server.SourceFile = abs
+			}
+			if err := applyAssignments(&server, sets); err != nil {
+				return err
+			}
+			if err := ss.AddServer(server); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Added %s\n", server.Alias)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&host, "host", "", "host name or IP address (HostName)")
+	cmd.Flags().StringVar(&user, "user", "", "login user")
+	cmd.Flags().IntVar(&port, "port", 0, "SSH port (omitted from the config when not set)")
+	cmd.Flags().StringSliceVar(&keys, "key", nil, "identity file (repeatable)")
+	cmd.Flags().StringSliceVar(&tags, "tag", nil, "tag (repeatable)")
+	cmd.Flags().StringArrayVar(&sets, "set", nil, "additional setting as Key=Value (repeatable)")
+	cmd.Flags().StringVar(&file, "file", "", "included config file to add the server to (default: main config)")
+	_ = cmd.MarkFlagRequired("host")
+	return cmd
+}
+
+func newEditCommand(ss ports.ServerService) *cobra.Command {
+	var sets []string
+	cmd := &cobra.Command{
+		Use:   "edit <alias>",
+		Short: "Change settings of an existing server",
+		Long: "Change settings of an existing server.\n\n" +
+			"Keys match SSH config keywords or lazyssh field names case-insensitively " +
+			"(e.g. HostName, Port, ProxyJump, Tags). An empty value removes the setting; " +
+			"list values such as IdentityFile or Tags are comma-separated.",
+		Example: "  lazyssh edit web-01 --set Port=2222 --set ProxyJump=bastion",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(sets) == 0 {
+				return fmt.Errorf("nothing to change, use --set Key=Value")
+			}
+			server, err := findServer(ss, args[0])
+			if err != nil {
+				return err
+			}
+			if err := ensureWritable(server); err != nil {
+				return err
+			}
+			updated := server
+			if err := applyAssignments(&updated, sets); err != nil {
+				return err
+			}
+			if err := ss.UpdateServer(server, updated); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Updated %s\n", updated.Alias)
+			return nil
+		},
+	}
+	cmd.Flags().StringArrayVar(&sets, "set", nil, "setting to change as Key=Value (repeatable)")
+	return cmd
+}
+
+func newRemoveCommand(ss ports.ServerService) *cobra.Command {
+	return &cobra.Command{
+		Use:     "rm <alias>",
+		Aliases: []string{"remove", "delete"},
+		Short:   "Delete a server from the SSH config",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			server, err := findServer(ss, args[0])
+			if err != nil {
+				return err
+			}
+			if err := ensureWritable(server); err != nil {
+				return err
+			}
+			if err := ss.DeleteServer(server); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Deleted %s\n", server.Alias)
+			return nil
+		},
+	}
+}
+
+// findServer looks up a server by its primary alias or any of its aliases.
+func findServer(ss ports.ServerService, alias string) (domain.Server, error) {
+	servers, err := ss.ListServers("")
+	if err != nil {
+		return domain.Server{}, err
+	}
+	for _, s := range servers {
+		if s.Alias == alias {
+			return s, nil
+		}
+	}
+	for _, s := range servers {
+		for _, a := range s.Aliases {
+			if a == alias {
+				return s, nil
+			}
+		}
+	}
+	return domain.Server{}, fmt.Errorf("server with alias '%s' not found", alias)
+}
+
+// ensureWritable mirrors the TUI rule that servers from read-only files cannot be changed.
+func ensureWritable(server domain.Server) error {
+	if server.Readonly {
+		return fmt.Errorf("read-only: %s is defined in %s", server.Alias, server.SourceFile)
+	}
+	return nil
+}
+
+func applyAssignments(server *domain.Server, assignments []string) error {
+	for _, a := range assignments {
+		key, value, err := parseAssignment(a)
+		if err != nil {
+			return err
+		}
+		if err := setServerField(server, key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeServerTable(w io.Writer, servers []domain.Server) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "ALIAS\tHOST\tUSER\tPORT\tTAGS")
+	for _, s := range servers {
+		port := ""
+		if s.Port != 0 {
+			port = fmt.Sprintf("%d", s.Port)
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", s.Alias, s.Host, s.User, port, strings.Join(s.Tags, ","))
+	}
+	return tw.Flush()
+}
+
+func writeServerDetails(w io.Writer, server domain.Server) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, f := range serverFields(server) {
+		if f.Value == "" {
+			continue
+		}
+		_, _ = fmt.Fprintf(tw, "%s:\t%s\n", f.Name, f.Value)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,155 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/core/ports"
+	"github.com/spf13/cobra"
+)
+
+func newExecCommand(ss ports.ServerService) *cobra.Command {
+	var (
+		tags        []string
+		concurrency int
+		timeout     time.Duration
+		quiet       bool
+	)
+	cmd := &cobra.Command{
+		Use:   "exec [alias...] [--tag tag] -- <command>",
+		Short: "Run a command on several servers in parallel",
+		Long: "Run a command with `ssh <alias> <command>` on the given servers and on every server " +
+			"with one of the tags, a few at a time. Output lines are prefixed with the alias; a " +
+			"summary of exit codes follows. ssh runs in batch mode, so servers that would prompt " +
+			"for a password or passphrase fail instead. The exit status is non-zero when any server fails.",
+		Example: "  lazyssh exec --tag prod -- uptime\n  lazyssh exec web-01 web-02 -j 2 --timeout 10s -- systemctl status nginx",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			aliases, command, err := splitExecArgs(args, cmd.ArgsLenAtDash())
+			if err != nil {
+				return err
+			}
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+
+			req := domain.ExecRequest{
+				Command:     command,
+				Aliases:     aliases,
+				Tags:        tags,
+				Concurrency: concurrency,
+				Timeout:     timeout,
+			}
+			out := newExecPrinter(cmd.OutOrStdout(), cmd.ErrOrStderr())
+			onEvent := out.print
+			if quiet {
+				onEvent = nil
+			}
+			results, err := ss.Exec(ctx, req, onEvent)
+			if err != nil {
+				return err
+			}
+			if !quiet {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout())
+			}
+			if err := writeExecSummary(cmd.OutOrStdout(), results); err != nil {
+				return err
+			}
+			if failed := countFailed(results); failed > 0 {
+				return fmt.Errorf("%d of %d servers failed", failed, len(results))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringSliceVar(&tags, "tag", nil, "run on servers with this tag (repeatable)")
+	cmd.Flags().IntVarP(&concurrency, "concurrency", "j", 10, "servers running the command at the same time")
+	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "time limit per server (0 for none)")
+	cmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "only print the summary")
+	return cmd
+}
+
+// splitExecArgs separates the aliases from the command after "--".
+func splitExecArgs(args []string, dash int) ([]string, string, error) {
+	if dash < 0 || dash == len(args) {
+		return nil, "", fmt.Errorf("give the command after --, e.g. lazyssh exec --tag prod -- uptime")
+	}
+	return args[:dash], strings.Join(args[dash:], " "), nil
+}
+
+// execPrinter writes output lines as they arrive, prefixed with their server.
+type execPrinter struct {
+	out, errOut io.Writer
+}
+
+func newExecPrinter(out, errOut io.Writer) *execPrinter {
+	return &execPrinter{out: out, errOut: errOut}
+}
+
+func (p *execPrinter) print(e domain.ExecEvent) {
+	switch e.Kind {
+	case domain.ExecOutput:
+		w := p.out
+		if e.Stderr {
+			w = p.errOut
+		}
+		_, _ = fmt.Fprintf(w, "%s | %s\n", e.Alias, e.Line)
+	case domain.ExecFinished:
+		if !e.Result.State.Done() || e.Result.State == domain.ExecSucceeded {
+			return
+		}
+		_, _ = fmt.Fprintf(p.errOut, "%s | [%s]\n", e.Alias, describeExecResult(e.Result))
+	case domain.ExecQueued, domain.ExecStarted:
+	}
+}
+
+func writeExecSummary(w io.Writer, results []domain.ExecResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "SERVER\tSTATUS\tEXIT\tTIME\tERROR")
+	for _, r := range results {
+		exit := ""
+		if r.ExitCode >= 0 {
+			exit = fmt.Sprint(r.ExitCode)
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.Alias, r.State, exit, r.Duration.Round(time.Millisecond), r.Error)
+	}
+	return tw.Flush()
+}
+
+func describeExecResult(r domain.ExecResult) string {
+	switch r.State {
+	case domain.ExecFailed:
+		return fmt.Sprintf("exit %d", r.ExitCode)
+	case domain.ExecError, domain.ExecTimedOut:
+		return r.State.String() + ": " + r.Error
+	default:
+		return r.State.String()
+	}
+}
+
+func countFailed(results []domain.ExecResult) int {
+	n := 0
+	for _, r := range results {
+		if r.State != domain.ExecSucceeded {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,70 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestSplitExecArgs(t *testing.T) {
+	aliases, command, err := splitExecArgs([]string{"web", "db", "systemctl", "status", "nginx"}, 2)
+	if err != nil || strings.Join(aliases, ",") != "web,db" || command != "systemctl status nginx" {
+		t.Errorf("splitExecArgs() = %v, %q, %v", aliases, command, err)
+	}
+	for _, dash := range []int{-1, 2} {
+		if _, _, err := splitExecArgs([]string{"web", "db"}, dash); err == nil {
+			t.Errorf("dash %d: expected an error without a command", dash)
+		}
+	}
+}
+
+func TestExecOutput(t *testing.T) {
+	var out, errOut bytes.Buffer
+	p := newExecPrinter(&out, &errOut)
+	p.print(domain.ExecEvent{Kind: domain.ExecStarted, Alias: "web"})
+	p.print(domain.ExecEvent{Kind: domain.ExecOutput, Alias: "web", Line: "up 3 days"})
+	p.print(domain.ExecEvent{Kind: domain.ExecOutput, Alias: "db", Line: "unit not found", Stderr: true})
+	p.print(domain.ExecEvent{Kind: domain.ExecFinished, Alias: "db", Result: domain.ExecResult{State: domain.ExecFailed, ExitCode: 3}})
+	if out.String() != "web | up 3 days\n" {
+		t.Errorf("stdout = %q", out.String())
+	}
+	if errOut.String() != "db | unit not found\ndb | [exit 3]\n" {
+		t.Errorf("stderr = %q", errOut.String())
+	}
+
+	results := []domain.ExecResult{
+		{Alias: "web", State: domain.ExecSucceeded, Duration: 120 * time.Millisecond},
+		{Alias: "db", State: domain.ExecFailed, ExitCode: 3},
+		{Alias: "cache", State: domain.ExecError, ExitCode: 255, Error: "Connection refused"},
+		{Alias: "slow", State: domain.ExecTimedOut, ExitCode: -1, Error: "no result after 30s"},
+	}
+	var summary bytes.Buffer
+	if err := writeExecSummary(&summary, results); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(summary.String()), "\n")
+	if len(lines) != 5 || !strings.HasPrefix(lines[0], "SERVER") || !strings.Contains(lines[3], "255") ||
+		strings.Contains(lines[4], "-1") {
+		t.Errorf("summary =\n%s", summary.String())
+	}
+	if n := countFailed(results); n != 3 {
+		t.Errorf("countFailed() = %d, want 3", n)
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats accepted by --output.
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+	outputTSV   = "tsv"
+)
+
+var outputFormats = []string{outputTable, outputJSON, outputYAML, outputTSV}
+
+// serverRecord is an ordered view of every domain.Server field, including lazyssh
+// metadata, used for machine-readable output. Keys keep the domain field names.
+type serverRecord []recordField
+
+type recordField struct {
+	Name  string
+	Value any
+}
+
+// newServerRecord converts a server into a serverRecord. Zero timestamps become nil
+// and nil slices become empty lists so consumers always see every key.
+func newServerRecord(server domain.Server) serverRecord {
+	v := reflect.ValueOf(server)
+	t := v.Type()
+	rec := make(serverRecord, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if internalFields[t.Field(i).Name] {
+			continue
+		}
+		var value any
+		switch val := v.Field(i).Interface().(type) {
+		case time.Time:
+			if !val.IsZero() {
+				value = val.Format(time.RFC3339)
+			}
+		case []string:
+			if val == nil {
+				val = []string{}
+			}
+			value = val
+		default:
+			value = val
+		}
+		rec = append(rec, recordField{Name: t.Field(i).Name, Value: value})
+	}
+	return rec
+}
+
+// MarshalJSON writes the record as a JSON object preserving field order.
+func (r serverRecord) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range r {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(f.Name)
+		if err != nil {
+			return nil, err
+		}
+		val, err := json.Marshal(f.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// MarshalYAML writes the record as a YAML mapping preserving field order.
+func (r serverRecord) MarshalYAML() (any, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, f := range r {
+		var val yaml.Node
+		if err := val.Encode(f.Value); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: f.Name}, &val)
+	}
+	return node, nil
+}
+
+// addOutputFlag registers the --output/-o flag on cmd.
+func addOutputFlag(cmd *cobra.Command, target *string, def string) {
+	cmd.Flags().StringVarP(target, "output", "o", def,
+		"output format: "+strings.Join(outputFormats, ", "))
+}
+
+// writeServers renders servers in the requested format.
+func writeServers(w io.Writer, servers []domain.Server, format string) error {
+	switch strings.ToLower(format) {
+	case outputTable, "":
+		return writeServerTable(w, servers)
+	case outputJSON:
+		records := make([]serverRecord, 0, len(servers))
+		for _, s := range servers {
+			records = append(records, newServerRecord(s))
+		}
+		return writeJSON(w, records)
+	case outputYAML:
+		records := make([]serverRecord, 0, len(servers))
+		for _, s := range servers {
+			records = append(records, newServerRecord(s))
+		}
+		return writeYAML(w, records)
+	case outputTSV:
+		return writeServersTSV(w, servers)
+	default:
+		return fmt.Errorf("unsupported output format %q (supported: %s)", format, strings.Join(outputFormats, ", "))
+	}
+}
+
+// writeServer renders a single server in the requested format.
+func writeServer(w io.Writer, server domain.Server, format string) error {
+	switch strings.ToLower(format) {
+	case outputTable, "":
+		return writeServerDetails(w, server)
+	case outputJSON:
+		return writeJSON(w, newServerRecord(server))
+	case outputYAML:
+		return writeYAML(w, newServerRecord(server))
+	case outputTSV:
+		return writeServersTSV(w, []domain.Server{server})
+	default:
+		return fmt.Errorf("unsupported output format %q (supported: %s)", format, strings.Join(outputFormats, ", "))
+	}
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func writeYAML(w io.Writer, v any) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// writeServersTSV writes a header row followed by one row per server.
+// List values are comma-separated; tabs and newlines inside values are replaced by spaces.
+func writeServersTSV(w io.Writer, servers []domain.Server) error {
+	header := newServerRecord(domain.Server{})
+	names := make([]string, 0, len(header))
+	for _, f := range header {
+		names = append(names, f.Name)
+	}
+	if _, err := fmt.Fprintln(w, strings.Join(names, "\t")); err != nil {
+		return err
+	}
+
+	for _, s := range servers {
+		rec := newServerRecord(s)
+		values := make([]string, 0, len(rec))
+		for _, f := range rec {
+			values = append(values, tsvCleaner.Replace(tsvValue(f.Value)))
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(values, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tsvCleaner replaces the tabs and newlines inside a TSV value by spaces, so
+// they do not break the row format.
+var tsvCleaner = strings.NewReplacer("\t", " ", "\r", " ", "\n", " ")
+
+func tsvValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case []string:
+		return strings.Join(val, ",")
+	case int:
+		if val == 0 {
+			return ""
+		}
+		return fmt.Sprint(val)
+	default:
+		return fmt.Sprint(val)
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func testServers() []domain.Server {
+	return []domain.Server{
+		{
+			Alias:         "web",
+			Host:          "10.0.0.1",
+			Port:          22,
+			IdentityFiles: []string{"~/.ssh/id_ed25519"},
+			Tags:          []string{"prod", "eu"},
+			LastSeen:      time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+			SSHCount:      4,
+			SourceFile:    "/home/u/.ssh/config",
+			ProxyCommand:  "nc -X\t5 %h %p",
+			Health:        domain.Health{Samples: []domain.HealthSample{{At: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)}}},
+		},
+	}
+}
+
+func TestWriteServersJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeServers(&buf, testServers(), outputJSON); err != nil {
+		t.Fatalf("writeServers() error: %v", err)
+	}
+	var decoded []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(decoded) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(decoded))
+	}
+	rec := decoded[0]
+	if rec["Alias"] != "web" || rec["LastSeen"] != "2025-01-02T03:04:05Z" || rec["PinnedAt"] != nil {
+		t.Errorf("unexpected record: %v", rec)
+	}
+	if rec["SSHCount"] != float64(4) || rec["Readonly"] != false {
+		t.Errorf("metadata not exported: %v", rec)
+	}
+	if _, ok := rec["Health"]; ok {
+		t.Errorf("Health is internal state and should not be exported: %v", rec["Health"])
+	}
+	if tags, ok := rec["Tags"].([]any); !ok || len(tags) != 2 {
+		t.Errorf("Tags = %v, want two entries", rec["Tags"])
+	}
+	if !strings.HasPrefix(strings.TrimSpace(buf.String()), "[\n  {\n    \"Alias\"") {
+		t.Errorf("expected field order to start with Alias:\n%s", buf.String())
+	}
+}
+
+func TestWriteServersTSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeServers(&buf, testServers(), outputTSV); err != nil {
+		t.Fatalf("writeServers() error: %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected header and one row, got %d lines", len(lines))
+	}
+	header := strings.Split(lines[0], "\t")
+	row := strings.Split(lines[1], "\t")
+	if len(header) != len(row) {
+		t.Fatalf("header has %d columns, row has %d", len(header), len(row))
+	}
+	values := make(map[string]string, len(header))
+	for i, name := range header {
+		values[name] = row[i]
+	}
+	if _, ok := values["Health"]; ok {
+		t.Error("TSV has a Health column")
+	}
+	if values["Tags"] != "prod,eu" {
+		t.Errorf("Tags = %q, want %q", values["Tags"], "prod,eu")
+	}
+	if values["ProxyCommand"] != "nc -X 5 %h %p" {
+		t.Errorf("ProxyCommand = %q, tabs should be replaced", values["ProxyCommand"])
+	}
+}
+
+func TestWriteServersUnknownFormat(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeServers(&buf, testServers(), "xml"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
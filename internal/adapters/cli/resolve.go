@@ -0,0 +1,141 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/core/ports"
+	"github.com/spf13/cobra"
+)
+
+// optionRecord is the machine-readable form of a domain.EffectiveOption.
+type optionRecord struct {
+	Option  string `json:"option" yaml:"option"`
+	Value   string `json:"value" yaml:"value"`
+	Source  string `json:"source" yaml:"source"`
+	Literal string `json:"literal,omitempty" yaml:"literal,omitempty"`
+	From    string `json:"from,omitempty" yaml:"from,omitempty"`
+}
+
+func newResolveCommand(ss ports.ServerService) *cobra.Command {
+	var (
+		output string
+		all    bool
+	)
+	cmd := &cobra.Command{
+		Use:   "resolve <alias>",
+		Short: "Show the effective SSH configuration of a server (ssh -G)",
+		Long: "Run `ssh -G` for the alias and compare each resolved option with the literal " +
+			"values of its Host block. SOURCE tells whether a value is set by the Host block, " +
+			"inherited from another block (wildcard Host, Match, Include), overridden by a block " +
+			"read earlier, or an ssh default. Defaults are hidden unless --all is given.",
+		Example: "  lazyssh resolve web-01\n  lazyssh resolve web-01 --all -o json",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			server, err := findServer(ss, args[0])
+			if err != nil {
+				// ssh -G resolves any destination, even one without a Host block.
+				server = domain.Server{Alias: args[0]}
+			}
+			effective, err := ss.ResolveServer(server)
+			if err != nil {
+				return err
+			}
+			options := effective.Options
+			if !all {
+				options = withoutDefaults(options)
+			}
+			return writeOptions(cmd.OutOrStdout(), options, output)
+		},
+	}
+	addOutputFlag(cmd, &output, outputTable)
+	cmd.Flags().BoolVar(&all, "all", false, "include options left at their ssh defaults")
+	return cmd
+}
+
+func withoutDefaults(options []domain.EffectiveOption) []domain.EffectiveOption {
+	out := make([]domain.EffectiveOption, 0, len(options))
+	for _, o := range options {
+		if o.Source != domain.SourceDefault {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// writeOptions renders resolved options in the requested format.
+func writeOptions(w io.Writer, options []domain.EffectiveOption, format string) error {
+	records := make([]optionRecord, 0, len(options))
+	for _, o := range options {
+		records = append(records, optionRecord{
+			Option:  o.Key,
+			Value:   o.Value,
+			Source:  o.Source.String(),
+			Literal: o.Literal,
+			From:    o.From,
+		})
+	}
+
+	switch strings.ToLower(format) {
+	case outputTable, "":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "OPTION\tVALUE\tSOURCE")
+		for _, r := range records {
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", r.Option, r.Value, describeSource(r))
+		}
+		return tw.Flush()
+	case outputJSON:
+		return writeJSON(w, records)
+	case outputYAML:
+		return writeYAML(w, records)
+	case outputTSV:
+		if _, err := fmt.Fprintln(w, "option\tvalue\tsource\tliteral\tfrom"); err != nil {
+			return err
+		}
+		for _, r := range records {
+			row := []string{r.Option, r.Value, r.Source, r.Literal, r.From}
+			for i, v := range row {
+				row[i] = tsvCleaner.Replace(v)
+			}
+			if _, err := fmt.Fprintln(w, strings.Join(row, "\t")); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format %q (supported: %s)", format, strings.Join(outputFormats, ", "))
+	}
+}
+
+func describeSource(r optionRecord) string {
+	switch r.Source {
+	case domain.SourceOverridden.String():
+		return fmt.Sprintf("overridden by %s (Host block says %q)", r.From, r.Literal)
+	case domain.SourceInherited.String():
+		return "inherited from " + r.From
+	case domain.SourceHost.String():
+		if !strings.EqualFold(r.Literal, r.Value) {
+			return fmt.Sprintf("host (written as %q)", r.Literal)
+		}
+		return "host"
+	default:
+		return r.Source
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestWriteOptionsTSV(t *testing.T) {
+	options := []domain.EffectiveOption{
+		{Key: "proxycommand", Value: "nc\t%h %p", Literal: "nc\t%h %p", Source: domain.SourceHost},
+		{Key: "remotecommand", Value: "cd /srv &&\nexec $SHELL", Source: domain.SourceInherited, From: "Host *.prod (config)"},
+	}
+	var buf bytes.Buffer
+	if err := writeOptions(&buf, options, outputTSV); err != nil {
+		t.Fatalf("writeOptions() error: %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header and two rows, got %d lines: %q", len(lines), lines)
+	}
+	for _, line := range lines {
+		if n := len(strings.Split(line, "\t")); n != 5 {
+			t.Errorf("row %q has %d columns, want 5", line, n)
+		}
+	}
+	if want := "proxycommand\tnc %h %p\thost\tnc %h %p\t"; lines[1] != want {
+		t.Errorf("row = %q, want %q", lines[1], want)
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+// keyAliases maps ssh_config keywords that differ from the domain.Server field names.
+var keyAliases = map[string]string{
+	"hostname":     "Host",
+	"identityfile": "IdentityFiles",
+	"tag":          "Tags",
+}
+
+// readonlyFields are managed by lazyssh itself and cannot be changed with --set.
+var readonlyFields = map[string]bool{
+	"Aliases":    true,
+	"LastSeen":   true,
+	"PinnedAt":   true,
+	"SSHCount":   true,
+	"SourceFile": true,
+	"Readonly":   true,
+}
+
+// internalFields hold runtime state, such as the health monitor's probe history
+// or the revision of the file a server was listed from; they are neither
+// printed nor settable.
+var internalFields = map[string]bool{
+	"Health":   true,
+	"Revision": true,
+}
+
+// serverField is a single named value of a domain.Server, rendered as text.
+type serverField struct {
+	Name  string
+	Value string
+}
+
+// resolveFieldName returns the domain.Server field name for a user supplied key.
+// Keys are matched case-insensitively against field names and ssh_config keywords.
+func resolveFieldName(key string) (string, bool) {
+	k := strings.ToLower(strings.TrimSpace(key))
+	if name, ok := keyAliases[k]; ok {
+		return name, true
+	}
+	t := reflect.TypeOf(domain.Server{})
+	for i := 0; i < t.NumField(); i++ {
+		if internalFields[t.Field(i).Name] {
+			continue
+		}
+		if strings.ToLower(t.Field(i).Name) == k {
+			return t.Field(i).Name, true
+		}
+	}
+	return "", false
+}
+
+// parseAssignment splits a Key=Value argument.
+func parseAssignment(arg string) (string, string, error) {
+	key, value, ok := strings.Cut(arg, "=")
+	if !ok || strings.TrimSpace(key) == "" {
+		return "", "", fmt.Errorf("invalid assignment %q, expected Key=Value", arg)
+	}
+	return strings.TrimSpace(key), strings.TrimSpace(value), nil
+}
+
+// setServerField assigns value to the field identified by key.
+// An empty value clears the field; list fields take a comma-separated value.
+func setServerField(server *domain.Server, key, value string) error {
+	name, ok := resolveFieldName(key)
+	if !ok {
+		return fmt.Errorf("unknown field %q", key)
+	}
+	if readonlyFields[name] {
+		return fmt.Errorf("field %q is managed by lazyssh and cannot be set", name)
+	}
+
+	field := reflect.ValueOf(server).Elem().FieldByName(name)
+	switch field.Kind() { //nolint:exhaustive // domain.Server only uses these kinds for settable fields
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int:
+		if value == "" {
+			field.SetInt(0)
+			return nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("field %q expects a number: %w", name, err)
+		}
+		field.SetInt(int64(n))
+	case reflect.Slice:
+		field.Set(reflect.ValueOf(splitList(value)))
+	default:
+		return fmt.Errorf("field %q cannot be set from the command line", name)
+	}
+	return nil
+}
+
+// splitList splits a comma-separated value, dropping empty items.
+func splitList(value string) []string {
+	var out []string
+	for _, part := range strings.Split(value, ",") {
+		if s := strings.TrimSpace(part); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// serverFields returns every field of the server in declaration order, except
+// the internal ones.
+func serverFields(server domain.Server) []serverField {
+	v := reflect.ValueOf(server)
+	t := v.Type()
+	fields := make([]serverField, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if internalFields[t.Field(i).Name] {
+			continue
+		}
+		fields = append(fields, serverField{Name: t.Field(i).Name, Value: formatFieldValue(v.Field(i))})
+	}
+	return fields
+}
+
+func formatFieldValue(v reflect.Value) string {
+	switch val := v.Interface().(type) {
+	case string:
+		return val
+	case int:
+		if val == 0 {
+			return ""
+		}
+		return strconv.Itoa(val)
+	case bool:
+		return strconv.FormatBool(val)
+	case []string:
+		return strings.Join(val, ", ")
+	case time.Time:
+		if val.IsZero() {
+			return ""
+		}
+		return val.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(val)
+	}
+}
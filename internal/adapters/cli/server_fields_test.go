@@ -0,0 +1,107 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestSetServerField(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		check   func(domain.Server) bool
+		wantErr bool
+	}{
+		{
+			name:  "ssh keyword maps to field",
+			key:   "HostName",
+			value: "10.0.0.1",
+			check: func(s domain.Server) bool { return s.Host == "10.0.0.1" },
+		},
+		{
+			name:  "case insensitive field name",
+			key:   "proxyjump",
+			value: "bastion",
+			check: func(s domain.Server) bool { return s.ProxyJump == "bastion" },
+		},
+		{
+			name:  "numeric field",
+			key:   "Port",
+			value: "2222",
+			check: func(s domain.Server) bool { return s.Port == 2222 },
+		},
+		{
+			name:  "list field",
+			key:   "IdentityFile",
+			value: "~/.ssh/a, ~/.ssh/b",
+			check: func(s domain.Server) bool {
+				return reflect.DeepEqual(s.IdentityFiles, []string{"~/.ssh/a", "~/.ssh/b"})
+			},
+		},
+		{
+			name:  "empty value clears",
+			key:   "User",
+			value: "",
+			check: func(s domain.Server) bool { return s.User == "" },
+		},
+		{name: "invalid number", key: "Port", value: "abc", wantErr: true},
+		{name: "unknown key", key: "NoSuchKey", value: "x", wantErr: true},
+		{name: "metadata field", key: "SSHCount", value: "3", wantErr: true},
+		{name: "internal field", key: "Health", value: "up", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := domain.Server{Alias: "web", Host: "example.com", User: "root", Port: 22}
+			err := setServerField(&server, tt.key, tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("setServerField(%q, %q) expected error", tt.key, tt.value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("setServerField(%q, %q) unexpected error: %v", tt.key, tt.value, err)
+			}
+			if !tt.check(server) {
+				t.Errorf("setServerField(%q, %q) produced %+v", tt.key, tt.value, server)
+			}
+		})
+	}
+}
+
+func TestServerFieldsSkipInternal(t *testing.T) {
+	server := domain.Server{Alias: "web", Health: domain.Health{Samples: []domain.HealthSample{{Up: true}}}}
+	for _, f := range serverFields(server) {
+		if internalFields[f.Name] {
+			t.Errorf("serverFields() returned internal field %s = %q", f.Name, f.Value)
+		}
+	}
+}
+
+func TestParseAssignment(t *testing.T) {
+	key, value, err := parseAssignment("ProxyJump=user@bastion:22")
+	if err != nil || key != "ProxyJump" || value != "user@bastion:22" {
+		t.Errorf("parseAssignment() = %q, %q, %v", key, value, err)
+	}
+	if _, _, err := parseAssignment("Port"); err == nil {
+		t.Error("parseAssignment(\"Port\") expected error")
+	}
+}
@@ -15,49 +15,126 @@
 package ssh_config_file
 
 import (
+	"bytes"
+	"compress/gzip"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/kevinburke/ssh_config"
 )
 
-// createBackup creates a timestamped backup of the current config file
-func (r *Repository) createBackup() error {
-	if _, err := r.fileSystem.Stat(r.configPath); os.IsNotExist(err) {
+// backupLocation returns the directory and base name used for the backups of the
+// config file at path. Without a backup directory, backups of included files are
+// kept next to the main config, never in the include directory, where an
+// "Include dir/*" would pick them up.
+func (r *Repository) backupLocation(path string) (dir, name string) {
+	if r.backupDir != "" {
+		return r.backupDir, backupNameInDir(path)
+	}
+	mainDir := filepath.Dir(r.configPath)
+	if path == r.configPath {
+		return mainDir, filepath.Base(path)
+	}
+	absMain, err := filepath.Abs(expandTilde(mainDir))
+	if err != nil {
+		absMain = mainDir
+	}
+	rel, err := filepath.Rel(absMain, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		rel = filepath.Base(path)
+	}
+	return mainDir, strings.ReplaceAll(filepath.ToSlash(rel), "/", "_")
+}
+
+// backupNameInDir names the backups of path in a backup directory shared by all
+// config files (and workspaces): the path relative to the home directory, or the
+// absolute path, with separators replaced, e.g. ".ssh_config" for ~/.ssh/config.
+func backupNameInDir(path string) string {
+	name, err := filepath.Abs(expandTilde(path))
+	if err != nil {
+		name = path
+	}
+	if home, err := os.UserHomeDir(); err == nil && home != "" {
+		if rel, err := filepath.Rel(home, name); err == nil && !strings.HasPrefix(rel, "..") {
+			name = rel
+		}
+	}
+	name = strings.TrimLeft(filepath.ToSlash(name), "/")
+	return strings.NewReplacer("/", "_", ":", "").Replace(name)
+}
+
+// ensureBackupDir creates the backup directory, if one is configured.
+func (r *Repository) ensureBackupDir() error {
+	if r.backupDir == "" {
+		return nil
+	}
+	if err := r.fileSystem.MkdirAll(r.backupDir, 0o700); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	return nil
+}
+
+// createBackup creates a timestamped backup of the config file at path
+func (r *Repository) createBackup(path string) error {
+	if r.maxBackups <= 0 {
+		return nil
+	}
+	if _, err := r.fileSystem.Stat(path); os.IsNotExist(err) {
 		return nil
 	} else if err != nil {
 		return fmt.Errorf("failed to check if config file exists: %w", err)
 	}
+	if err := r.ensureBackupDir(); err != nil {
+		return err
+	}
 
+	configDir, name := r.backupLocation(path)
 	timestamp := time.Now().UnixMilli()
-	backupPath := fmt.Sprintf("%s-%d-%s", r.configPath, timestamp, BackupSuffix)
+	backupPath := filepath.Join(configDir, fmt.Sprintf("%s-%d-%s", name, timestamp, BackupSuffix))
 
-	if err := r.copyFile(r.configPath, backupPath); err != nil {
+	copyBackup := r.copyFile
+	if r.compressBackups {
+		backupPath += GzipSuffix
+		copyBackup = r.gzipFile
+	}
+	if err := copyBackup(path, backupPath); err != nil {
 		return fmt.Errorf("failed to copy config to backup: %w", err)
 	}
 
 	r.logger.Infof("Created backup: %s", backupPath)
+	return r.pruneBackups(configDir, name)
+}
 
-	configDir := filepath.Dir(r.configPath)
-
-	backupFiles, err := r.findBackupFiles(configDir)
+// pruneBackups removes the rolling backups of name beyond the retention limits:
+// count, age and total size, checked from the newest backup, which is always kept.
+func (r *Repository) pruneBackups(dir, name string) error {
+	backupFiles, err := r.findBackupFiles(dir, name)
 	if err != nil {
 		return err
 	}
 
-	if len(backupFiles) <= MaxBackups {
-		return nil
-	}
-
 	sort.Slice(backupFiles, func(i, j int) bool {
-		return backupFiles[i].ModTime().After(backupFiles[j].ModTime())
+		return backupTime(backupFiles[i]).After(backupTime(backupFiles[j]))
 	})
 
-	for i := MaxBackups; i < len(backupFiles); i++ {
-		backupPath := filepath.Join(configDir, backupFiles[i].Name())
+	var total int64
+	now := time.Now()
+	for i, info := range backupFiles {
+		total += info.Size()
+		keep := i == 0 || (i < r.maxBackups &&
+			(r.maxBackupAge <= 0 || now.Sub(backupTime(info)) <= r.maxBackupAge) &&
+			(r.maxBackupSize <= 0 || total <= r.maxBackupSize))
+		if keep {
+			continue
+		}
+		backupPath := filepath.Join(dir, info.Name())
 		if err := r.fileSystem.Remove(backupPath); err != nil {
 			r.logger.Warnf("failed to remove old backup %s: %v", backupPath, err)
 			continue
@@ -67,6 +144,39 @@ func (r *Repository) createBackup() error {
 	return nil
 }
 
+// gzipFile writes a gzip-compressed copy of src to dst.
+func (r *Repository) gzipFile(src, dst string) error {
+	srcFile, err := r.fileSystem.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := srcFile.Close(); cerr != nil {
+			r.logger.Warnf("failed to close source file %s: %v", src, cerr)
+		}
+	}()
+
+	destFile, err := r.fileSystem.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, SSHConfigPerms)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := destFile.Close(); cerr != nil {
+			r.logger.Warnf("failed to close destination file %s: %v", dst, cerr)
+		}
+	}()
+
+	zw := gzip.NewWriter(destFile)
+	zw.Name = filepath.Base(src)
+	if _, err := io.Copy(zw, srcFile); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return destFile.Sync()
+}
+
 // copyFile copies a file from src to dst
 func (r *Repository) copyFile(src, dst string) error {
 	srcFile, err := r.fileSystem.Open(src)
@@ -102,8 +212,8 @@ func (r *Repository) copyFile(src, dst string) error {
 	return destFile.Sync()
 }
 
-// findBackupFiles finds all backup files for the given config file
-func (r *Repository) findBackupFiles(dir string) ([]os.FileInfo, error) {
+// findBackupFiles finds all backup files in dir for the config file with the given backup name
+func (r *Repository) findBackupFiles(dir, name string) ([]os.FileInfo, error) {
 	entries, err := r.fileSystem.ReadDir(dir)
 	if err != nil {
 		return nil, err
@@ -112,11 +222,10 @@ func (r *Repository) findBackupFiles(dir string) ([]os.FileInfo, error) {
 	var backupFiles []os.FileInfo
 
 	for _, entry := range entries {
-		name := entry.Name()
-		if strings.HasSuffix(name, BackupSuffix) {
+		if isBackupOf(entry.Name(), name) {
 			info, err := entry.Info()
 			if err != nil {
-				r.logger.Warnf("failed to get info for backup file %s: %v", name, err)
+				r.logger.Warnf("failed to get info for backup file %s: %v", entry.Name(), err)
 				continue
 			}
 			backupFiles = append(backupFiles, info)
@@ -126,28 +235,161 @@ func (r *Repository) findBackupFiles(dir string) ([]os.FileInfo, error) {
 	return backupFiles, nil
 }
 
-// createOriginalBackupIfNeeded creates a one-time original backup of the current SSH config.
-func (r *Repository) createOriginalBackupIfNeeded() error {
+// isBackupOf reports whether fileName is a timestamped backup ("<name>-<unixms>-lazyssh.backup",
+// followed by ".gz" when compressed) of the config file with the given backup name.
+func isBackupOf(fileName, name string) bool {
+	rest, ok := strings.CutSuffix(strings.TrimSuffix(fileName, GzipSuffix), "-"+BackupSuffix)
+	if !ok {
+		return false
+	}
+	rest, ok = strings.CutPrefix(rest, name+"-")
+	if !ok || rest == "" {
+		return false
+	}
+	for _, c := range rest {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// createOriginalBackupIfNeeded creates a one-time original backup of the SSH config file at path.
+func (r *Repository) createOriginalBackupIfNeeded(path string) error {
 	// If no SSH config file, nothing to do.
-	if _, err := r.fileSystem.Stat(r.configPath); os.IsNotExist(err) {
+	if _, err := r.fileSystem.Stat(path); os.IsNotExist(err) {
 		return nil
 	} else if err != nil {
 		return fmt.Errorf("failed to check if config file exists: %w", err)
 	}
+	if err := r.ensureBackupDir(); err != nil {
+		return err
+	}
 
-	configDir := filepath.Dir(r.configPath)
-	originalBackupPath := filepath.Join(configDir, OriginalBackupName)
-
+	originalBackupPath := r.originalBackupPath(path)
 	if _, err := r.fileSystem.Stat(originalBackupPath); err == nil {
 		return nil
 	} else if !r.fileSystem.IsNotExist(err) {
 		return fmt.Errorf("failed to check if original backup exists: %w", err)
 	}
 
-	if err := r.copyFile(r.configPath, originalBackupPath); err != nil {
+	if err := r.copyFile(path, originalBackupPath); err != nil {
 		return fmt.Errorf("failed to create original backup: %w", err)
 	}
 
 	r.logger.Infof("Created original backup: %s", originalBackupPath)
 	return nil
 }
+
+// originalBackupPath returns where the one-time original backup of the config file at path is kept.
+func (r *Repository) originalBackupPath(path string) string {
+	configDir, name := r.backupLocation(path)
+	if path == r.configPath && r.backupDir == "" {
+		return filepath.Join(configDir, OriginalBackupName)
+	}
+	return filepath.Join(configDir, name+".original.backup")
+}
+
+// ListBackups returns the backups of the main config and its includes, newest
+// first for each file, followed by the file's original backup.
+func (r *Repository) ListBackups() ([]domain.Backup, error) {
+	backups := make([]domain.Backup, 0, r.maxBackups+1)
+	for _, path := range r.configFiles() {
+		dir, name := r.backupLocation(path)
+		files, err := r.findBackupFiles(dir, name)
+		if err != nil && !r.fileSystem.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to list backups of %s: %w", path, err)
+		}
+		forFile := make([]domain.Backup, 0, len(files)+1)
+		for _, info := range files {
+			forFile = append(forFile, domain.Backup{
+				Path:       filepath.Join(dir, info.Name()),
+				ConfigFile: path,
+				CreatedAt:  backupTime(info),
+				Size:       info.Size(),
+			})
+		}
+		sort.SliceStable(forFile, func(i, j int) bool {
+			return forFile[i].CreatedAt.After(forFile[j].CreatedAt)
+		})
+		original := r.originalBackupPath(path)
+		if info, err := r.fileSystem.Stat(original); err == nil {
+			forFile = append(forFile, domain.Backup{
+				Path:       original,
+				ConfigFile: path,
+				Original:   true,
+				CreatedAt:  info.ModTime(),
+				Size:       info.Size(),
+			})
+		}
+		backups = append(backups, forFile...)
+	}
+	return backups, nil
+}
+
+// backupTime returns when a timestamped backup was taken, from its name.
+func backupTime(info os.FileInfo) time.Time {
+	rest := strings.TrimSuffix(strings.TrimSuffix(info.Name(), GzipSuffix), "-"+BackupSuffix)
+	ms, err := strconv.ParseInt(rest[strings.LastIndex(rest, "-")+1:], 10, 64)
+	if err != nil {
+		return info.ModTime()
+	}
+	return time.UnixMilli(ms)
+}
+
+// RestoreBackup replaces a config file with one of its backups. The current
+// content is backed up first, so the restore can itself be reverted.
+func (r *Repository) RestoreBackup(backup domain.Backup) error {
+	path, err := r.targetFile(backup.ConfigFile)
+	if err != nil {
+		return err
+	}
+	dir, name := r.backupLocation(path)
+	isBackup := isBackupOf(filepath.Base(backup.Path), name) || backup.Path == r.originalBackupPath(path)
+	if filepath.Dir(backup.Path) != dir || !isBackup {
+		return fmt.Errorf("%s is not a backup of %s", backup.Path, path)
+	}
+
+	data, err := r.ReadBackup(backup)
+	if err != nil {
+		return err
+	}
+	if _, err := ssh_config.Decode(bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("backup is not a valid SSH config: %w", err)
+	}
+
+	message := fmt.Sprintf("restore %s from %s", filepath.Base(path), backup.Name())
+	if err := r.replaceConfigAt(path, string(data), "", message); err != nil {
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+	r.logger.Infof("Restored %s from %s", path, backup.Path)
+	return nil
+}
+
+// ReadBackup returns the content of a backup, decompressed if needed.
+func (r *Repository) ReadBackup(backup domain.Backup) ([]byte, error) {
+	file, err := r.fileSystem.Open(backup.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer func() {
+		if cerr := file.Close(); cerr != nil {
+			r.logger.Warnf("failed to close backup %s: %v", backup.Path, cerr)
+		}
+	}()
+
+	var src io.Reader = file
+	if strings.HasSuffix(backup.Path, GzipSuffix) {
+		zr, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress backup: %w", err)
+		}
+		defer func() { _ = zr.Close() }()
+		src = zr
+	}
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,253 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"go.uber.org/zap"
+)
+
+func TestBatchTakesOneBackupPerFile(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config")
+	if err := os.WriteFile(configPath, []byte("Host web\n    HostName 10.0.0.1\n\nHost db\n    HostName 10.0.0.2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewRepository(zap.NewNop().Sugar(), configPath, filepath.Join(dir, "metadata.json"))
+	backups := func() int {
+		t.Helper()
+		files, err := filepath.Glob(filepath.Join(dir, "config-*-"+BackupSuffix))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(files)
+	}
+
+	servers, err := repo.ListServers("")
+	if err != nil || len(servers) != 2 {
+		t.Fatalf("ListServers() = %+v, %v", servers, err)
+	}
+	err = repo.Batch(func() error {
+		for _, srv := range servers {
+			updated := srv
+			updated.User = "admin"
+			if err := repo.UpdateServer(srv, updated); err != nil {
+				return err
+			}
+			// Distinct timestamps, so separate backups would not share a name.
+			time.Sleep(5 * time.Millisecond)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Batch() error = %v", err)
+	}
+	if n := backups(); n != 1 {
+		t.Errorf("got %d backups after a batch of two writes, want 1", n)
+	}
+	assertFileContains(t, configPath, "User admin")
+
+	// Outside a batch every write is backed up again.
+	updated := servers[0]
+	updated.Port = 2222
+	if err := repo.UpdateServer(servers[0], updated); err != nil {
+		t.Fatal(err)
+	}
+	if n := backups(); n != 2 {
+		t.Errorf("got %d backups after another write, want 2", n)
+	}
+}
+
+func TestListAndRestoreBackups(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config")
+	initial := "Host web\n    HostName 10.0.0.1\n"
+	if err := os.WriteFile(configPath, []byte(initial), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewRepository(zap.NewNop().Sugar(), configPath, filepath.Join(dir, "metadata.json"))
+
+	for _, user := range []string{"deploy", "admin"} {
+		servers, err := repo.ListServers("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		updated := servers[0]
+		updated.User = user
+		if err := repo.UpdateServer(servers[0], updated); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	backups, err := repo.ListBackups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 3 {
+		t.Fatalf("ListBackups() = %+v, want 2 backups and the original", backups)
+	}
+	if !backups[0].CreatedAt.After(backups[1].CreatedAt) || !backups[2].Original {
+		t.Errorf("ListBackups() order = %+v, want newest first and the original last", backups)
+	}
+	for _, b := range backups {
+		if b.ConfigFile != configPath || b.Size == 0 {
+			t.Errorf("backup %+v: want ConfigFile %s and a size", b, configPath)
+		}
+	}
+
+	// backups[1] holds the config before the first update.
+	if err := repo.RestoreBackup(backups[1]); err != nil {
+		t.Fatalf("RestoreBackup() error = %v", err)
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != initial {
+		t.Errorf("config after restore = %q, want %q", data, initial)
+	}
+	after, err := repo.ListBackups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(after) != 4 {
+		t.Errorf("got %d backups after restore, want the content before it backed up too", len(after))
+	}
+
+	foreign := domain.Backup{Path: filepath.Join(dir, "metadata.json"), ConfigFile: configPath}
+	if err := repo.RestoreBackup(foreign); err == nil {
+		t.Error("RestoreBackup() accepted a file that is not a backup")
+	}
+}
+
+func TestPruneBackups(t *testing.T) {
+	now := time.Now()
+	// Backups from newest to oldest: ages in hours and sizes in bytes.
+	backups := []struct {
+		age  time.Duration
+		size int
+	}{{0, 100}, {1 * time.Hour, 100}, {2 * time.Hour, 100}, {48 * time.Hour, 100}}
+
+	tests := []struct {
+		name    string
+		max     int
+		maxAge  time.Duration
+		maxSize int64
+		want    int
+	}{
+		{name: "count", max: 2, want: 2},
+		{name: "age", max: 10, maxAge: 24 * time.Hour, want: 3},
+		{name: "total size", max: 10, maxSize: 250, want: 2},
+		{name: "newest is kept even when too large", max: 10, maxSize: 50, want: 1},
+		{name: "no limits hit", max: 10, want: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, b := range backups {
+				name := fmt.Sprintf("config-%d-%s", now.Add(-b.age).UnixMilli(), BackupSuffix)
+				if err := os.WriteFile(filepath.Join(dir, name), make([]byte, b.size), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			r := &Repository{
+				fileSystem:    DefaultFileSystem{},
+				logger:        zap.NewNop().Sugar(),
+				maxBackups:    tt.max,
+				maxBackupAge:  tt.maxAge,
+				maxBackupSize: tt.maxSize,
+			}
+			if err := r.pruneBackups(dir, "config"); err != nil {
+				t.Fatal(err)
+			}
+			left, err := r.findBackupFiles(dir, "config")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(left) != tt.want {
+				t.Fatalf("%d backups left, want %d", len(left), tt.want)
+			}
+			newest := fmt.Sprintf("config-%d-%s", now.UnixMilli(), BackupSuffix)
+			if _, err := os.Stat(filepath.Join(dir, newest)); err != nil {
+				t.Errorf("newest backup removed: %v", err)
+			}
+		})
+	}
+}
+
+func TestBackupDirAndCompression(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	configPath := filepath.Join(home, ".ssh", "config")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	initial := "Host web\n    HostName 10.0.0.1\n"
+	if err := os.WriteFile(configPath, []byte(initial), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	backupDir := filepath.Join(home, ".lazyssh", "backups")
+	repo := NewRepository(zap.NewNop().Sugar(), configPath, filepath.Join(home, ".lazyssh", "metadata.json"),
+		WithBackupDir(backupDir), WithBackupCompression(true))
+
+	servers, err := repo.ListServers("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated := servers[0]
+	updated.User = "admin"
+	if err := repo.UpdateServer(servers[0], updated); err != nil {
+		t.Fatal(err)
+	}
+
+	if left, _ := filepath.Glob(filepath.Join(home, ".ssh", "*backup*")); len(left) > 0 {
+		t.Errorf("backups written next to the config: %v", left)
+	}
+	backups, err := repo.ListBackups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("ListBackups() = %+v, want a backup and the original", backups)
+	}
+	rolling, original := backups[0], backups[1]
+	if filepath.Dir(rolling.Path) != backupDir || !strings.HasPrefix(rolling.Name(), ".ssh_config-") ||
+		!strings.HasSuffix(rolling.Name(), BackupSuffix+GzipSuffix) {
+		t.Errorf("rolling backup at %s, want a gzipped .ssh_config backup in %s", rolling.Path, backupDir)
+	}
+	if original.Path != filepath.Join(backupDir, ".ssh_config.original.backup") {
+		t.Errorf("original backup at %s", original.Path)
+	}
+
+	data, err := repo.ReadBackup(rolling)
+	if err != nil || string(data) != initial {
+		t.Fatalf("ReadBackup() = %q, %v, want %q", data, err, initial)
+	}
+	if err := repo.RestoreBackup(rolling); err != nil {
+		t.Fatalf("RestoreBackup() error = %v", err)
+	}
+	if got, _ := os.ReadFile(configPath); string(got) != initial {
+		t.Errorf("config after restore = %q, want %q", got, initial)
+	}
+}
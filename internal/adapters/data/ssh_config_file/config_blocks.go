@@ -0,0 +1,223 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/kevinburke/ssh_config"
+)
+
+// matchKeywordsWithoutArgument lists Match criteria that do not take an argument.
+var matchKeywordsWithoutArgument = map[string]bool{
+	"all":       true,
+	"canonical": true,
+	"final":     true,
+}
+
+// ListConfigBlocks returns every Host and Match block of the main config and its
+// includes in the order ssh evaluates them: the blocks of an included file take the
+// place of its Include line. Hidden files are left out.
+func (r *Repository) ListConfigBlocks() ([]domain.ConfigBlock, error) {
+	files := r.configFiles()
+	visited := map[string]struct{}{files[0]: {}}
+	return r.fileConfigBlocks(files[0], true, visited), nil
+}
+
+// fileConfigBlocks returns the blocks of a config file with the blocks of the
+// files it includes spliced in. visited keeps a file from being listed twice.
+func (r *Repository) fileConfigBlocks(path string, isMain bool, visited map[string]struct{}) []domain.ConfigBlock {
+	directives, err := r.includeDirectives(path)
+	if err != nil {
+		r.logger.Warnf("failed to resolve includes of %s: %v", path, err)
+	}
+	included := func(line int) []domain.ConfigBlock {
+		var blocks []domain.ConfigBlock
+		for _, d := range directives {
+			if d.line != line {
+				continue
+			}
+			for _, child := range d.files {
+				if _, ok := visited[child]; ok {
+					continue
+				}
+				visited[child] = struct{}{}
+				blocks = append(blocks, r.fileConfigBlocks(child, false, visited)...)
+			}
+		}
+		return blocks
+	}
+
+	cfg, err := r.decodeConfigAt(path)
+	if err != nil {
+		r.logger.Warnf("failed to decode %s: %v", path, err)
+		return nil
+	}
+	blocks := r.toConfigBlocks(cfg, path, included)
+	if !r.isHiddenFile(path, isMain) {
+		return blocks
+	}
+	shown := blocks[:0]
+	for _, b := range blocks {
+		if b.SourceFile != path {
+			shown = append(shown, b)
+		}
+	}
+	return shown
+}
+
+// toConfigBlocks converts a parsed config into Host and Match blocks.
+// Settings placed before the first Host line are reported as an implicit "Host *" block.
+// When included is set, the blocks it returns for an Include line are placed right
+// after the block holding that line.
+func (r *Repository) toConfigBlocks(cfg *ssh_config.Config, origin string, included func(line int) []domain.ConfigBlock) []domain.ConfigBlock {
+	blocks := make([]domain.ConfigBlock, 0, len(cfg.Hosts))
+	var pending []int
+	splice := func() {
+		if included != nil {
+			for _, line := range pending {
+				blocks = append(blocks, included(line)...)
+			}
+		}
+		pending = pending[:0]
+	}
+	for _, host := range cfg.Hosts {
+		own, trailing := splitMatchNodes(host.Nodes)
+		pending = append(pending, includeLines(own)...)
+
+		settings := settingsFromNodes(own)
+		if !host.Implicit || len(settings) > 0 {
+			patterns := make([]string, 0, len(host.Patterns))
+			for _, p := range host.Patterns {
+				patterns = append(patterns, p.String())
+			}
+			blocks = append(blocks, domain.ConfigBlock{
+				Kind:       domain.BlockHost,
+				Patterns:   patterns,
+				Settings:   settings,
+				SourceFile: origin,
+			})
+		}
+		splice()
+
+		var current *domain.ConfigBlock
+		for _, node := range trailing {
+			if inc, ok := node.(*ssh_config.Include); ok {
+				pending = append(pending, inc.Pos().Line)
+				continue
+			}
+			kv, ok := node.(*ssh_config.KV)
+			if !ok {
+				continue
+			}
+			if isMatchKV(kv) {
+				if current != nil {
+					blocks = append(blocks, *current)
+					splice()
+				}
+				current = &domain.ConfigBlock{
+					Kind:       domain.BlockMatch,
+					Criteria:   parseMatchCriteria(kv.Value),
+					SourceFile: origin,
+					Line:       kv.Pos().Line,
+				}
+				continue
+			}
+			current.Settings = append(current.Settings, domain.ConfigSetting{Key: kv.Key, Value: kv.Value})
+		}
+		if current != nil {
+			blocks = append(blocks, *current)
+			splice()
+		}
+	}
+	return blocks
+}
+
+// includeLines returns the line numbers of the Include directives among nodes.
+func includeLines(nodes []ssh_config.Node) []int {
+	var lines []int
+	for _, node := range nodes {
+		if inc, ok := node.(*ssh_config.Include); ok {
+			lines = append(lines, inc.Pos().Line)
+		}
+	}
+	return lines
+}
+
+// restoreNegatedPatterns puts back the leading "!" the parser strips from negated
+// Host patterns, so they are displayed and written back unchanged.
+func restoreNegatedPatterns(cfg *ssh_config.Config) {
+	for _, host := range cfg.Hosts {
+		for _, p := range host.Patterns {
+			if strings.HasPrefix(p.Str, "!") {
+				continue
+			}
+			// A pattern always matches its own text unless it is negated.
+			probe := &ssh_config.Host{Patterns: []*ssh_config.Pattern{p}}
+			if !probe.Matches(p.Str) {
+				p.Str = "!" + p.Str
+			}
+		}
+	}
+}
+
+// splitMatchNodes splits host nodes at the first Match keyword. The parser has no
+// notion of Match sections, so their lines end up attached to the preceding Host.
+// own holds the nodes that really belong to the Host; trailing starts with the Match line.
+func splitMatchNodes(nodes []ssh_config.Node) (own, trailing []ssh_config.Node) {
+	for i, node := range nodes {
+		if kv, ok := node.(*ssh_config.KV); ok && isMatchKV(kv) {
+			return nodes[:i:i], nodes[i:]
+		}
+	}
+	return nodes, nil
+}
+
+func isMatchKV(kv *ssh_config.KV) bool {
+	return strings.EqualFold(kv.Key, "match")
+}
+
+func settingsFromNodes(nodes []ssh_config.Node) []domain.ConfigSetting {
+	settings := make([]domain.ConfigSetting, 0, len(nodes))
+	for _, node := range nodes {
+		if kv, ok := node.(*ssh_config.KV); ok {
+			settings = append(settings, domain.ConfigSetting{Key: kv.Key, Value: kv.Value})
+		}
+	}
+	return settings
+}
+
+// parseMatchCriteria parses the arguments of a Match line, e.g.
+// `host *.prod,!bastion user deploy exec "test -f /etc/vpn"`.
+func parseMatchCriteria(value string) []domain.MatchCriterion {
+	fields := splitFieldsRespectQuotes(stripInlineComment(value))
+	criteria := make([]domain.MatchCriterion, 0, len(fields))
+	for i := 0; i < len(fields); i++ {
+		keyword := strings.ToLower(fields[i])
+		c := domain.MatchCriterion{}
+		if strings.HasPrefix(keyword, "!") {
+			c.Negated = true
+			keyword = keyword[1:]
+		}
+		c.Keyword = keyword
+		if !matchKeywordsWithoutArgument[keyword] && i+1 < len(fields) {
+			i++
+			c.Argument = unquote(fields[i])
+		}
+		criteria = append(criteria, c)
+	}
+	return criteria
+}
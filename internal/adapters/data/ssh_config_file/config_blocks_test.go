@@ -0,0 +1,138 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/kevinburke/ssh_config"
+	"go.uber.org/zap"
+)
+
+const matchTestConfig = `ForwardAgent no
+
+Host web
+    HostName 10.0.0.1
+    User deploy
+
+Match host *.prod,!bastion exec "test -f /etc/vpn"
+    ProxyJump bastion
+
+Host * !bastion
+    ServerAliveInterval 30
+`
+
+func decodeTestConfig(t *testing.T, text string) *ssh_config.Config {
+	t.Helper()
+	cfg, err := ssh_config.Decode(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	restoreNegatedPatterns(cfg)
+	return cfg
+}
+
+func TestToConfigBlocks(t *testing.T) {
+	r := &Repository{logger: zap.NewNop().Sugar()}
+	blocks := r.toConfigBlocks(decodeTestConfig(t, matchTestConfig), "/tmp/config", nil)
+
+	headers := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		headers = append(headers, b.Header())
+	}
+	want := []string{
+		"Host *",
+		"Host web",
+		`Match host *.prod,!bastion exec "test -f /etc/vpn"`,
+		"Host * !bastion",
+	}
+	if !reflect.DeepEqual(headers, want) {
+		t.Fatalf("headers = %q, want %q", headers, want)
+	}
+
+	match := blocks[2]
+	if match.Kind != domain.BlockMatch || match.Line != 7 {
+		t.Errorf("unexpected match block: %+v", match)
+	}
+	wantCriteria := []domain.MatchCriterion{
+		{Keyword: "host", Argument: "*.prod,!bastion"},
+		{Keyword: "exec", Argument: "test -f /etc/vpn"},
+	}
+	if !reflect.DeepEqual(match.Criteria, wantCriteria) {
+		t.Errorf("criteria = %+v, want %+v", match.Criteria, wantCriteria)
+	}
+	if len(match.Settings) != 1 || match.Settings[0].Key != "ProxyJump" {
+		t.Errorf("match settings = %+v", match.Settings)
+	}
+	if len(blocks[1].Settings) != 2 {
+		t.Errorf("Host web should not include Match settings: %+v", blocks[1].Settings)
+	}
+}
+
+func TestListConfigBlocksSplicesIncludes(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config")
+	if err := os.Mkdir(filepath.Join(dir, "config.d"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		configPath: "Include config.d/early\n\nHost web\n    HostName 10.0.0.1\n" +
+			"Match host *.prod\n    User deploy\n    Include config.d/late\n\nHost *\n    User admin\n",
+		filepath.Join(dir, "config.d", "early"): "Host db\n    HostName 10.0.0.2\n",
+		filepath.Join(dir, "config.d", "late"):  "Host cache\n    HostName 10.0.0.3\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	repo := NewRepository(zap.NewNop().Sugar(), configPath, filepath.Join(dir, "metadata.json"))
+
+	blocks, err := repo.ListConfigBlocks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	headers := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		headers = append(headers, b.Header())
+	}
+	want := []string{"Host db", "Host web", "Match host *.prod", "Host cache", "Host *"}
+	if !reflect.DeepEqual(headers, want) {
+		t.Errorf("headers = %q, want %q", headers, want)
+	}
+}
+
+func TestMatchSettingsDoNotLeakIntoServer(t *testing.T) {
+	r := &Repository{logger: zap.NewNop().Sugar()}
+	servers := r.toDomainServersFromConfig(decodeTestConfig(t, matchTestConfig), "/tmp/config", true)
+	if len(servers) != 1 {
+		t.Fatalf("expected only the web server, got %+v", servers)
+	}
+	if servers[0].ProxyJump != "" {
+		t.Errorf("ProxyJump from Match block leaked into web: %q", servers[0].ProxyJump)
+	}
+}
+
+func TestNegatedPatternsRoundTrip(t *testing.T) {
+	cfg := decodeTestConfig(t, matchTestConfig)
+	if !strings.Contains(cfg.String(), "Host * !bastion") {
+		t.Errorf("negated pattern lost when writing config:\n%s", cfg.String())
+	}
+}
@@ -15,7 +15,9 @@
 package ssh_config_file
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
@@ -23,15 +25,16 @@ import (
 	"github.com/kevinburke/ssh_config"
 )
 
-// loadConfig reads and parses the SSH config file.
+// loadConfigAt reads and parses the SSH config file at path and returns the
+// revision of the content it read, which saving the config checks the file against.
 // If the file does not exist, it returns an empty config without error to support first-run behavior.
-func (r *Repository) loadConfig() (*ssh_config.Config, error) {
-	file, err := r.fileSystem.Open(r.configPath)
+func (r *Repository) loadConfigAt(path string) (*ssh_config.Config, string, error) {
+	file, err := r.fileSystem.Open(path)
 	if err != nil {
 		if r.fileSystem.IsNotExist(err) {
-			return &ssh_config.Config{Hosts: []*ssh_config.Host{}}, nil
+			return &ssh_config.Config{Hosts: []*ssh_config.Host{}}, r.snapshots.remember(""), nil
 		}
-		return nil, fmt.Errorf("failed to open config file: %w", err)
+		return nil, "", fmt.Errorf("failed to open config file: %w", err)
 	}
 	defer func() {
 		if cerr := file.Close(); cerr != nil {
@@ -39,19 +42,34 @@ func (r *Repository) loadConfig() (*ssh_config.Config, error) {
 		}
 	}()
 
-	cfg, err := ssh_config.Decode(file)
+	data, err := io.ReadAll(file)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode config: %w", err)
+		return nil, "", fmt.Errorf("failed to read config file: %w", err)
 	}
+	cfg, err := ssh_config.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode config: %w", err)
+	}
+	restoreNegatedPatterns(cfg)
 
-	return cfg, nil
+	return cfg, r.snapshots.remember(string(data)), nil
 }
 
-// saveConfig writes the SSH config back to the file with atomic operations and backup management.
-func (r *Repository) saveConfig(cfg *ssh_config.Config) error {
-	configDir := filepath.Dir(r.configPath)
+// saveConfigAt writes an SSH config (the main file or an included one) back to path
+// with atomic operations and backup management. revision is the one loadConfigAt
+// returned for cfg; message describes the change for git versioning.
+func (r *Repository) saveConfigAt(path string, cfg *ssh_config.Config, revision, message string) error {
+	return r.replaceConfigAt(path, cfg.String(), revision, message)
+}
 
-	tempFile, err := r.createTempFile(configDir)
+// replaceConfigAt atomically replaces the config file at path with content,
+// backing up the current file first, and commits it with message when git
+// versioning is on. An empty message marks a write that changes nothing.
+// Right before the file is replaced, it fails with a ConfigConflictError when
+// the file no longer has the content of revision, so a change made outside
+// lazyssh since it was loaded is not lost; an empty revision is not checked.
+func (r *Repository) replaceConfigAt(path, content, revision, message string) error {
+	tempFile, err := r.createTempFile(path)
 	if err != nil {
 		return fmt.Errorf("failed to create temporary file: %w", err)
 	}
@@ -62,29 +80,46 @@ func (r *Repository) saveConfig(cfg *ssh_config.Config) error {
 		}
 	}()
 
-	if err := r.writeConfigToFile(tempFile, cfg); err != nil {
+	if err := r.writeConfigToFile(tempFile, content); err != nil {
 		return fmt.Errorf("failed to write config to temporary file: %w", err)
 	}
 
 	// Ensure a one-time original backup exists before any modifications managed by lazyssh.
-	if err := r.createOriginalBackupIfNeeded(); err != nil {
+	if err := r.createOriginalBackupIfNeeded(path); err != nil {
 		return fmt.Errorf("failed to create original backup: %w", err)
 	}
 
-	if err := r.createBackup(); err != nil {
-		return fmt.Errorf("failed to create backup: %w", err)
+	if r.batch == nil || !r.batch.backedUp[path] {
+		if err := r.createBackup(path); err != nil {
+			return fmt.Errorf("failed to create backup: %w", err)
+		}
+		if r.batch != nil {
+			r.batch.backedUp[path] = true
+		}
 	}
 
-	if err := r.fileSystem.Rename(tempFile, r.configPath); err != nil {
+	if err := r.checkConflict(path, revision); err != nil {
+		return err
+	}
+	if err := r.fileSystem.Rename(tempFile, path); err != nil {
 		return fmt.Errorf("failed to atomically replace config file: %w", err)
 	}
 
-	r.logger.Infof("SSH config successfully updated: %s", r.configPath)
+	r.logger.Infof("SSH config successfully updated: %s", path)
+	r.snapshots.setWritten(path, content)
+	if r.gitVersioning && message != "" {
+		write := configWrite{path: path, message: message}
+		if r.batch != nil {
+			r.batch.writes = append(r.batch.writes, write)
+		} else {
+			r.commitWrites([]configWrite{write})
+		}
+	}
 	return nil
 }
 
 // writeConfigToFile writes the SSH config content to the specified file
-func (r *Repository) writeConfigToFile(filePath string, cfg *ssh_config.Config) error {
+func (r *Repository) writeConfigToFile(filePath, content string) error {
 	file, err := r.fileSystem.OpenFile(filePath, os.O_WRONLY|os.O_TRUNC, SSHConfigPerms)
 	if err != nil {
 		return fmt.Errorf("failed to open file for writing: %w", err)
@@ -95,8 +130,7 @@ func (r *Repository) writeConfigToFile(filePath string, cfg *ssh_config.Config)
 		}
 	}()
 
-	configContent := cfg.String()
-	if _, err := file.WriteString(configContent); err != nil {
+	if _, err := file.WriteString(content); err != nil {
 		return fmt.Errorf("failed to write config content: %w", err)
 	}
 
@@ -107,11 +141,12 @@ func (r *Repository) writeConfigToFile(filePath string, cfg *ssh_config.Config)
 	return nil
 }
 
-// createTempFile creates a temporary file in the specified directory
-func (r *Repository) createTempFile(dir string) (string, error) {
+// createTempFile creates a temporary file next to the config file at path,
+// so it can be renamed over it atomically.
+func (r *Repository) createTempFile(path string) (string, error) {
 	timestamp := time.Now().Format("20060102150405")
-	tempFileName := fmt.Sprintf("config%s%s", timestamp, TempSuffix)
-	tempFilePath := filepath.Join(dir, tempFileName)
+	tempFileName := fmt.Sprintf("%s%s%s", filepath.Base(path), timestamp, TempSuffix)
+	tempFilePath := filepath.Join(filepath.Dir(path), tempFileName)
 
 	// Create the temp file with explicit 0600 permissions
 	f, err := r.fileSystem.OpenFile(tempFilePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, SSHConfigPerms)
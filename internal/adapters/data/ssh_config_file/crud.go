@@ -23,9 +23,10 @@ import (
 )
 
 const (
-	MaxBackups         = 10
+	MaxBackups         = 10 // default number of rolling backups kept per file
 	TempSuffix         = ".tmp"
 	BackupSuffix       = "lazyssh.backup"
+	GzipSuffix         = ".gz" // appended to compressed backups
 	SSHConfigPerms     = 0o600
 	OriginalBackupName = "config.original.backup"
 )
@@ -71,6 +72,23 @@ func (r *Repository) serverExists(cfg *ssh_config.Config, alias string) bool {
 	return r.findHostByAlias(cfg, alias) != nil
 }
 
+// ensureAliasAvailable fails when any config file already defines the alias,
+// since ssh would only ever use the first definition.
+func (r *Repository) ensureAliasAvailable(alias string) error {
+	servers, err := r.loadAllServers()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	for _, s := range servers {
+		for _, a := range s.Aliases {
+			if a == alias {
+				return fmt.Errorf("server with alias '%s' already exists", alias)
+			}
+		}
+	}
+	return nil
+}
+
 // findHostByAlias finds a host by its alias in the SSH config.
 func (r *Repository) findHostByAlias(cfg *ssh_config.Config, alias string) *ssh_config.Host {
 	for _, host := range cfg.Hosts {
@@ -526,9 +544,13 @@ func (r *Repository) convertCLIForwardToConfigFormat(forward string) string {
 }
 
 // removeHostByAlias removes a host by its alias from the list of hosts.
+// A Match section that follows the host is kept by attaching it to the previous host.
 func (r *Repository) removeHostByAlias(hosts []*ssh_config.Host, alias string) []*ssh_config.Host {
 	for i, host := range hosts {
 		if r.hostContainsPattern(host, alias) {
+			if _, trailing := splitMatchNodes(host.Nodes); len(trailing) > 0 && i > 0 {
+				hosts[i-1].Nodes = append(hosts[i-1].Nodes, trailing...)
+			}
 			return append(hosts[:i], hosts[i+1:]...)
 		}
 	}
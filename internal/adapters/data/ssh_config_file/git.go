@@ -0,0 +1,406 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+const (
+	// gitTimeout bounds each git command, so a slow hook cannot hang a save.
+	gitTimeout = 10 * time.Second
+	// maxSubjectLength is the longest subject a commit message gets before its
+	// setting changes move to the body.
+	maxSubjectLength = 72
+	// serverLogLimit is the number of commits ServerLog returns.
+	serverLogLimit = 10
+)
+
+// configWrite is a config file written with a message describing the change.
+type configWrite struct {
+	path    string
+	message string
+}
+
+// commitWrites commits the files of writes to the git repositories holding
+// them. The main config's directory becomes a repository if it is not in one;
+// included files outside a repository are not versioned. Versioning never fails
+// a save, problems are only logged.
+func (r *Repository) commitWrites(writes []configWrite) {
+	messages := make([]string, 0, len(writes))
+	roots := make([]string, 0, 1)
+	files := make(map[string][]string)
+	for _, w := range writes {
+		if !slices.Contains(messages, w.message) {
+			messages = append(messages, w.message)
+		}
+		root, rel, err := r.gitRepoFor(w.path)
+		if err != nil {
+			r.logger.Debugf("not versioning %s: %v", w.path, err)
+			continue
+		}
+		managed, err := allowInGitignore(root, rel)
+		if err != nil {
+			r.logger.Warnf("not versioning %s: %v", w.path, err)
+			continue
+		}
+		if _, ok := files[root]; !ok {
+			roots = append(roots, root)
+		}
+		for _, f := range []string{rel, gitignoreName} {
+			if (f == rel || managed) && !slices.Contains(files[root], f) {
+				files[root] = append(files[root], f)
+			}
+		}
+	}
+
+	message := commitMessage(messages)
+	for _, root := range roots {
+		if err := commitFiles(root, files[root], message); err != nil {
+			r.logger.Warnf("failed to commit config change in %s: %v", root, err)
+		}
+	}
+}
+
+// gitRepoFor returns the work tree holding path and path relative to it,
+// creating a repository next to the main config if needed.
+func (r *Repository) gitRepoFor(path string) (root, rel string, err error) {
+	root, rel, err = gitWorkTree(path)
+	if err == nil || path != r.configPath {
+		return root, rel, err
+	}
+	// The main config usually sits next to private keys in ~/.ssh, so the new
+	// repository ignores everything but the files lazyssh versions.
+	dir := filepath.Dir(resolvePath(path))
+	if _, err := runGit(dir, "init", "-q"); err != nil {
+		return "", "", err
+	}
+	ignore := filepath.Join(dir, gitignoreName)
+	if _, err := os.Stat(ignore); os.IsNotExist(err) {
+		if err := os.WriteFile(ignore, []byte(gitignoreHeader), 0o600); err != nil {
+			return "", "", fmt.Errorf("failed to write %s: %w", ignore, err)
+		}
+	}
+	r.logger.Infof("Initialized a git repository in %s to version %s", dir, path)
+	return gitWorkTree(path)
+}
+
+const gitignoreName = ".gitignore"
+
+// gitignoreHeader starts the .gitignore of a repository lazyssh created: it
+// ignores everything, and allowInGitignore lets the versioned files back in.
+const gitignoreHeader = `# Written by lazyssh: only the SSH config files below are versioned, never keys.
+/*
+!/.gitignore
+`
+
+// allowInGitignore adds the rules that let rel be versioned to the .gitignore
+// of root when lazyssh wrote it, and reports whether it did; other .gitignore
+// files are left alone.
+func allowInGitignore(root, rel string) (bool, error) {
+	path := filepath.Join(root, gitignoreName)
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.HasPrefix(string(data), gitignoreHeader) {
+		return false, nil
+	}
+	lines := strings.Split(string(data), "\n")
+	var missing []string
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	for i := range parts {
+		entry := "/" + strings.Join(parts[:i+1], "/")
+		rules := []string{"!" + entry}
+		if i < len(parts)-1 {
+			// Let the directory in, but none of its other files.
+			rules = []string{"!" + entry + "/", entry + "/*"}
+		}
+		for _, rule := range rules {
+			if !slices.Contains(lines, rule) && !slices.Contains(missing, rule) {
+				missing = append(missing, rule)
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return true, nil
+	}
+	content := string(data)
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += strings.Join(missing, "\n") + "\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		return true, fmt.Errorf("failed to update %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// commitFiles commits the files rels of the work tree root, leaving anything
+// else staged there alone.
+func commitFiles(root string, rels []string, message string) error {
+	if _, err := runGit(root, append([]string{"add", "--"}, rels...)...); err != nil {
+		return err
+	}
+	// Nothing to commit when the files are back to their committed content.
+	if _, err := runGit(root, append([]string{"diff", "--cached", "--quiet", "--"}, rels...)...); err == nil {
+		return nil
+	}
+
+	var env []string
+	if _, err := runGit(root, "var", "GIT_AUTHOR_IDENT"); err != nil {
+		// No identity configured; commit anyway rather than lose the change.
+		env = []string{
+			"GIT_AUTHOR_NAME=lazyssh", "GIT_AUTHOR_EMAIL=lazyssh@localhost",
+			"GIT_COMMITTER_NAME=lazyssh", "GIT_COMMITTER_EMAIL=lazyssh@localhost",
+		}
+	}
+	_, err := runGitEnv(root, env, append([]string{"commit", "-q", "--only", "-m", message, "--"}, rels...)...)
+	return err
+}
+
+// ServerLog returns the most recent commits that changed the Host block of
+// server, following the block's lines back through history.
+func (r *Repository) ServerLog(server domain.Server) ([]domain.ConfigCommit, error) {
+	if !r.gitVersioning {
+		return nil, nil
+	}
+	path := server.SourceFile
+	if path == "" {
+		path = r.configPath
+	}
+	root, rel, err := gitWorkTree(path)
+	if err != nil {
+		return nil, nil
+	}
+	// Line ranges refer to the committed file, which may differ from the one on disk.
+	committed, err := runGit(root, "show", "HEAD:"+rel)
+	if err != nil {
+		return nil, nil
+	}
+	start, end, ok := hostLines(committed, server.Alias)
+	if !ok {
+		return nil, nil
+	}
+
+	out, err := runGit(root, "log", "-L", fmt.Sprintf("%d,%d:%s", start, end, rel), "--no-patch",
+		"-n", strconv.Itoa(serverLogLimit), "--format=%h%x1f%an%x1f%aI%x1f%s")
+	if err != nil {
+		return nil, err
+	}
+	return parseLog(out), nil
+}
+
+// parseLog parses the output of git log with the format used by ServerLog.
+func parseLog(out string) []domain.ConfigCommit {
+	commits := make([]domain.ConfigCommit, 0, serverLogLimit)
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, "\x1f")
+		if len(fields) != 4 {
+			continue
+		}
+		date, _ := time.Parse(time.RFC3339, fields[2])
+		commits = append(commits, domain.ConfigCommit{
+			Hash:    fields[0],
+			Author:  fields[1],
+			Date:    date,
+			Subject: fields[3],
+		})
+	}
+	return commits
+}
+
+// hostLines returns the 1-based line range of the Host block defining alias in
+// content: from its Host line to its last setting before the next Host or Match.
+func hostLines(content, alias string) (start, end int, ok bool) {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		key, value := splitSetting(strings.TrimSpace(line))
+		isHost := strings.EqualFold(key, "host")
+		if start > 0 && (isHost || strings.EqualFold(key, "match")) {
+			return start, lastSettingLine(lines, start, i), true
+		}
+		if start == 0 && isHost && slices.Contains(strings.Fields(value), alias) {
+			start = i + 1
+		}
+	}
+	if start == 0 {
+		return 0, 0, false
+	}
+	return start, lastSettingLine(lines, start, len(lines)), true
+}
+
+// lastSettingLine returns the 1-based number of the last line before line end
+// (exclusive, 0-based) that is neither blank nor a comment, but not before start.
+func lastSettingLine(lines []string, start, end int) int {
+	for end > start {
+		line := strings.TrimSpace(lines[end-1])
+		if line != "" && !strings.HasPrefix(line, "#") {
+			break
+		}
+		end--
+	}
+	return end
+}
+
+// describeEdit summarizes the change between two recorded blocks of a host,
+// e.g. "update web-01: Port 22 -> 2222". Long lists of changes go to the body.
+func describeEdit(aliasBefore, aliasAfter, before, after string) string {
+	subject := "update " + aliasAfter
+	if aliasBefore != aliasAfter {
+		subject = fmt.Sprintf("rename %s -> %s", aliasBefore, aliasAfter)
+	}
+	changes := settingChanges(before, after)
+	if len(changes) == 0 {
+		return subject
+	}
+	if line := subject + ": " + strings.Join(changes, ", "); len(line) <= maxSubjectLength {
+		return line
+	}
+	return fmt.Sprintf("%s: %d settings\n\n%s", subject, len(changes), strings.Join(changes, "\n"))
+}
+
+// settingChanges lists the settings that differ between two blocks as
+// "Key old -> new", "+Key value" for added and "-Key" for removed ones.
+func settingChanges(before, after string) []string {
+	oldKeys, oldValues := blockSettings(before)
+	newKeys, newValues := blockSettings(after)
+	changes := make([]string, 0, 4)
+	for _, key := range newKeys {
+		value := newValues[strings.ToLower(key)]
+		old, ok := oldValues[strings.ToLower(key)]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("+%s %s", key, value))
+		case old != value:
+			changes = append(changes, fmt.Sprintf("%s %s -> %s", key, old, value))
+		}
+	}
+	for _, key := range oldKeys {
+		if _, ok := newValues[strings.ToLower(key)]; !ok {
+			changes = append(changes, "-"+key)
+		}
+	}
+	return changes
+}
+
+// blockSettings returns the keys of the settings in block in order of
+// appearance and their values by lower-case key. Repeated keys such as
+// IdentityFile have their values joined.
+func blockSettings(block string) (keys []string, values map[string]string) {
+	values = make(map[string]string)
+	for _, line := range blockLines(block) {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value := splitSetting(line)
+		lower := strings.ToLower(key)
+		if lower == "host" || lower == "match" {
+			continue
+		}
+		if old, ok := values[lower]; ok {
+			values[lower] = old + ", " + value
+			continue
+		}
+		keys = append(keys, key)
+		values[lower] = value
+	}
+	return keys, values
+}
+
+// splitSetting splits a config line into its keyword and value, which are
+// separated by whitespace and/or "=".
+func splitSetting(line string) (key, value string) {
+	i := strings.IndexAny(line, " \t=")
+	if i < 0 {
+		return line, ""
+	}
+	return line[:i], strings.TrimSpace(strings.TrimLeft(line[i:], " \t="))
+}
+
+// commitMessage combines the messages of writes committed together. Several
+// changes get a summary subject such as "update 3 servers" and are listed in
+// the body.
+func commitMessage(messages []string) string {
+	if len(messages) == 1 {
+		return messages[0]
+	}
+	verb, _, _ := strings.Cut(messages[0], " ")
+	subjects := make([]string, 0, len(messages))
+	for _, m := range messages {
+		subject, _, _ := strings.Cut(m, "\n")
+		if v, _, _ := strings.Cut(subject, " "); v != verb {
+			verb = "change"
+		}
+		subjects = append(subjects, subject)
+	}
+	return fmt.Sprintf("%s %d servers\n\n%s", verb, len(messages), strings.Join(subjects, "\n"))
+}
+
+// gitWorkTree returns the root of the git work tree holding path and path
+// relative to it, with symlinks resolved.
+func gitWorkTree(path string) (root, rel string, err error) {
+	resolved := resolvePath(path)
+	out, err := runGit(filepath.Dir(resolved), "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", "", err
+	}
+	root = filepath.FromSlash(strings.TrimSpace(out))
+	rel, err = filepath.Rel(root, resolved)
+	if err != nil {
+		return "", "", err
+	}
+	return root, filepath.ToSlash(rel), nil
+}
+
+// resolvePath returns path with symlinks resolved, or path itself when that fails.
+func resolvePath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return path
+}
+
+// runGit runs git in dir and returns its output. Errors carry git's message.
+func runGit(dir string, args ...string) (string, error) {
+	return runGitEnv(dir, nil, args...)
+}
+
+// runGitEnv is runGit with extra environment variables.
+func runGitEnv(dir string, env []string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), gitTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(append(os.Environ(), "GIT_TERMINAL_PROMPT=0"), env...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return string(out), nil
+}
@@ -0,0 +1,206 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"go.uber.org/zap"
+)
+
+func TestDescribeEdit(t *testing.T) {
+	web := "Host web\n    HostName 10.0.0.1\n    Port 22\n    IdentityFile ~/.ssh/a\n"
+	tests := []struct {
+		name        string
+		aliasAfter  string
+		after, want string
+	}{
+		{"changed", "web", "Host web\n    HostName 10.0.0.1\n    Port 2222\n    IdentityFile ~/.ssh/a\n", "update web: Port 22 -> 2222"},
+		{"added and removed", "web", "Host web\n    HostName 10.0.0.1\n    Port 22\n    User deploy\n", "update web: +User deploy, -IdentityFile"},
+		{"repeated key", "web", web + "    IdentityFile ~/.ssh/b\n", "update web: IdentityFile ~/.ssh/a -> ~/.ssh/a, ~/.ssh/b"},
+		{"renamed", "web-01", "Host web-01\n    HostName 10.0.0.1\n    Port 22\n    IdentityFile ~/.ssh/a\n", "rename web -> web-01"},
+		{"key case and equals", "web", "Host web\n    hostname=10.0.0.9\n    Port 22\n    IdentityFile ~/.ssh/a\n", "update web: hostname 10.0.0.1 -> 10.0.0.9"},
+		{
+			"long", "web",
+			"Host web\n    HostName bastion-frontend.internal.example.com\n    Port 2222\n    User deploy\n",
+			"update web: 4 settings\n\nHostName 10.0.0.1 -> bastion-frontend.internal.example.com\nPort 22 -> 2222\n+User deploy\n-IdentityFile",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describeEdit("web", tt.aliasAfter, web, tt.after); got != tt.want {
+				t.Errorf("describeEdit() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommitMessage(t *testing.T) {
+	tests := []struct {
+		messages []string
+		want     string
+	}{
+		{[]string{"delete web"}, "delete web"},
+		{[]string{"delete web", "delete db"}, "delete 2 servers\n\ndelete web\ndelete db"},
+		{[]string{"update web: 2 settings\n\nPort 22 -> 2\n-User", "add db"}, "change 2 servers\n\nupdate web: 2 settings\nadd db"},
+	}
+	for _, tt := range tests {
+		if got := commitMessage(tt.messages); got != tt.want {
+			t.Errorf("commitMessage(%q) = %q, want %q", tt.messages, got, tt.want)
+		}
+	}
+}
+
+func TestHostLines(t *testing.T) {
+	equalsSign := strings.Replace(historyTestConfig, "Host web", "Host=web-01 www", 1)
+	tests := []struct {
+		content, alias string
+		start, end     int
+		ok             bool
+	}{
+		{historyTestConfig, "web", 2, 5, true},
+		{historyTestConfig, "db", 7, 9, true},
+		{historyTestConfig, "cache", 0, 0, false},
+		{equalsSign, "www", 2, 5, true},
+		{"Host a\n  User x\n\n# b\n", "a", 1, 2, true},
+	}
+	for _, tt := range tests {
+		start, end, ok := hostLines(tt.content, tt.alias)
+		if start != tt.start || end != tt.end || ok != tt.ok {
+			t.Errorf("hostLines(%q) = %d, %d, %v, want %d, %d, %v", tt.alias, start, end, ok, tt.start, tt.end, tt.ok)
+		}
+	}
+}
+
+func TestGitVersioning(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	// No identity: commits fall back to lazyssh.
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config")
+	if err := os.WriteFile(configPath, []byte(historyTestConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewRepository(zap.NewNop().Sugar(), configPath, filepath.Join(dir, "metadata.json"), WithGitVersioning(true))
+
+	web := findServer(t, repo, "web")
+	updated := web
+	updated.Port = 2222
+	if err := repo.UpdateServer(web, updated); err != nil {
+		t.Fatal(err)
+	}
+	db := findServer(t, repo, "db")
+	updated = db
+	updated.User = "admin"
+	if err := repo.UpdateServer(db, updated); err != nil {
+		t.Fatal(err)
+	}
+	servers := []domain.Server{findServer(t, repo, "web"), findServer(t, repo, "db")}
+	err := repo.Batch(func() error {
+		for _, s := range servers {
+			updated := s
+			updated.Port = 2200
+			if err := repo.UpdateServer(s, updated); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only metadata changes: nothing to commit.
+	tagged := findServer(t, repo, "web")
+	tagged.Tags = []string{"prod"}
+	if err := repo.UpdateServer(findServer(t, repo, "web"), tagged); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := exec.Command("git", "-C", dir, "log", "--format=%an|%s").Output()
+	if err != nil {
+		t.Fatalf("git log: %v", err)
+	}
+	want := "lazyssh|update 2 servers\nlazyssh|update db: +User admin\nlazyssh|update web: Port 22 -> 2222\n"
+	if string(out) != want {
+		t.Errorf("git log =\n%s\nwant\n%s", out, want)
+	}
+
+	commits, err := repo.ServerLog(findServer(t, repo, "db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subjects := make([]string, 0, len(commits))
+	for _, c := range commits {
+		if c.Author != "lazyssh" || c.Hash == "" || c.Date.IsZero() {
+			t.Errorf("unexpected commit %+v", c)
+		}
+		subjects = append(subjects, c.Subject)
+	}
+	// The first commit added the whole file, db's block included.
+	wantSubjects := []string{"update 2 servers", "update db: +User admin", "update web: Port 22 -> 2222"}
+	if !slices.Equal(subjects, wantSubjects) {
+		t.Errorf("ServerLog(db) = %q, want %q", subjects, wantSubjects)
+	}
+
+	// The repository lazyssh created next to the config ignores everything else.
+	if err := os.WriteFile(filepath.Join(dir, "id_ed25519"), []byte("private"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command("git", "-C", dir, "status", "--porcelain").Output(); err != nil || len(out) != 0 {
+		t.Errorf("git status = %q, %v; want a clean tree", out, err)
+	}
+	if out, _ := exec.Command("git", "-C", dir, "ls-files").Output(); string(out) != ".gitignore\nconfig\n" {
+		t.Errorf("git ls-files = %q", out)
+	}
+}
+
+func TestAllowInGitignore(t *testing.T) {
+	root := t.TempDir()
+	ignore := filepath.Join(root, gitignoreName)
+	if managed, err := allowInGitignore(root, "config"); managed || err != nil {
+		t.Errorf("allowInGitignore() without a .gitignore = %v, %v", managed, err)
+	}
+
+	if err := os.WriteFile(ignore, []byte(gitignoreHeader), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, rel := range []string{"config", "config.d/work", "config.d/home", "config"} {
+		if managed, err := allowInGitignore(root, rel); !managed || err != nil {
+			t.Fatalf("allowInGitignore(%q) = %v, %v", rel, managed, err)
+		}
+	}
+	want := gitignoreHeader + "!/config\n!/config.d/\n/config.d/*\n!/config.d/work\n!/config.d/home\n"
+	if got := readFile(t, ignore); got != want {
+		t.Errorf(".gitignore =\n%s\nwant\n%s", got, want)
+	}
+
+	if err := os.WriteFile(ignore, []byte("*.swp\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if managed, _ := allowInGitignore(root, "config"); managed || readFile(t, ignore) != "*.swp\n" {
+		t.Error("allowInGitignore() changed a .gitignore lazyssh did not write")
+	}
+}
@@ -0,0 +1,408 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kevinburke/ssh_config"
+	"go.uber.org/zap"
+)
+
+const (
+	// HistoryFileName is the undo history kept next to the metadata file.
+	HistoryFileName = "history.json"
+	// historyLimit is the number of changes that can be undone.
+	historyLimit = 100
+)
+
+// hostEdit is one reversible change to a Host block and its metadata. Blocks hold
+// the text of the Host line and its own settings; an empty block means the host
+// did not exist on that side of the change, and an empty File means only the
+// metadata changed. Trailing counts the Match section lines that followed a
+// deleted host and were left to the previous one.
+type hostEdit struct {
+	Action      string          `json:"action"`
+	File        string          `json:"file,omitempty"`
+	Index       int             `json:"index,omitempty"`
+	Trailing    int             `json:"trailing,omitempty"`
+	AliasBefore string          `json:"alias_before,omitempty"`
+	AliasAfter  string          `json:"alias_after,omitempty"`
+	Before      string          `json:"before,omitempty"`
+	After       string          `json:"after,omitempty"`
+	MetaBefore  *ServerMetadata `json:"meta_before,omitempty"`
+	MetaAfter   *ServerMetadata `json:"meta_after,omitempty"`
+}
+
+// editSide is the state of a host on one side of a hostEdit.
+type editSide struct {
+	alias string
+	block string
+	meta  *ServerMetadata
+}
+
+// sides returns the state to move away from and the state to move to.
+func (e hostEdit) sides(undo bool) (from, to editSide) {
+	before := editSide{alias: e.AliasBefore, block: e.Before, meta: e.MetaBefore}
+	after := editSide{alias: e.AliasAfter, block: e.After, meta: e.MetaAfter}
+	if undo {
+		return after, before
+	}
+	return before, after
+}
+
+func (e hostEdit) alias() string {
+	if e.AliasAfter != "" {
+		return e.AliasAfter
+	}
+	return e.AliasBefore
+}
+
+// historyEntry is one undo step; a Batch records all its edits as one entry.
+type historyEntry struct {
+	Summary string     `json:"summary"`
+	At      time.Time  `json:"at"`
+	Edits   []hostEdit `json:"edits"`
+}
+
+func newHistoryEntry(edits []hostEdit) historyEntry {
+	entry := historyEntry{At: time.Now(), Edits: edits}
+	action := edits[0].Action
+	for _, e := range edits[1:] {
+		if e.Action != action {
+			action = "change"
+			break
+		}
+	}
+	if len(edits) == 1 {
+		entry.Summary = capitalize(action) + " " + edits[0].alias()
+	} else {
+		entry.Summary = fmt.Sprintf("%s %d servers", capitalize(action), len(edits))
+	}
+	return entry
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+type history struct {
+	Undo []historyEntry `json:"undo"`
+	Redo []historyEntry `json:"redo"`
+}
+
+type historyStore struct {
+	filePath string
+	logger   *zap.SugaredLogger
+}
+
+func newHistoryStore(metaDataPath string, logger *zap.SugaredLogger) *historyStore {
+	return &historyStore{filePath: filepath.Join(filepath.Dir(metaDataPath), HistoryFileName), logger: logger}
+}
+
+func (h *historyStore) load() (history, error) {
+	var hist history
+	data, err := os.ReadFile(h.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return hist, nil
+		}
+		return hist, fmt.Errorf("read history '%s': %w", h.filePath, err)
+	}
+	if len(data) == 0 {
+		return hist, nil
+	}
+	if err := json.Unmarshal(data, &hist); err != nil {
+		return hist, fmt.Errorf("parse history JSON '%s': %w", h.filePath, err)
+	}
+	return hist, nil
+}
+
+func (h *historyStore) save(hist history) error {
+	if err := os.MkdirAll(filepath.Dir(h.filePath), 0o750); err != nil {
+		return fmt.Errorf("mkdir '%s': %w", filepath.Dir(h.filePath), err)
+	}
+	data, err := json.MarshalIndent(hist, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal history: %w", err)
+	}
+	if err := os.WriteFile(h.filePath, data, 0o600); err != nil {
+		return fmt.Errorf("write history '%s': %w", h.filePath, err)
+	}
+	return nil
+}
+
+// push records a new change; it drops the oldest entries beyond historyLimit
+// and clears the redo stack.
+func (h *historyStore) push(entry historyEntry) {
+	hist, err := h.load()
+	if err != nil {
+		h.logger.Warnf("failed to load history, starting a new one: %v", err)
+	}
+	hist.Undo = append(hist.Undo, entry)
+	if len(hist.Undo) > historyLimit {
+		hist.Undo = hist.Undo[len(hist.Undo)-historyLimit:]
+	}
+	hist.Redo = nil
+	if err := h.save(hist); err != nil {
+		h.logger.Warnf("failed to save history: %v", err)
+	}
+}
+
+// record adds edit to the undo history, or to the running Batch.
+func (r *Repository) record(edit hostEdit) {
+	if r.batch != nil {
+		r.batch.edits = append(r.batch.edits, edit)
+		return
+	}
+	r.history.push(newHistoryEntry([]hostEdit{edit}))
+}
+
+// hostBlock returns the text of the Host line and its own settings, without a
+// following Match section.
+func hostBlock(host *ssh_config.Host) string {
+	own, _ := splitMatchNodes(host.Nodes)
+	block := *host
+	block.Nodes = own
+	return block.String()
+}
+
+// sameBlock compares two blocks ignoring indentation and blank lines.
+func sameBlock(a, b string) bool {
+	return slices.Equal(blockLines(a), blockLines(b))
+}
+
+func blockLines(block string) []string {
+	lines := make([]string, 0, 8)
+	for _, line := range strings.Split(block, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// parseHostBlock parses a block recorded by hostBlock back into a Host.
+func parseHostBlock(block string) (*ssh_config.Host, error) {
+	cfg, err := ssh_config.Decode(strings.NewReader(block))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode recorded block: %w", err)
+	}
+	restoreNegatedPatterns(cfg)
+	for _, host := range cfg.Hosts {
+		if !host.Implicit {
+			return host, nil
+		}
+	}
+	return nil, fmt.Errorf("recorded block has no Host line")
+}
+
+// metadataSnapshot returns a copy of the metadata of alias, or nil if it has none.
+func (r *Repository) metadataSnapshot(alias string) *ServerMetadata {
+	all, err := r.metadataManager.loadAll()
+	if err != nil {
+		return nil
+	}
+	meta, ok := all[alias]
+	if !ok {
+		return nil
+	}
+	meta.Tags = slices.Clone(meta.Tags)
+	return &meta
+}
+
+// Undo reverts the most recent recorded change and returns its description.
+func (r *Repository) Undo() (string, error) {
+	return r.replay(true)
+}
+
+// Redo applies the most recently undone change again and returns its description.
+func (r *Repository) Redo() (string, error) {
+	return r.replay(false)
+}
+
+func (r *Repository) replay(undo bool) (string, error) {
+	hist, err := r.history.load()
+	if err != nil {
+		return "", err
+	}
+	verb, stack, other := "undo", &hist.Undo, &hist.Redo
+	if !undo {
+		verb, stack, other = "redo", other, stack
+	}
+	if len(*stack) == 0 {
+		return "", fmt.Errorf("nothing to %s", verb)
+	}
+	entry := (*stack)[len(*stack)-1]
+
+	if err := r.applyEntry(entry, undo); err != nil {
+		return "", fmt.Errorf("cannot %s %q: %w", verb, entry.Summary, err)
+	}
+
+	*stack = (*stack)[:len(*stack)-1]
+	*other = append(*other, entry)
+	if err := r.history.save(hist); err != nil {
+		r.logger.Warnf("failed to save history: %v", err)
+	}
+	return entry.Summary, nil
+}
+
+// applyEntry moves every host of entry to the other side of its edits. All
+// blocks are checked against the files first, so nothing is written when any
+// of them was changed outside lazyssh in the meantime.
+func (r *Repository) applyEntry(entry historyEntry, undo bool) error {
+	edits := slices.Clone(entry.Edits)
+	if undo {
+		slices.Reverse(edits)
+	}
+
+	configs := make(map[string]*ssh_config.Config)
+	revisions := make(map[string]string)
+	order := make([]string, 0, 2)
+	for _, e := range edits {
+		if e.File == "" {
+			continue
+		}
+		path, err := r.targetFile(e.File)
+		if err != nil {
+			return err
+		}
+		cfg, ok := configs[path]
+		if !ok {
+			var revision string
+			if cfg, revision, err = r.loadConfigAt(path); err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			configs[path] = cfg
+			revisions[path] = revision
+			order = append(order, path)
+		}
+		from, to := e.sides(undo)
+		if err := r.applyBlock(cfg, e, from, to); err != nil {
+			return err
+		}
+	}
+
+	metadata, err := r.metadataManager.loadAll()
+	if err != nil {
+		return fmt.Errorf("load metadata: %w", err)
+	}
+	for _, e := range edits {
+		from, to := e.sides(undo)
+		applyMetadata(metadata, from, to)
+	}
+
+	verb := "undo"
+	if !undo {
+		verb = "redo"
+	}
+	message := fmt.Sprintf("%s %q", verb, entry.Summary)
+	err = r.Batch(func() error {
+		for _, path := range order {
+			if err := r.saveConfigAt(path, configs[path], revisions[path], message); err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return r.metadataManager.saveAll(metadata)
+}
+
+// applyBlock replaces, removes or re-inserts the Host block of one edit in cfg.
+func (r *Repository) applyBlock(cfg *ssh_config.Config, e hostEdit, from, to editSide) error {
+	if from.block == "" {
+		if r.findHostByAlias(cfg, to.alias) != nil {
+			return fmt.Errorf("%s already exists", to.alias)
+		}
+		host, err := parseHostBlock(to.block)
+		if err != nil {
+			return err
+		}
+		index := min(max(e.Index, 0), len(cfg.Hosts))
+		if n := e.Trailing; n > 0 && index > 0 {
+			// Take back the Match section the host was followed by.
+			prev := cfg.Hosts[index-1]
+			if cut := len(prev.Nodes) - n; cut >= 0 {
+				if kv, ok := prev.Nodes[cut].(*ssh_config.KV); ok && isMatchKV(kv) {
+					host.Nodes = append(host.Nodes, prev.Nodes[cut:]...)
+					prev.Nodes = prev.Nodes[:cut]
+				}
+			}
+		}
+		cfg.Hosts = slices.Insert(cfg.Hosts, index, host)
+		return nil
+	}
+
+	i := slices.IndexFunc(cfg.Hosts, func(h *ssh_config.Host) bool { return r.hostContainsPattern(h, from.alias) })
+	if i < 0 {
+		return fmt.Errorf("%s no longer exists", from.alias)
+	}
+	if !sameBlock(hostBlock(cfg.Hosts[i]), from.block) {
+		return fmt.Errorf("%s was changed since", from.alias)
+	}
+	if to.block == "" {
+		cfg.Hosts = r.removeHostByAlias(cfg.Hosts, from.alias)
+		return nil
+	}
+	if to.alias != from.alias && r.findHostByAlias(cfg, to.alias) != nil {
+		return fmt.Errorf("%s already exists", to.alias)
+	}
+	host, err := parseHostBlock(to.block)
+	if err != nil {
+		return err
+	}
+	_, trailing := splitMatchNodes(cfg.Hosts[i].Nodes)
+	host.Nodes = append(host.Nodes, trailing...)
+	cfg.Hosts[i] = host
+	return nil
+}
+
+// applyMetadata moves the tags and pin of one edit to the other side. Usage
+// statistics recorded since the change are kept.
+func applyMetadata(metadata map[string]ServerMetadata, from, to editSide) {
+	current, ok := metadata[from.alias]
+	if from.alias != "" {
+		delete(metadata, from.alias)
+	}
+	if to.alias == "" {
+		return
+	}
+	switch {
+	case to.meta == nil:
+		current.Tags = nil
+		current.PinnedAt = ""
+	case !ok:
+		current = *to.meta
+	default:
+		current.Tags = to.meta.Tags
+		current.PinnedAt = to.meta.PinnedAt
+	}
+	if current.Tags == nil && current.PinnedAt == "" && current.LastSeen == "" && current.SSHCount == 0 {
+		return
+	}
+	metadata[to.alias] = current
+}
@@ -0,0 +1,214 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/core/ports"
+	"go.uber.org/zap"
+)
+
+const historyTestConfig = `# team hosts
+Host web
+    HostName 10.0.0.1
+    User deploy
+    Port 22
+
+Host db
+    HostName 10.0.0.2
+    Port 22
+
+Match host 10.0.*
+    Compression yes
+`
+
+func newHistoryRepo(t *testing.T, config string) (ports.ServerRepository, string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config")
+	if err := os.WriteFile(configPath, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	metaPath := filepath.Join(dir, "metadata.json")
+	return NewRepository(zap.NewNop().Sugar(), configPath, metaPath), configPath, metaPath
+}
+
+func findServer(t *testing.T, repo ports.ServerRepository, alias string) domain.Server {
+	t.Helper()
+	servers, err := repo.ListServers("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range servers {
+		if s.Alias == alias {
+			return s
+		}
+	}
+	t.Fatalf("server %q not found in %+v", alias, servers)
+	return domain.Server{}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestUndoRedoUpdateKeepsUnrelatedEdits(t *testing.T) {
+	repo, configPath, _ := newHistoryRepo(t, historyTestConfig)
+
+	web := findServer(t, repo, "web")
+	updated := web
+	updated.User = "admin"
+	updated.Port = 2222
+	if err := repo.UpdateServer(web, updated); err != nil {
+		t.Fatal(err)
+	}
+
+	// An edit made outside lazyssh after the change must survive the undo.
+	edited := strings.Replace(readFile(t, configPath), "HostName 10.0.0.2", "HostName 10.0.0.9", 1)
+	if err := os.WriteFile(configPath, []byte(edited), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	summary, err := repo.Undo()
+	if err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if summary != "Edit web" {
+		t.Errorf("Undo() = %q, want %q", summary, "Edit web")
+	}
+	got := readFile(t, configPath)
+	if want := strings.Replace(historyTestConfig, "10.0.0.2", "10.0.0.9", 1); got != want {
+		t.Errorf("config after undo:\n%s\nwant:\n%s", got, want)
+	}
+
+	if _, err := repo.Redo(); err != nil {
+		t.Fatalf("Redo() error = %v", err)
+	}
+	assertFileContains(t, configPath, "User admin", "Port 2222", "HostName 10.0.0.9", "Match host 10.0.*")
+	if _, err := repo.Redo(); err == nil || !strings.Contains(err.Error(), "nothing to redo") {
+		t.Errorf("second Redo() error = %v, want nothing to redo", err)
+	}
+}
+
+func TestUndoDeleteRestoresBlockAndTags(t *testing.T) {
+	repo, configPath, _ := newHistoryRepo(t, historyTestConfig)
+
+	web := findServer(t, repo, "web")
+	tagged := web
+	tagged.Tags = []string{"prod"}
+	if err := repo.UpdateServer(web, tagged); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.SetPinned("web", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.DeleteServer(findServer(t, repo, "web")); err != nil {
+		t.Fatal(err)
+	}
+	// db is followed by the Match section, which must come back after it.
+	if err := repo.DeleteServer(findServer(t, repo, "db")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Undo(); err != nil {
+		t.Fatal(err)
+	}
+
+	if summary, err := repo.Undo(); err != nil || summary != "Delete web" {
+		t.Fatalf("Undo() = %q, %v", summary, err)
+	}
+	if got := readFile(t, configPath); got != historyTestConfig {
+		t.Errorf("config after undoing delete:\n%s\nwant:\n%s", got, historyTestConfig)
+	}
+	restored := findServer(t, repo, "web")
+	if len(restored.Tags) != 1 || restored.Tags[0] != "prod" || restored.PinnedAt.IsZero() {
+		t.Errorf("restored metadata = tags %v, pinned %v", restored.Tags, restored.PinnedAt)
+	}
+
+	if summary, err := repo.Undo(); err != nil || summary != "Pin web" {
+		t.Fatalf("Undo() = %q, %v", summary, err)
+	}
+	if summary, err := repo.Undo(); err != nil || summary != "Tag web" {
+		t.Fatalf("Undo() = %q, %v", summary, err)
+	}
+	web = findServer(t, repo, "web")
+	if len(web.Tags) != 0 || !web.PinnedAt.IsZero() {
+		t.Errorf("metadata after undo = tags %v, pinned %v", web.Tags, web.PinnedAt)
+	}
+	if _, err := repo.Undo(); err == nil || !strings.Contains(err.Error(), "nothing to undo") {
+		t.Errorf("Undo() on empty history error = %v", err)
+	}
+}
+
+func TestUndoAddAndBatch(t *testing.T) {
+	repo, configPath, metaPath := newHistoryRepo(t, historyTestConfig)
+
+	err := repo.Batch(func() error {
+		for _, alias := range []string{"cache", "queue"} {
+			if err := repo.AddServer(newTestServer(alias, "")); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertFileContains(t, configPath, "Host cache", "Host queue")
+
+	// The history survives a restart.
+	repo = NewRepository(zap.NewNop().Sugar(), configPath, metaPath)
+	if summary, err := repo.Undo(); err != nil || summary != "Add 2 servers" {
+		t.Fatalf("Undo() = %q, %v", summary, err)
+	}
+	if got := readFile(t, configPath); got != historyTestConfig {
+		t.Errorf("config after undoing batch:\n%s\nwant:\n%s", got, historyTestConfig)
+	}
+	if _, err := repo.Redo(); err != nil {
+		t.Fatal(err)
+	}
+	assertFileContains(t, configPath, "Host cache", "Host queue")
+}
+
+func TestUndoRefusesChangedBlock(t *testing.T) {
+	repo, configPath, _ := newHistoryRepo(t, historyTestConfig)
+
+	web := findServer(t, repo, "web")
+	updated := web
+	updated.User = "admin"
+	if err := repo.UpdateServer(web, updated); err != nil {
+		t.Fatal(err)
+	}
+	edited := strings.Replace(readFile(t, configPath), "User admin", "User root", 1)
+	if err := os.WriteFile(configPath, []byte(edited), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.Undo(); err == nil || !strings.Contains(err.Error(), "web was changed since") {
+		t.Fatalf("Undo() error = %v, want a conflict", err)
+	}
+	if got := readFile(t, configPath); got != edited {
+		t.Errorf("config changed by a refused undo:\n%s", got)
+	}
+}
@@ -29,33 +29,30 @@ import (
 // from non-commented Include directives, returning a flat, de-duplicated list.
 // Main config takes precedence on alias conflicts.
 func (r *Repository) loadAllServers() ([]domain.Server, error) { //nolint:unparam // kept for symmetry and future enhancements
-	mainPath := expandTilde(r.configPath)
-	absMain, err := filepath.Abs(mainPath)
-	if err != nil {
-		absMain = mainPath
-	}
-
-	files := []string{absMain}
-	visited := map[string]struct{}{absMain: {}}
-
-	included, err := r.resolveIncludes(absMain, visited)
-	if err != nil {
-		r.logger.Warnf("failed to resolve includes: %v", err)
-	}
-	files = append(files, included...)
+	files := r.configFiles()
 
 	seen := make(map[string]struct{}, 64)
 	all := make([]domain.Server, 0, 64)
 
 	for i, f := range files {
-		cfg, err := r.decodeConfigAt(f)
+		content, err := r.readConfigFile(f)
+		if err != nil {
+			r.logger.Warnf("failed to read %s: %v", f, err)
+			continue
+		}
+		revision := r.snapshots.remember(content)
+		cfg, err := decodeConfig(content)
 		if err != nil {
 			r.logger.Warnf("failed to decode %s: %v", f, err)
 			continue
 		}
 		isMain := i == 0
+		if r.isHiddenFile(f, isMain) {
+			continue
+		}
 		servers := r.toDomainServersFromConfig(cfg, f, isMain)
 		for _, s := range servers {
+			s.Revision = revision
 			if _, ok := seen[s.Alias]; ok {
 				continue
 			}
@@ -66,10 +63,65 @@ func (r *Repository) loadAllServers() ([]domain.Server, error) { //nolint:unpara
 	return all, nil
 }
 
+// configFiles returns the absolute path of the main config followed by every
+// file it includes, in depth-first order.
+func (r *Repository) configFiles() []string {
+	mainPath := expandTilde(r.configPath)
+	absMain, err := filepath.Abs(mainPath)
+	if err != nil {
+		absMain = mainPath
+	}
+
+	files := []string{absMain}
+	visited := map[string]struct{}{absMain: {}}
+
+	included, err := r.resolveIncludes(absMain, visited)
+	if err != nil {
+		r.logger.Warnf("failed to resolve includes: %v", err)
+	}
+	return append(files, included...)
+}
+
 // resolveIncludes parses a config file for non-commented Include directives,
 // supports multiple patterns per line, globs, tilde-expansion and relative paths.
 // It returns a depth-first ordered list of unique absolute file paths.
 func (r *Repository) resolveIncludes(filePath string, visited map[string]struct{}) ([]string, error) {
+	directives, err := r.includeDirectives(filePath)
+
+	results := make([]string, 0)
+	added := make(map[string]struct{})
+	for _, d := range directives {
+		for _, child := range d.files {
+			if _, ok := visited[child]; ok {
+				continue
+			}
+			visited[child] = struct{}{}
+			if _, ok := added[child]; !ok {
+				results = append(results, child)
+				added[child] = struct{}{}
+			}
+			// Recurse
+			sub, _ := r.resolveIncludes(child, visited)
+			for _, s := range sub {
+				if _, ok := added[s]; !ok {
+					results = append(results, s)
+					added[s] = struct{}{}
+				}
+			}
+		}
+	}
+	return results, err
+}
+
+// includeDirective is an Include line of a config file with the files it matches.
+type includeDirective struct {
+	line  int
+	files []string
+}
+
+// includeDirectives returns the non-commented Include lines of a config file in
+// file order, with their patterns expanded to absolute paths of existing files.
+func (r *Repository) includeDirectives(filePath string) ([]includeDirective, error) {
 	fp := expandTilde(filePath)
 	if !filepath.IsAbs(fp) {
 		if ap, err := filepath.Abs(fp); err == nil {
@@ -80,18 +132,19 @@ func (r *Repository) resolveIncludes(filePath string, visited map[string]struct{
 	f, err := r.fileSystem.Open(fp)
 	if err != nil {
 		// If the including file can't be read, treat as no includes
-		return []string{}, nil
+		return nil, nil
 	}
 	defer func() {
 		_ = f.Close()
 	}()
 
 	baseDir := filepath.Dir(fp)
-	results := make([]string, 0)
-	added := make(map[string]struct{})
+	directives := make([]includeDirective, 0)
 
 	scanner := bufio.NewScanner(f)
+	lineNo := 0
 	for scanner.Scan() {
+		lineNo++
 		line := scanner.Text()
 		line = strings.TrimSpace(line)
 		if line == "" {
@@ -117,8 +170,8 @@ func (r *Repository) resolveIncludes(filePath string, visited map[string]struct{
 		if !strings.EqualFold(fields[0], "Include") {
 			continue
 		}
-		patterns := fields[1:]
-		for _, pat := range patterns {
+		d := includeDirective{line: lineNo}
+		for _, pat := range fields[1:] {
 			p := unquote(strings.TrimSpace(pat))
 			if p == "" {
 				continue
@@ -139,30 +192,16 @@ func (r *Repository) resolveIncludes(filePath string, visited map[string]struct{
 						child = ap
 					}
 				}
-				if _, ok := visited[child]; ok {
-					continue
-				}
-				visited[child] = struct{}{}
-				if _, ok := added[child]; !ok {
-					results = append(results, child)
-					added[child] = struct{}{}
-				}
-				// Recurse
-				sub, _ := r.resolveIncludes(child, visited)
-				for _, s := range sub {
-					if _, ok := added[s]; !ok {
-						results = append(results, s)
-						added[s] = struct{}{}
-					}
-				}
+				d.files = append(d.files, child)
 			}
 		}
+		directives = append(directives, d)
 	}
 
 	if err := scanner.Err(); err != nil {
-		return results, fmt.Errorf("scanner error: %w", err)
+		return directives, fmt.Errorf("scanner error: %w", err)
 	}
-	return results, nil
+	return directives, nil
 }
 
 // decodeConfigAt decodes a single ssh config file at the given absolute path.
@@ -173,7 +212,22 @@ func (r *Repository) decodeConfigAt(path string) (*ssh_config.Config, error) {
 	}
 	defer func() { _ = rc.Close() }()
 
-	return ssh_config.Decode(rc)
+	cfg, err := ssh_config.Decode(rc)
+	if err != nil {
+		return nil, err
+	}
+	restoreNegatedPatterns(cfg)
+	return cfg, nil
+}
+
+// decodeConfig decodes the content of an ssh config file.
+func decodeConfig(content string) (*ssh_config.Config, error) {
+	cfg, err := ssh_config.Decode(strings.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	restoreNegatedPatterns(cfg)
+	return cfg, nil
 }
 
 func expandTilde(p string) string {
@@ -269,3 +323,26 @@ func splitFieldsRespectQuotes(s string) []string {
 	flush()
 	return fields
 }
+
+// targetFile maps a server's SourceFile to the config file to write. An empty
+// SourceFile means the main config. Only the main config and the files it includes
+// are accepted, so lazyssh never writes outside the SSH config.
+func (r *Repository) targetFile(sourceFile string) (string, error) {
+	files := r.configFiles()
+	if sourceFile == "" || sourceFile == r.configPath || sourceFile == files[0] {
+		if r.isReadonlyFile(files[0], true) {
+			return "", fmt.Errorf("%s is read-only", files[0])
+		}
+		return r.configPath, nil
+	}
+	for _, f := range files[1:] {
+		if f != sourceFile {
+			continue
+		}
+		if r.isReadonlyFile(f, false) {
+			return "", fmt.Errorf("%s is read-only", f)
+		}
+		return f, nil
+	}
+	return "", fmt.Errorf("%s is not part of the SSH config", sourceFile)
+}
@@ -0,0 +1,148 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"go.uber.org/zap"
+)
+
+func TestWriteServerToIncludedFile(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config")
+	includeDir := filepath.Join(dir, "config.d")
+	includePath := filepath.Join(includeDir, "work")
+	if err := os.Mkdir(includeDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configPath, []byte("Include config.d/*\n\nHost home\n    HostName 192.168.1.2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(includePath, []byte("Host db\n    HostName 10.0.0.7\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewRepository(zap.NewNop().Sugar(), configPath, filepath.Join(dir, "metadata.json"))
+
+	servers, err := repo.ListServers("db")
+	if err != nil || len(servers) != 1 {
+		t.Fatalf("ListServers() = %+v, %v", servers, err)
+	}
+	db := servers[0]
+	if db.SourceFile != includePath || db.Readonly {
+		t.Fatalf("unexpected origin: source=%q readonly=%t", db.SourceFile, db.Readonly)
+	}
+
+	updated := db
+	updated.User = "postgres"
+	if err := repo.UpdateServer(db, updated); err != nil {
+		t.Fatalf("UpdateServer() error = %v", err)
+	}
+	if err := repo.AddServer(newTestServer("cache", includePath)); err != nil {
+		t.Fatalf("AddServer() error = %v", err)
+	}
+	if err := repo.AddServer(newTestServer("home", includePath)); err == nil {
+		t.Error("AddServer() accepted an alias already defined in the main config")
+	}
+
+	assertFileContains(t, includePath, "User postgres", "Host cache")
+	main, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(main), "postgres") || strings.Contains(string(main), "cache") {
+		t.Errorf("main config should be untouched:\n%s", main)
+	}
+
+	entries, err := os.ReadDir(includeDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("backups must not be written to the include directory, found %d files", len(entries))
+	}
+	backups, err := filepath.Glob(filepath.Join(dir, "config.d_work-*-"+BackupSuffix))
+	if err != nil || len(backups) == 0 {
+		t.Errorf("expected backups of the included file beside the main config, got %v", backups)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "config.d_work.original.backup")); err != nil {
+		t.Errorf("expected original backup of the included file: %v", err)
+	}
+
+	if err := repo.DeleteServer(updated); err != nil {
+		t.Fatalf("DeleteServer() error = %v", err)
+	}
+	data, err := os.ReadFile(includePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "Host db") {
+		t.Errorf("db should be removed from the included file:\n%s", data)
+	}
+}
+
+func TestAddServerOutsideConfig(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config")
+	if err := os.WriteFile(configPath, []byte("Host home\n    HostName 192.168.1.2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewRepository(zap.NewNop().Sugar(), configPath, filepath.Join(dir, "metadata.json"))
+
+	if err := repo.AddServer(newTestServer("stray", filepath.Join(dir, "other"))); err == nil {
+		t.Error("AddServer() wrote to a file that is not part of the SSH config")
+	}
+}
+
+func TestIsBackupOf(t *testing.T) {
+	tests := []struct {
+		file string
+		name string
+		want bool
+	}{
+		{"config-1700000000000-lazyssh.backup", "config", true},
+		{"config.d_work-1700000000000-lazyssh.backup", "config.d_work", true},
+		{"config.d_work-1700000000000-lazyssh.backup", "config", false},
+		{"config-abc-lazyssh.backup", "config", false},
+		{"config--lazyssh.backup", "config", false},
+		{"config.original.backup", "config", false},
+	}
+	for _, tt := range tests {
+		if got := isBackupOf(tt.file, tt.name); got != tt.want {
+			t.Errorf("isBackupOf(%q, %q) = %t, want %t", tt.file, tt.name, got, tt.want)
+		}
+	}
+}
+
+func newTestServer(alias, sourceFile string) domain.Server {
+	return domain.Server{Alias: alias, Host: alias + ".example.com", SourceFile: sourceFile}
+}
+
+func assertFileContains(t *testing.T, path string, want ...string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range want {
+		if !strings.Contains(string(data), w) {
+			t.Errorf("%s missing %q:\n%s", path, w, data)
+		}
+	}
+}
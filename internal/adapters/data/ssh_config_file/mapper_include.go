@@ -25,6 +25,7 @@ import (
 // setting origin metadata (SourceFile, Readonly).
 func (r *Repository) toDomainServersFromConfig(cfg *ssh_config.Config, origin string, isMain bool) []domain.Server {
 	servers := make([]domain.Server, 0, len(cfg.Hosts))
+	readonly := r.isReadonlyFile(origin, isMain)
 	for _, host := range cfg.Hosts {
 
 		aliases := make([]string, 0, len(host.Patterns))
@@ -47,10 +48,11 @@ func (r *Repository) toDomainServersFromConfig(cfg *ssh_config.Config, origin st
 			IdentityFiles: []string{},
 
 			SourceFile: origin,
-			Readonly:   !isMain,
+			Readonly:   readonly,
 		}
 
-		for _, node := range host.Nodes {
+		own, _ := splitMatchNodes(host.Nodes)
+		for _, node := range own {
 			kvNode, ok := node.(*ssh_config.KV)
 			if !ok {
 				continue
@@ -15,10 +15,12 @@
 package ssh_config_file
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/Adembc/lazyssh/internal/core/domain"
@@ -35,6 +37,11 @@ type ServerMetadata struct {
 type metadataManager struct {
 	filePath string
 	logger   *zap.SugaredLogger
+
+	// known is the content last read or written, to tell changes made by
+	// something else from our own.
+	mu    sync.Mutex
+	known []byte
 }
 
 func newMetadataManager(filePath string, logger *zap.SugaredLogger) *metadataManager {
@@ -45,6 +52,7 @@ func (m *metadataManager) loadAll() (map[string]ServerMetadata, error) {
 	metadata := make(map[string]ServerMetadata)
 
 	if _, err := os.Stat(m.filePath); os.IsNotExist(err) {
+		m.remember(nil)
 		return metadata, nil
 	}
 
@@ -52,6 +60,7 @@ func (m *metadataManager) loadAll() (map[string]ServerMetadata, error) {
 	if err != nil {
 		return nil, fmt.Errorf("read metadata '%s': %w", m.filePath, err)
 	}
+	m.remember(data)
 
 	if len(data) == 0 {
 		return metadata, nil
@@ -81,9 +90,27 @@ func (m *metadataManager) saveAll(metadata map[string]ServerMetadata) error {
 		m.logger.Errorw("failed to write metadata file", "path", m.filePath, "error", err)
 		return fmt.Errorf("write metadata '%s': %w", m.filePath, err)
 	}
+	m.remember(data)
 	return nil
 }
 
+func (m *metadataManager) remember(data []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.known = data
+}
+
+// changedOnDisk reports whether the file no longer has the content last read or written.
+func (m *metadataManager) changedOnDisk() bool {
+	data, err := os.ReadFile(m.filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return !bytes.Equal(data, m.known)
+}
+
 func (m *metadataManager) updateServer(server domain.Server, oldAlias string) error {
 	metadata, err := m.loadAll()
 	if err != nil {
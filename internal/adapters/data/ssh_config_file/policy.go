@@ -0,0 +1,87 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+// fileAccess applies the configured policy to a config file. The main config
+// cannot be hidden; without a matching pattern it is writable.
+func (r *Repository) fileAccess(path string, isMain bool) domain.FileAccess {
+	p := r.filePolicy
+	switch {
+	case !isMain && r.matchesAny(p.Hidden, path):
+		return domain.FileHidden
+	case r.matchesAny(p.Readonly, path):
+		return domain.FileReadonly
+	case r.matchesAny(p.Writable, path):
+		return domain.FileWritable
+	case !isMain && p.IncludesReadonly:
+		return domain.FileReadonly
+	default:
+		return domain.FileWritable
+	}
+}
+
+// isReadonlyFile reports whether lazyssh must not write the config file at path:
+// the policy says so, or the file cannot be opened for writing. The main config
+// may not exist yet, so only the policy applies to it.
+func (r *Repository) isReadonlyFile(path string, isMain bool) bool {
+	if r.fileAccess(path, isMain) != domain.FileWritable {
+		return true
+	}
+	if isMain {
+		return false
+	}
+	f, err := r.fileSystem.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return true
+	}
+	_ = f.Close()
+	return false
+}
+
+func (r *Repository) isHiddenFile(path string, isMain bool) bool {
+	return r.fileAccess(path, isMain) == domain.FileHidden
+}
+
+// matchesAny matches path against glob patterns, both as an absolute path and
+// relative to the directory of the main config (e.g. "config.d/*").
+func (r *Repository) matchesAny(patterns []string, path string) bool {
+	if len(patterns) == 0 {
+		return false
+	}
+	rel := ""
+	if base, err := filepath.Abs(filepath.Dir(expandTilde(r.configPath))); err == nil {
+		if p, err := filepath.Rel(base, path); err == nil {
+			rel = p
+		}
+	}
+	for _, pattern := range patterns {
+		pattern = expandTilde(pattern)
+		target := path
+		if !filepath.IsAbs(pattern) {
+			target = rel
+		}
+		if ok, _ := filepath.Match(pattern, target); ok {
+			return true
+		}
+	}
+	return false
+}
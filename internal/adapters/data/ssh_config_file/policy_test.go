@@ -0,0 +1,111 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"go.uber.org/zap"
+)
+
+func TestFileAccess(t *testing.T) {
+	r := &Repository{
+		configPath: "/home/me/.ssh/config",
+		filePolicy: domain.FilePolicy{
+			Writable:         []string{"config.d/personal*"},
+			Readonly:         []string{"/home/me/.ssh/company/*", "config"},
+			Hidden:           []string{"config.d/legacy", "config.d/personal-old"},
+			IncludesReadonly: true,
+		},
+	}
+	tests := []struct {
+		path   string
+		isMain bool
+		want   domain.FileAccess
+	}{
+		{"/home/me/.ssh/config.d/personal", false, domain.FileWritable},
+		{"/home/me/.ssh/company/hosts", false, domain.FileReadonly},
+		{"/home/me/.ssh/config.d/legacy", false, domain.FileHidden},
+		{"/home/me/.ssh/config.d/personal-old", false, domain.FileHidden},
+		{"/home/me/.ssh/config.d/other", false, domain.FileReadonly},
+		{"/home/me/.ssh/config", true, domain.FileReadonly},
+	}
+	for _, tt := range tests {
+		if got := r.fileAccess(tt.path, tt.isMain); got != tt.want {
+			t.Errorf("fileAccess(%q) = %d, want %d", tt.path, got, tt.want)
+		}
+	}
+
+	r.filePolicy = domain.FilePolicy{Hidden: []string{"config"}}
+	if got := r.fileAccess("/home/me/.ssh/config", true); got != domain.FileWritable {
+		t.Errorf("the main config cannot be hidden, got %d", got)
+	}
+}
+
+func TestFilePolicyAppliesToServers(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config")
+	if err := os.Mkdir(filepath.Join(dir, "config.d"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		configPath: "Include config.d/*\n",
+		filepath.Join(dir, "config.d", "company"): "Host gitlab\n    HostName gitlab.corp\n",
+		filepath.Join(dir, "config.d", "mine"):    "Host box\n    HostName 10.0.0.9\n",
+		filepath.Join(dir, "config.d", "old"):     "Host legacy\n    HostName 10.0.0.1\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	repo := NewRepository(zap.NewNop().Sugar(), configPath, filepath.Join(dir, "metadata.json"),
+		WithFilePolicy(domain.FilePolicy{
+			Readonly: []string{"config.d/company"},
+			Hidden:   []string{"config.d/old"},
+		}))
+
+	servers, err := repo.ListServers("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	readonly := map[string]bool{}
+	for _, s := range servers {
+		readonly[s.Alias] = s.Readonly
+	}
+	if len(servers) != 2 || !readonly["gitlab"] || readonly["box"] {
+		t.Errorf("unexpected servers: %+v", readonly)
+	}
+
+	blocks, err := repo.ListConfigBlocks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range blocks {
+		if b.SourceFile == filepath.Join(dir, "config.d", "old") {
+			t.Errorf("ListConfigBlocks() returned a block of a hidden file: %+v", b)
+		}
+	}
+	if len(blocks) != 2 {
+		t.Errorf("ListConfigBlocks() = %d blocks, want 2", len(blocks))
+	}
+
+	if err := repo.AddServer(newTestServer("api", filepath.Join(dir, "config.d", "company"))); err == nil {
+		t.Error("AddServer() wrote to a read-only file")
+	}
+}
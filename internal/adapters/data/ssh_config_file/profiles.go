@@ -0,0 +1,253 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/kevinburke/ssh_config"
+)
+
+// ListProfiles returns the Host blocks whose patterns contain wildcards or negations,
+// from the main config and its includes. A profile's Alias holds its full pattern
+// list (e.g. "* !bastion") and Aliases the individual patterns.
+func (r *Repository) ListProfiles() ([]domain.Server, error) {
+	profiles := make([]domain.Server, 0, 8)
+	for i, f := range r.configFiles() {
+		if r.isHiddenFile(f, i == 0) {
+			continue
+		}
+		content, err := r.readConfigFile(f)
+		if err != nil {
+			r.logger.Warnf("failed to read %s: %v", f, err)
+			continue
+		}
+		revision := r.snapshots.remember(content)
+		cfg, err := decodeConfig(content)
+		if err != nil {
+			r.logger.Warnf("failed to decode %s: %v", f, err)
+			continue
+		}
+		for _, p := range r.toDomainProfilesFromConfig(cfg, f, i == 0) {
+			p.Revision = revision
+			profiles = append(profiles, p)
+		}
+	}
+	return profiles, nil
+}
+
+// AddProfile adds a new pattern Host block to profile.SourceFile, or to the main
+// config when it is empty. ssh uses the first value of each setting, so the block
+// goes before the first "Host *" block, which would otherwise shadow it.
+func (r *Repository) AddProfile(profile domain.Server) error {
+	patterns := strings.Fields(profile.Alias)
+	if len(patterns) == 0 {
+		return fmt.Errorf("profile patterns are required")
+	}
+
+	path, err := r.targetFile(profile.SourceFile)
+	if err != nil {
+		return err
+	}
+	cfg, revision, err := r.loadConfigAt(path)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if r.findHostByPatterns(cfg, patterns) != nil {
+		return fmt.Errorf("profile '%s' already exists", profile.Alias)
+	}
+
+	host := r.createHostFromServer(profile)
+	host.Patterns = toPatterns(patterns)
+	if i := slices.IndexFunc(cfg.Hosts, isCatchAllHost); i >= 0 {
+		host.Nodes = append(host.Nodes, &ssh_config.Empty{})
+		cfg.Hosts = slices.Insert(cfg.Hosts, i, host)
+	} else {
+		cfg.Hosts = append(cfg.Hosts, host)
+	}
+
+	if err := r.saveConfigAt(path, cfg, revision, "add profile "+profile.Alias); err != nil {
+		r.logger.Warnf("Failed to save config while adding profile: %v", err)
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+// UpdateProfile rewrites the settings (and optionally the patterns) of a profile.
+func (r *Repository) UpdateProfile(profile domain.Server, newProfile domain.Server) error {
+	newPatterns := strings.Fields(newProfile.Alias)
+	if len(newPatterns) == 0 {
+		return fmt.Errorf("profile patterns are required")
+	}
+
+	path, err := r.targetFile(profile.SourceFile)
+	if err != nil {
+		return err
+	}
+	if err := r.checkConflict(path, profile.Revision); err != nil {
+		return err
+	}
+	cfg, revision, err := r.loadConfigAt(path)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	host := r.findHostByPatterns(cfg, strings.Fields(profile.Alias))
+	if host == nil {
+		return fmt.Errorf("profile '%s' not found", profile.Alias)
+	}
+	before := hostBlock(host)
+	if profile.Alias != newProfile.Alias {
+		if r.findHostByPatterns(cfg, newPatterns) != nil {
+			return fmt.Errorf("profile '%s' already exists", newProfile.Alias)
+		}
+		host.Patterns = toPatterns(newPatterns)
+	}
+
+	own, trailing := splitMatchNodes(host.Nodes)
+	host.Nodes = own
+	r.updateHostNodes(host, newProfile)
+	host.Nodes = append(host.Nodes, trailing...)
+
+	message := ""
+	if after := hostBlock(host); after != before {
+		message = describeEdit("profile "+profile.Alias, "profile "+newProfile.Alias, before, after)
+	}
+	if err := r.saveConfigAt(path, cfg, revision, message); err != nil {
+		r.logger.Warnf("Failed to save config while updating profile: %v", err)
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+// DeleteProfile removes a profile from the config file it is defined in.
+func (r *Repository) DeleteProfile(profile domain.Server) error {
+	path, err := r.targetFile(profile.SourceFile)
+	if err != nil {
+		return err
+	}
+	if err := r.checkConflict(path, profile.Revision); err != nil {
+		return err
+	}
+	cfg, revision, err := r.loadConfigAt(path)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	host := r.findHostByPatterns(cfg, strings.Fields(profile.Alias))
+	if host == nil {
+		return fmt.Errorf("profile '%s' not found", profile.Alias)
+	}
+	for i, h := range cfg.Hosts {
+		if h != host {
+			continue
+		}
+		if _, trailing := splitMatchNodes(h.Nodes); len(trailing) > 0 && i > 0 {
+			cfg.Hosts[i-1].Nodes = append(cfg.Hosts[i-1].Nodes, trailing...)
+		}
+		cfg.Hosts = append(cfg.Hosts[:i], cfg.Hosts[i+1:]...)
+		break
+	}
+
+	if err := r.saveConfigAt(path, cfg, revision, "delete profile "+profile.Alias); err != nil {
+		r.logger.Warnf("Failed to save config while deleting profile: %v", err)
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+// toDomainProfilesFromConfig is the counterpart of toDomainServersFromConfig for
+// pattern Host blocks. Settings before the first Host line are not a profile.
+func (r *Repository) toDomainProfilesFromConfig(cfg *ssh_config.Config, origin string, isMain bool) []domain.Server {
+	profiles := make([]domain.Server, 0, 4)
+	readonly := r.isReadonlyFile(origin, isMain)
+	for _, host := range cfg.Hosts {
+		if host.Implicit || !isPatternHost(host) {
+			continue
+		}
+
+		patterns := make([]string, 0, len(host.Patterns))
+		for _, p := range host.Patterns {
+			patterns = append(patterns, p.String())
+		}
+		profile := domain.Server{
+			Alias:         strings.Join(patterns, " "),
+			Aliases:       patterns,
+			IdentityFiles: []string{},
+
+			SourceFile: origin,
+			Readonly:   readonly,
+		}
+
+		own, _ := splitMatchNodes(host.Nodes)
+		for _, node := range own {
+			if kv, ok := node.(*ssh_config.KV); ok {
+				r.mapKVToServer(&profile, kv)
+			}
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles
+}
+
+// isPatternHost reports whether a Host block targets a pattern rather than concrete aliases.
+func isPatternHost(host *ssh_config.Host) bool {
+	for _, p := range host.Patterns {
+		if strings.ContainsAny(p.String(), "!*?[]") {
+			return true
+		}
+	}
+	return false
+}
+
+// isCatchAllHost reports whether a Host block has the pattern "*", e.g.
+// "Host *" or "Host * !bastion".
+func isCatchAllHost(host *ssh_config.Host) bool {
+	if host.Implicit {
+		return false
+	}
+	return slices.ContainsFunc(host.Patterns, func(p *ssh_config.Pattern) bool { return p.Str == "*" })
+}
+
+// findHostByPatterns finds the explicit Host block with exactly the given patterns.
+func (r *Repository) findHostByPatterns(cfg *ssh_config.Config, patterns []string) *ssh_config.Host {
+	for _, host := range cfg.Hosts {
+		if host.Implicit || len(host.Patterns) != len(patterns) {
+			continue
+		}
+		same := true
+		for i, p := range host.Patterns {
+			if p.String() != patterns[i] {
+				same = false
+				break
+			}
+		}
+		if same {
+			return host
+		}
+	}
+	return nil
+}
+
+func toPatterns(patterns []string) []*ssh_config.Pattern {
+	out := make([]*ssh_config.Pattern, 0, len(patterns))
+	for _, p := range patterns {
+		out = append(out, &ssh_config.Pattern{Str: p})
+	}
+	return out
+}
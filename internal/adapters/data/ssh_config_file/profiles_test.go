@@ -0,0 +1,97 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"go.uber.org/zap"
+)
+
+func TestToDomainProfilesFromConfig(t *testing.T) {
+	r := &Repository{logger: zap.NewNop().Sugar()}
+	profiles := r.toDomainProfilesFromConfig(decodeTestConfig(t, matchTestConfig), "/tmp/config", true)
+	if len(profiles) != 1 {
+		t.Fatalf("expected one profile, got %+v", profiles)
+	}
+	p := profiles[0]
+	if p.Alias != "* !bastion" || p.ServerAliveInterval != "30" || p.Port != 0 {
+		t.Errorf("unexpected profile: alias=%q interval=%q port=%d", p.Alias, p.ServerAliveInterval, p.Port)
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config")
+	if err := os.WriteFile(configPath, []byte(matchTestConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewRepository(zap.NewNop().Sugar(), configPath, filepath.Join(dir, "metadata.json"))
+
+	profiles, err := repo.ListProfiles()
+	if err != nil || len(profiles) != 1 {
+		t.Fatalf("ListProfiles() = %+v, %v", profiles, err)
+	}
+	updated := profiles[0]
+	updated.Alias = "*.prod !bastion"
+	updated.ProxyJump = "jump"
+	if err := repo.UpdateProfile(profiles[0], updated); err != nil {
+		t.Fatalf("UpdateProfile() error = %v", err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	for _, want := range []string{"Host *.prod !bastion", "ProxyJump jump", "ServerAliveInterval 30", "Match host"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("config missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Port") {
+		t.Errorf("profile without a port should not get a Port line:\n%s", got)
+	}
+}
+
+func TestAddProfileGoesBeforeCatchAll(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config")
+	if err := os.WriteFile(configPath, []byte(matchTestConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewRepository(zap.NewNop().Sugar(), configPath, filepath.Join(dir, "metadata.json"))
+
+	if err := repo.AddProfile(domain.Server{Alias: "*.prod", User: "deploy"}); err != nil {
+		t.Fatalf("AddProfile() error = %v", err)
+	}
+	got := readFile(t, configPath)
+	profile, catchAll := strings.Index(got, "Host *.prod"), strings.Index(got, "Host * !bastion")
+	if profile < 0 || catchAll < 0 || profile > catchAll {
+		t.Errorf("the new profile should come before Host *:\n%s", got)
+	}
+	if !strings.Contains(got, "User deploy\n\nHost * !bastion") {
+		t.Errorf("the new profile is not separated from Host *:\n%s", got)
+	}
+
+	profiles, err := repo.ListProfiles()
+	if err != nil || len(profiles) != 2 {
+		t.Fatalf("ListProfiles() = %+v, %v", profiles, err)
+	}
+}
@@ -16,6 +16,9 @@ package ssh_config_file
 
 import (
 	"fmt"
+	"reflect"
+	"slices"
+	"time"
 
 	"github.com/Adembc/lazyssh/internal/core/domain"
 	"github.com/Adembc/lazyssh/internal/core/ports"
@@ -28,26 +31,111 @@ type Repository struct {
 	configPath      string
 	fileSystem      FileSystem
 	metadataManager *metadataManager
+	history         *historyStore
+	filePolicy      domain.FilePolicy
+	maxBackups      int
+	maxBackupAge    time.Duration
+	maxBackupSize   int64
+	backupDir       string
+	compressBackups bool
+	gitVersioning   bool
 	logger          *zap.SugaredLogger
+
+	// batch collects what the running Batch did; it is nil outside one.
+	batch *batchState
+	// snapshots holds the config files as last listed or written, to detect
+	// changes made outside lazyssh.
+	snapshots fileSnapshots
+}
+
+// batchState records the files backed up in a Batch, the changes it made and
+// the commit messages of its writes.
+type batchState struct {
+	backedUp map[string]bool
+	edits    []hostEdit
+	writes   []configWrite
 }
 
 // NewRepository creates a new SSH config repository.
-func NewRepository(logger *zap.SugaredLogger, configPath, metaDataPath string) ports.ServerRepository {
-	return &Repository{
+func NewRepository(logger *zap.SugaredLogger, configPath, metaDataPath string, opts ...Option) ports.ServerRepository {
+	r := &Repository{
 		logger:          logger,
 		configPath:      configPath,
 		fileSystem:      DefaultFileSystem{},
 		metadataManager: newMetadataManager(metaDataPath, logger),
+		history:         newHistoryStore(metaDataPath, logger),
+		maxBackups:      MaxBackups,
+	}
+	for _, opt := range opts {
+		opt(r)
 	}
+	return r
 }
 
 // NewRepositoryWithFS creates a new SSH config repository with a custom filesystem.
-func NewRepositoryWithFS(logger *zap.SugaredLogger, configPath string, metaDataPath string, fs FileSystem) ports.ServerRepository {
-	return &Repository{
+func NewRepositoryWithFS(logger *zap.SugaredLogger, configPath string, metaDataPath string, fs FileSystem, opts ...Option) ports.ServerRepository {
+	r := &Repository{
 		logger:          logger,
 		configPath:      configPath,
 		fileSystem:      fs,
 		metadataManager: newMetadataManager(metaDataPath, logger),
+		history:         newHistoryStore(metaDataPath, logger),
+		maxBackups:      MaxBackups,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Option configures a Repository.
+type Option func(*Repository)
+
+// WithFilePolicy sets which config files lazyssh may write, only read, or hide.
+func WithFilePolicy(policy domain.FilePolicy) Option {
+	return func(r *Repository) {
+		r.filePolicy = policy
+	}
+}
+
+// WithMaxBackups sets how many rolling backups are kept per config file; 0 disables them.
+// The one-time original backup is always created.
+func WithMaxBackups(n int) Option {
+	return func(r *Repository) {
+		r.maxBackups = n
+	}
+}
+
+// WithBackupRetention also drops rolling backups older than maxAge, and the
+// oldest ones once the backups of a file take more than maxSize bytes. The most
+// recent backup is always kept; zero disables a limit.
+func WithBackupRetention(maxAge time.Duration, maxSize int64) Option {
+	return func(r *Repository) {
+		r.maxBackupAge = maxAge
+		r.maxBackupSize = maxSize
+	}
+}
+
+// WithBackupDir keeps the backups of all config files in dir instead of next to
+// the main config.
+func WithBackupDir(dir string) Option {
+	return func(r *Repository) {
+		r.backupDir = dir
+	}
+}
+
+// WithBackupCompression gzips the rolling backups.
+func WithBackupCompression(compress bool) Option {
+	return func(r *Repository) {
+		r.compressBackups = compress
+	}
+}
+
+// WithGitVersioning commits every config write to the git repository holding
+// the file, with a message describing the change.
+func WithGitVersioning(enabled bool) Option {
+	return func(r *Repository) {
+		r.gitVersioning = enabled
 	}
 }
 
@@ -72,30 +160,53 @@ func (r *Repository) ListServers(query string) ([]domain.Server, error) {
 	return r.filterServers(servers, query), nil
 }
 
-// AddServer adds a new server to the SSH config.
+// AddServer adds a new server to the SSH config. The Host block is appended to
+// server.SourceFile when it names an included file, otherwise to the main config.
 func (r *Repository) AddServer(server domain.Server) error {
-	cfg, err := r.loadConfig()
+	path, err := r.targetFile(server.SourceFile)
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		return err
+	}
+	if err := r.ensureAliasAvailable(server.Alias); err != nil {
+		return err
 	}
 
-	if r.serverExists(cfg, server.Alias) {
-		return fmt.Errorf("server with alias '%s' already exists", server.Alias)
+	cfg, revision, err := r.loadConfigAt(path)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
 	}
 
 	host := r.createHostFromServer(server)
 	cfg.Hosts = append(cfg.Hosts, host)
 
-	if err := r.saveConfig(cfg); err != nil {
+	if err := r.saveConfigAt(path, cfg, revision, "add "+server.Alias); err != nil {
 		r.logger.Warnf("Failed to save config while adding new server: %v", err)
 		return fmt.Errorf("failed to save config: %w", err)
 	}
-	return r.metadataManager.updateServer(server, server.Alias)
+	if err := r.metadataManager.updateServer(server, server.Alias); err != nil {
+		return err
+	}
+	r.record(hostEdit{
+		Action:     "add",
+		File:       path,
+		Index:      len(cfg.Hosts) - 1,
+		AliasAfter: server.Alias,
+		After:      hostBlock(host),
+		MetaAfter:  r.metadataSnapshot(server.Alias),
+	})
+	return nil
 }
 
-// UpdateServer updates an existing server in the SSH config.
+// UpdateServer updates an existing server in the SSH config file it is defined in.
 func (r *Repository) UpdateServer(server domain.Server, newServer domain.Server) error {
-	cfg, err := r.loadConfig()
+	path, err := r.targetFile(server.SourceFile)
+	if err != nil {
+		return err
+	}
+	if err := r.checkConflict(path, server.Revision); err != nil {
+		return err
+	}
+	cfg, revision, err := r.loadConfigAt(path)
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
@@ -104,10 +215,18 @@ func (r *Repository) UpdateServer(server domain.Server, newServer domain.Server)
 	if host == nil {
 		return fmt.Errorf("server with alias '%s' not found", server.Alias)
 	}
+	edit := hostEdit{
+		Action:      "edit",
+		File:        path,
+		AliasBefore: server.Alias,
+		AliasAfter:  newServer.Alias,
+		Before:      hostBlock(host),
+		MetaBefore:  r.metadataSnapshot(server.Alias),
+	}
 
 	if server.Alias != newServer.Alias {
-		if r.serverExists(cfg, newServer.Alias) {
-			return fmt.Errorf("server with alias '%s' already exists", newServer.Alias)
+		if err := r.ensureAliasAvailable(newServer.Alias); err != nil {
+			return err
 		}
 
 		newPatterns := make([]*ssh_config.Pattern, 0, len(host.Patterns))
@@ -123,40 +242,134 @@ func (r *Repository) UpdateServer(server domain.Server, newServer domain.Server)
 
 	}
 
+	// Only touch the Host's own lines; a following Match section stays in place.
+	own, trailing := splitMatchNodes(host.Nodes)
+	host.Nodes = own
 	r.updateHostNodes(host, newServer)
+	host.Nodes = append(host.Nodes, trailing...)
+	edit.After = hostBlock(host)
 
-	if err := r.saveConfig(cfg); err != nil {
+	message := ""
+	if edit.Before != edit.After {
+		message = describeEdit(edit.AliasBefore, edit.AliasAfter, edit.Before, edit.After)
+	}
+	if err := r.saveConfigAt(path, cfg, revision, message); err != nil {
 		r.logger.Warnf("Failed to save config while updating server: %v", err)
 		return fmt.Errorf("failed to save config: %w", err)
 	}
 	// Update metadata; pass old alias to allow inline migration
-	return r.metadataManager.updateServer(newServer, server.Alias)
+	if err := r.metadataManager.updateServer(newServer, server.Alias); err != nil {
+		return err
+	}
+	edit.MetaAfter = r.metadataSnapshot(newServer.Alias)
+	if edit.Before == edit.After {
+		// Only tags changed; there is no block to restore.
+		edit.Action, edit.File, edit.Before, edit.After = "tag", "", "", ""
+		if reflect.DeepEqual(edit.MetaBefore, edit.MetaAfter) {
+			return nil
+		}
+	}
+	r.record(edit)
+	return nil
 }
 
-// DeleteServer removes a server from the SSH config.
+// DeleteServer removes a server from the SSH config file it is defined in.
 func (r *Repository) DeleteServer(server domain.Server) error {
-	cfg, err := r.loadConfig()
+	path, err := r.targetFile(server.SourceFile)
+	if err != nil {
+		return err
+	}
+	if err := r.checkConflict(path, server.Revision); err != nil {
+		return err
+	}
+	cfg, revision, err := r.loadConfigAt(path)
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	initialCount := len(cfg.Hosts)
-	cfg.Hosts = r.removeHostByAlias(cfg.Hosts, server.Alias)
-
-	if len(cfg.Hosts) == initialCount {
+	index := slices.IndexFunc(cfg.Hosts, func(h *ssh_config.Host) bool { return r.hostContainsPattern(h, server.Alias) })
+	if index < 0 {
 		return fmt.Errorf("server with alias '%s' not found", server.Alias)
 	}
+	edit := hostEdit{
+		Action:      "delete",
+		File:        path,
+		Index:       index,
+		AliasBefore: server.Alias,
+		Before:      hostBlock(cfg.Hosts[index]),
+		MetaBefore:  r.metadataSnapshot(server.Alias),
+	}
+	if _, trailing := splitMatchNodes(cfg.Hosts[index].Nodes); index > 0 {
+		edit.Trailing = len(trailing)
+	}
+	cfg.Hosts = r.removeHostByAlias(cfg.Hosts, server.Alias)
 
-	if err := r.saveConfig(cfg); err != nil {
+	if err := r.saveConfigAt(path, cfg, revision, "delete "+server.Alias); err != nil {
 		r.logger.Warnf("Failed to save config while deleting server: %v", err)
 		return fmt.Errorf("failed to save config: %w", err)
 	}
-	return r.metadataManager.deleteServer(server.Alias)
+	if err := r.metadataManager.deleteServer(server.Alias); err != nil {
+		return err
+	}
+	r.record(edit)
+	return nil
+}
+
+// ListConfigFiles returns the main config followed by the files it includes.
+func (r *Repository) ListConfigFiles() ([]domain.ConfigFile, error) {
+	paths := r.configFiles()
+	files := make([]domain.ConfigFile, 0, len(paths))
+	for i, p := range paths {
+		files = append(files, domain.ConfigFile{
+			Path:     p,
+			Main:     i == 0,
+			Readonly: r.isReadonlyFile(p, i == 0),
+			Hidden:   r.isHiddenFile(p, i == 0),
+		})
+	}
+	return files, nil
+}
+
+// Batch runs fn as one change: each config file written during fn is backed up
+// before its first write only, all its changes are undone in one step and, with
+// git versioning, committed together.
+func (r *Repository) Batch(fn func() error) error {
+	if r.batch != nil {
+		return fn()
+	}
+	batch := &batchState{backedUp: make(map[string]bool)}
+	r.batch = batch
+	defer func() {
+		r.batch = nil
+	}()
+	err := fn()
+	if len(batch.edits) > 0 {
+		r.history.push(newHistoryEntry(batch.edits))
+	}
+	if len(batch.writes) > 0 {
+		r.commitWrites(batch.writes)
+	}
+	return err
 }
 
 // SetPinned sets or unsets the pinned status of a server.
 func (r *Repository) SetPinned(alias string, pinned bool) error {
-	return r.metadataManager.setPinned(alias, pinned)
+	before := r.metadataSnapshot(alias)
+	if err := r.metadataManager.setPinned(alias, pinned); err != nil {
+		return err
+	}
+	action := "pin"
+	if !pinned {
+		action = "unpin"
+	}
+	r.record(hostEdit{
+		Action:      action,
+		AliasBefore: alias,
+		AliasAfter:  alias,
+		MetaBefore:  before,
+		MetaAfter:   r.metadataSnapshot(alias),
+	})
+	return nil
 }
 
 // RecordSSH increments the SSH access count and updates the last seen timestamp for a server.
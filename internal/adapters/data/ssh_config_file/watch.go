@@ -0,0 +1,280 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long Watch waits for a burst of file events to settle,
+// since editors and tools often write a file in several steps.
+const watchDebounce = 300 * time.Millisecond
+
+// maxListedContents bounds the listed config contents kept to overwrite a
+// conflicting change with.
+const maxListedContents = 32
+
+// fileSnapshots remembers what lazyssh knows of each config file: the content
+// it last wrote or accepted, recently listed contents by revision, and the
+// revision a conflicting write was based on.
+type fileSnapshots struct {
+	mu        sync.Mutex
+	written   map[string]string // path -> content
+	listed    map[string]string // revision -> content
+	order     []string          // revisions in listed, oldest first
+	conflicts map[string]string // path -> revision
+}
+
+// setWritten records content as written or accepted by lazyssh for path.
+func (s *fileSnapshots) setWritten(path, content string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.written == nil {
+		s.written = make(map[string]string)
+	}
+	s.written[snapshotKey(path)] = content
+}
+
+func (s *fileSnapshots) getWritten(path string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	content, ok := s.written[snapshotKey(path)]
+	return content, ok
+}
+
+// remember keeps a listed content under its revision and returns the revision.
+func (s *fileSnapshots) remember(content string) string {
+	revision := configRevision(content)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listed == nil {
+		s.listed = make(map[string]string)
+	}
+	if _, ok := s.listed[revision]; ok {
+		return revision
+	}
+	s.listed[revision] = content
+	s.order = append(s.order, revision)
+	if len(s.order) > maxListedContents {
+		delete(s.listed, s.order[0])
+		s.order = s.order[1:]
+	}
+	return revision
+}
+
+func (s *fileSnapshots) setConflict(path, revision string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conflicts == nil {
+		s.conflicts = make(map[string]string)
+	}
+	s.conflicts[snapshotKey(path)] = revision
+}
+
+// takeConflict returns and forgets the content the last conflicting write of
+// path was based on, if it is still known.
+func (s *fileSnapshots) takeConflict(path string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := snapshotKey(path)
+	revision, ok := s.conflicts[key]
+	if !ok {
+		return "", false
+	}
+	delete(s.conflicts, key)
+	content, ok := s.listed[revision]
+	return content, ok
+}
+
+func snapshotKey(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+// configRevision identifies a config file content.
+func configRevision(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// readConfigFile returns the content of the config file at path; a missing
+// file reads as empty.
+func (r *Repository) readConfigFile(path string) (string, error) {
+	file, err := r.fileSystem.Open(path)
+	if err != nil {
+		if r.fileSystem.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	defer func() { _ = file.Close() }()
+	data, err := io.ReadAll(file)
+	return string(data), err
+}
+
+// checkConflict fails with a ConfigConflictError when the config file at path
+// no longer has the content of revision, the one an edit started from, and
+// was changed by something other than lazyssh. An empty revision is not checked.
+func (r *Repository) checkConflict(path, revision string) error {
+	if revision == "" {
+		return nil
+	}
+	current, err := r.readConfigFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to check config file: %w", err)
+	}
+	if configRevision(current) == revision {
+		return nil
+	}
+	if written, ok := r.snapshots.getWritten(path); ok && written == current {
+		// Only lazyssh changed the file since, e.g. an earlier step of a batch.
+		return nil
+	}
+	r.snapshots.setConflict(path, revision)
+	return &domain.ConfigConflictError{Path: path}
+}
+
+// ResolveConflict lets the next write of the config file at path go ahead after
+// a ConfigConflictError: ConflictMerge accepts the file as it is now, so the
+// change is applied on top of it; ConflictOverwrite first puts back the content
+// the edit started from, after backing up the current one.
+func (r *Repository) ResolveConflict(path string, resolution domain.ConflictResolution) error {
+	path, err := r.targetFile(path)
+	if err != nil {
+		return err
+	}
+	base, known := r.snapshots.takeConflict(path)
+	current, err := r.acceptCurrent(path)
+	if err != nil {
+		return err
+	}
+	if resolution != domain.ConflictOverwrite || !known || base == current {
+		return nil
+	}
+	r.logger.Infof("Discarding changes made outside lazyssh to %s", path)
+	// The content goes back to what was last committed, so there is nothing to version.
+	return r.replaceConfigAt(path, base, "", "")
+}
+
+// acceptCurrent takes the config file at path as it is now on disk as written
+// by lazyssh, so the next write does not report a conflict, and returns its content.
+func (r *Repository) acceptCurrent(path string) (string, error) {
+	current, err := r.readConfigFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read config file: %w", err)
+	}
+	r.snapshots.setWritten(path, current)
+	return current, nil
+}
+
+// Watch calls onChange when the config files, the files they include or the
+// metadata change on disk other than through this repository, until stop is
+// closed. The directories holding them are watched, so files replaced by a
+// rename are followed.
+func (r *Repository) Watch(stop <-chan struct{}, onChange func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start file watcher: %w", err)
+	}
+	defer func() { _ = watcher.Close() }()
+
+	files := r.watchFiles(watcher)
+	seen := make(map[string]string, len(files))
+	for _, f := range files {
+		r.changedSince(f, seen)
+	}
+	timer := time.NewTimer(watchDebounce)
+	timer.Stop()
+	defer timer.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op != fsnotify.Chmod {
+				timer.Reset(watchDebounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			r.logger.Warnf("file watcher: %v", err)
+		case <-timer.C:
+			previous := files
+			files = r.watchFiles(watcher)
+			changed := !slices.Equal(files, previous) || r.metadataManager.changedOnDisk()
+			for _, f := range files {
+				changed = r.changedSince(f, seen) || changed
+			}
+			if changed {
+				onChange()
+			}
+		}
+	}
+}
+
+// changedSince reports whether the config file at path differs from its content
+// in seen, other than by a write of lazyssh, and records the current content.
+func (r *Repository) changedSince(path string, seen map[string]string) bool {
+	current, err := r.readConfigFile(path)
+	if err != nil {
+		return false
+	}
+	previous, ok := seen[path]
+	seen[path] = current
+	if !ok || previous == current {
+		return false
+	}
+	written, ok := r.snapshots.getWritten(path)
+	return !ok || written != current
+}
+
+// watchFiles returns the current config files and watches the directories
+// holding them, the targets of symlinked ones and the metadata, adding those
+// that are not watched yet, e.g. the metadata directory once it is created.
+func (r *Repository) watchFiles(watcher *fsnotify.Watcher) []string {
+	files := r.configFiles()
+	dirs := make([]string, 0, len(files)+1)
+	for _, f := range append(slices.Clone(files), r.metadataManager.filePath) {
+		dirs = append(dirs, filepath.Dir(f), filepath.Dir(resolvePath(f)))
+	}
+	watched := watcher.WatchList()
+	for _, dir := range dirs {
+		if slices.Contains(watched, dir) {
+			continue
+		}
+		if err := watcher.Add(dir); err != nil {
+			r.logger.Debugf("not watching %s: %v", dir, err)
+			continue
+		}
+		watched = append(watched, dir)
+	}
+	return files
+}
@@ -0,0 +1,195 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ssh_config_file
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"go.uber.org/zap"
+)
+
+const externalEdit = "\nHost cache\n    HostName 10.0.0.3\n"
+
+func TestConflictingWrite(t *testing.T) {
+	tests := []struct {
+		name         string
+		resolution   domain.ConflictResolution
+		keepExternal bool
+	}{
+		{"merge", domain.ConflictMerge, true},
+		{"overwrite", domain.ConflictOverwrite, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, configPath, _ := newHistoryRepo(t, historyTestConfig)
+			web := findServer(t, repo, "web")
+
+			// Another tool appends a host after lazyssh listed the file.
+			external := historyTestConfig + externalEdit
+			if err := os.WriteFile(configPath, []byte(external), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			// A background refresh while the edit is open does not move its base.
+			if _, err := repo.ListServers(""); err != nil {
+				t.Fatal(err)
+			}
+			updated := web
+			updated.Port = 2222
+			update := func() error { return repo.UpdateServer(web, updated) }
+
+			var conflict *domain.ConfigConflictError
+			if err := update(); !errors.As(err, &conflict) || conflict.Path != configPath {
+				t.Fatalf("UpdateServer() error = %v, want a conflict on %s", err, configPath)
+			}
+			if got := readFile(t, configPath); got != external {
+				t.Fatalf("the conflicting write replaced the file:\n%s", got)
+			}
+
+			err := repo.Batch(func() error {
+				if err := repo.ResolveConflict(configPath, tt.resolution); err != nil {
+					return err
+				}
+				return update()
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := readFile(t, configPath)
+			if !strings.Contains(got, "Port 2222") || strings.Contains(got, "Host cache") != tt.keepExternal {
+				t.Errorf("config after %s:\n%s", tt.name, got)
+			}
+
+			backups, err := repo.ListBackups()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(backups) < 2 || readFile(t, backups[0].Path) != external {
+				t.Errorf("the outside change was not backed up: %+v", backups)
+			}
+		})
+	}
+}
+
+func TestOwnWritesAreNotConflicts(t *testing.T) {
+	repo, _, _ := newHistoryRepo(t, historyTestConfig)
+	web, db := findServer(t, repo, "web"), findServer(t, repo, "db")
+
+	updated := web
+	updated.Port = 2222
+	if err := repo.UpdateServer(web, updated); err != nil {
+		t.Fatal(err)
+	}
+	// db was listed before lazyssh itself rewrote the file.
+	if err := repo.DeleteServer(db); err != nil {
+		t.Errorf("DeleteServer() after an own write = %v", err)
+	}
+}
+
+// racingFileSystem writes content to path when the first file is opened for
+// writing, like another tool saving the config after lazyssh loaded it but
+// before lazyssh replaced it.
+type racingFileSystem struct {
+	FileSystem
+	path    string
+	content string
+	done    bool
+}
+
+func (fs *racingFileSystem) OpenFile(path string, flag int, perms os.FileMode) (*os.File, error) {
+	if !fs.done {
+		fs.done = true
+		if err := os.WriteFile(fs.path, []byte(fs.content), 0o600); err != nil {
+			return nil, err
+		}
+	}
+	return fs.FileSystem.OpenFile(path, flag, perms)
+}
+
+func TestOutsideChangeDuringWrite(t *testing.T) {
+	repo, configPath, _ := newHistoryRepo(t, historyTestConfig)
+	external := historyTestConfig + externalEdit
+	r := repo.(*Repository)
+	r.fileSystem = &racingFileSystem{FileSystem: r.fileSystem, path: configPath, content: external}
+
+	err := repo.AddServer(domain.Server{Alias: "new", Host: "10.0.0.9"})
+	var conflict *domain.ConfigConflictError
+	if !errors.As(err, &conflict) || conflict.Path != configPath {
+		t.Fatalf("AddServer() error = %v, want a conflict on %s", err, configPath)
+	}
+	if got := readFile(t, configPath); got != external {
+		t.Fatalf("the write replaced the outside change:\n%s", got)
+	}
+
+	if err := repo.ResolveConflict(configPath, domain.ConflictMerge); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.AddServer(domain.Server{Alias: "new", Host: "10.0.0.9"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, configPath); !strings.Contains(got, "Host cache") || !strings.Contains(got, "Host new") {
+		t.Errorf("config after merge:\n%s", got)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config")
+	if err := os.WriteFile(configPath, []byte(historyTestConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewRepository(zap.NewNop().Sugar(), configPath, filepath.Join(dir, "lazyssh", "metadata.json"))
+	findServer(t, repo, "web")
+
+	changes := make(chan struct{}, 8)
+	stop := make(chan struct{})
+	done := make(chan error, 1)
+	go func() { done <- repo.Watch(stop, func() { changes <- struct{}{} }) }()
+	defer func() {
+		close(stop)
+		if err := <-done; err != nil {
+			t.Error(err)
+		}
+	}()
+	time.Sleep(100 * time.Millisecond) // let the watcher start
+
+	// lazyssh's own writes, with their backups and metadata, are not reported.
+	web := findServer(t, repo, "web")
+	updated := web
+	updated.Port = 2222
+	updated.Tags = []string{"prod"}
+	if err := repo.UpdateServer(web, updated); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-changes:
+		t.Fatal("Watch() reported lazyssh's own write")
+	case <-time.After(3 * watchDebounce):
+	}
+
+	if err := os.WriteFile(configPath, []byte(readFile(t, configPath)+externalEdit), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch() missed an outside change")
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// AgentView lists the identities loaded in ssh-agent and lets the user load and
+// unload keys.
+type AgentView struct {
+	*tview.Flex
+	list        *tview.List
+	details     *tview.TextView
+	keys        []domain.AgentKey
+	onAdd       func()
+	onRemove    func(domain.AgentKey)
+	onRemoveAll func()
+	onRefresh   func()
+	onClose     func()
+}
+
+func NewAgentView(header *AppHeader) *AgentView {
+	v := &AgentView{
+		Flex:    tview.NewFlex().SetDirection(tview.FlexRow),
+		list:    tview.NewList(),
+		details: tview.NewTextView(),
+	}
+	v.build(header)
+	return v
+}
+
+func (v *AgentView) build(header *AppHeader) {
+	v.list.ShowSecondaryText(false)
+	v.list.SetBorder(true).
+		SetTitle(" ssh-agent ").
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+	v.list.
+		SetSelectedBackgroundColor(tcell.Color24).
+		SetSelectedTextColor(tcell.Color255).
+		SetHighlightFullLine(true)
+	v.list.SetChangedFunc(func(index int, _ string, _ string, _ rune) {
+		v.showKey(index)
+	})
+
+	v.details.SetDynamicColors(true).
+		SetWrap(true).
+		SetBorder(true).
+		SetTitle(" Details ").
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+
+	hint := tview.NewTextView().SetDynamicColors(true)
+	hint.SetBackgroundColor(tcell.Color235)
+	hint.SetTextAlign(tview.AlignCenter)
+	hint.SetText("[white]↑↓[-] Navigate  • [white]a[-] Add key  • [white]d[-] Remove  • [white]D[-] Remove all  • [white]r[-] Refresh  • [white]Esc[-] Back")
+
+	content := tview.NewFlex().SetDirection(tview.FlexColumn).
+		AddItem(v.list, 0, 3, true).
+		AddItem(v.details, 0, 2, false)
+
+	v.Flex.AddItem(header, 2, 0, false).
+		AddItem(content, 0, 1, true).
+		AddItem(hint, 1, 0, false)
+
+	v.Flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			if v.onClose != nil {
+				v.onClose()
+			}
+			return nil
+		}
+		switch event.Rune() {
+		case 'q':
+			if v.onClose != nil {
+				v.onClose()
+			}
+			return nil
+		case 'a':
+			if v.onAdd != nil {
+				v.onAdd()
+			}
+			return nil
+		case 'd':
+			i := v.list.GetCurrentItem()
+			if i >= 0 && i < len(v.keys) && v.onRemove != nil {
+				v.onRemove(v.keys[i])
+			}
+			return nil
+		case 'D':
+			if len(v.keys) > 0 && v.onRemoveAll != nil {
+				v.onRemoveAll()
+			}
+			return nil
+		case 'r':
+			if v.onRefresh != nil {
+				v.onRefresh()
+			}
+			return nil
+		}
+		return event
+	})
+}
+
+// SetKeys shows the agent's identities, or err when the agent cannot be reached.
+func (v *AgentView) SetKeys(keys []domain.AgentKey, err error) *AgentView {
+	v.keys = keys
+	v.list.Clear()
+	if err != nil {
+		v.details.SetText(fmt.Sprintf("[#FF6B6B]%s[-]\n\nStart one with [white]eval \"$(ssh-agent)\"[-] before running lazyssh.", tview.Escape(err.Error())))
+		return v
+	}
+	for _, k := range keys {
+		name := k.Comment
+		if name == "" {
+			name = k.Fingerprint
+		}
+		v.list.AddItem(fmt.Sprintf("[white::b]%s[-::-] [#888888]%s %d[-]", tview.Escape(name), k.Type, k.Bits), "", 0, nil)
+	}
+	if len(keys) == 0 {
+		v.details.SetText("The agent has no identities.\n\nPress [white]a[-] to add a key.")
+		return v
+	}
+	v.list.SetCurrentItem(0)
+	v.showKey(0)
+	return v
+}
+
+// ShowError appends an error from the last agent operation to the details panel.
+func (v *AgentView) ShowError(msg string) *AgentView {
+	v.details.SetText(v.details.GetText(false) + "\n[#FF6B6B]" + tview.Escape(msg) + "[-]\n")
+	return v
+}
+
+func (v *AgentView) OnAdd(fn func()) *AgentView {
+	v.onAdd = fn
+	return v
+}
+
+func (v *AgentView) OnRemove(fn func(domain.AgentKey)) *AgentView {
+	v.onRemove = fn
+	return v
+}
+
+func (v *AgentView) OnRemoveAll(fn func()) *AgentView {
+	v.onRemoveAll = fn
+	return v
+}
+
+func (v *AgentView) OnRefresh(fn func()) *AgentView {
+	v.onRefresh = fn
+	return v
+}
+
+func (v *AgentView) OnClose(fn func()) *AgentView {
+	v.onClose = fn
+	return v
+}
+
+func (v *AgentView) showKey(index int) {
+	if index < 0 || index >= len(v.keys) {
+		return
+	}
+	k := v.keys[index]
+	v.details.SetText(fmt.Sprintf("[::b]%s[-]\n\n  Type: [white]%s[-]\n  Bits: [white]%d[-]\n  Fingerprint: [white]%s[-]\n",
+		tview.Escape(k.Comment), k.Type, k.Bits, k.Fingerprint))
+	v.details.ScrollToBeginning()
+}
+
+// AgentAddRequest is what the add-key form asks to load into the agent.
+type AgentAddRequest struct {
+	Path     string
+	Lifetime time.Duration
+	Confirm  bool
+}
+
+// NewAgentAddForm asks for a key file, a lifetime and whether each use must be confirmed.
+func NewAgentAddForm(keys []string, onSubmit func(AgentAddRequest), onCancel func()) *tview.Form {
+	form := tview.NewForm()
+	form.SetBorder(true).
+		SetTitle(" Add key to ssh-agent ").
+		SetTitleAlign(tview.AlignCenter)
+
+	path := ""
+	if len(keys) > 0 {
+		path = keys[0]
+	}
+	form.AddInputField("Key file:", path, 50, nil, nil)
+	if field, ok := form.GetFormItem(0).(*tview.InputField); ok {
+		field.SetAutocompleteFunc(func(current string) []string {
+			var matches []string
+			for _, k := range keys {
+				if strings.Contains(k, current) {
+					matches = append(matches, k)
+				}
+			}
+			return matches
+		})
+	}
+	form.AddInputField("Lifetime:", "", 12, nil, nil)
+	if field, ok := form.GetFormItem(1).(*tview.InputField); ok {
+		field.SetPlaceholder("e.g. 1h, 30m")
+	}
+	form.AddCheckbox("Confirm each use:", false, nil)
+
+	form.AddButton("Add", func() {
+		req := AgentAddRequest{
+			Path:    strings.TrimSpace(form.GetFormItem(0).(*tview.InputField).GetText()),
+			Confirm: form.GetFormItem(2).(*tview.Checkbox).IsChecked(),
+		}
+		lifetime, err := parseLifetime(form.GetFormItem(1).(*tview.InputField).GetText())
+		if err != nil || req.Path == "" {
+			form.SetTitle(" Add key to ssh-agent — enter a key file and a lifetime like 1h or 90s ")
+			return
+		}
+		req.Lifetime = lifetime
+		onSubmit(req)
+	})
+	form.AddButton("Cancel", onCancel)
+	form.SetCancelFunc(onCancel)
+	return form
+}
+
+// parseLifetime accepts Go durations ("1h30m") or plain seconds like ssh-add -t.
+func parseLifetime(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	if secs, err := strconv.Atoi(s); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < time.Second {
+		return 0, fmt.Errorf("invalid lifetime %q", s)
+	}
+	return d, nil
+}
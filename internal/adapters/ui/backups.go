@@ -0,0 +1,232 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/core/ports"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// BackupsView lists the config backups lazyssh took, shows what changed since
+// the selected one and restores it.
+type BackupsView struct {
+	*tview.Flex
+	app     *tview.Application
+	service ports.ServerService
+
+	table  *tview.Table
+	diff   *tview.TextView
+	status *tview.TextView
+
+	backups   []domain.Backup
+	onRestore func(domain.Backup)
+	onClose   func()
+}
+
+func NewBackupsView(header *AppHeader, app *tview.Application, ss ports.ServerService) *BackupsView {
+	v := &BackupsView{
+		Flex:    tview.NewFlex().SetDirection(tview.FlexRow),
+		app:     app,
+		service: ss,
+		table:   tview.NewTable(),
+		diff:    tview.NewTextView(),
+		status:  tview.NewTextView(),
+	}
+	v.build(header)
+	return v
+}
+
+func (v *BackupsView) build(header *AppHeader) {
+	v.table.SetBorder(true).
+		SetTitle(" Backups ").
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+	v.table.SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetSelectedStyle(tcell.StyleDefault.Background(tcell.Color24).Foreground(tcell.Color255))
+	v.table.SetSelectionChangedFunc(func(row, _ int) {
+		v.showDiff(row)
+	})
+
+	v.diff.SetDynamicColors(true).
+		SetWrap(false).
+		SetBorder(true).
+		SetTitle(" Changes since backup ").
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+
+	v.status.SetDynamicColors(true)
+	v.status.SetBackgroundColor(tcell.Color235)
+	v.status.SetTextAlign(tview.AlignCenter)
+	v.setHint()
+
+	content := tview.NewFlex().SetDirection(tview.FlexColumn).
+		AddItem(v.table, 0, 2, true).
+		AddItem(v.diff, 0, 3, false)
+	v.Flex.AddItem(header, 2, 0, false).
+		AddItem(content, 0, 1, true).
+		AddItem(v.status, 1, 0, false)
+
+	v.Flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			if v.onClose != nil {
+				v.onClose()
+			}
+			return nil
+		case tcell.KeyTab:
+			if v.diff.HasFocus() {
+				v.app.SetFocus(v.table)
+			} else {
+				v.app.SetFocus(v.diff)
+			}
+			return nil
+		default:
+		}
+		switch event.Rune() {
+		case 'q':
+			if v.onClose != nil {
+				v.onClose()
+			}
+			return nil
+		case 'r':
+			v.Load("")
+			return nil
+		case 'R':
+			row, _ := v.table.GetSelection()
+			if v.onRestore != nil && row >= 1 && row <= len(v.backups) {
+				v.onRestore(v.backups[row-1])
+			}
+			return nil
+		}
+		return event
+	})
+}
+
+func (v *BackupsView) OnRestore(fn func(domain.Backup)) *BackupsView {
+	v.onRestore = fn
+	return v
+}
+
+func (v *BackupsView) OnClose(fn func()) *BackupsView {
+	v.onClose = fn
+	return v
+}
+
+// ShowError displays err in the hint bar until the next action.
+func (v *BackupsView) ShowError(err error) *BackupsView {
+	v.status.SetText("[#FF6B6B]" + tview.Escape(err.Error()) + "[-]")
+	return v
+}
+
+// ShowMessage displays msg in the hint bar until the next action.
+func (v *BackupsView) ShowMessage(msg string) *BackupsView {
+	v.status.SetText("[#A0FFA0]" + tview.Escape(msg) + "[-]")
+	return v
+}
+
+// Load lists the backups and selects the one at path, if given.
+func (v *BackupsView) Load(path string) *BackupsView {
+	if row, _ := v.table.GetSelection(); path == "" && row >= 1 && row <= len(v.backups) {
+		path = v.backups[row-1].Path
+	}
+	backups, err := v.service.ListBackups()
+	if err != nil {
+		v.ShowError(err)
+	} else {
+		v.setHint()
+	}
+	v.backups = backups
+
+	v.table.Clear()
+	for i, h := range []string{"Config", "Taken", "", "Size"} {
+		v.table.SetCell(0, i, tview.NewTableCell("[::b]"+h).SetSelectable(false).SetTextColor(tcell.Color250))
+	}
+	if len(backups) == 0 {
+		v.table.SetCell(1, 0, tview.NewTableCell("").SetSelectable(false))
+		v.diff.SetText("No backups yet.\n\nlazyssh backs up a config file before each change it makes.")
+		return v
+	}
+	row := 1
+	for i, b := range backups {
+		r := i + 1
+		if b.Path == path {
+			row = r
+		}
+		taken := b.CreatedAt.Format("2006-01-02 15:04:05")
+		age := "[#888888]" + humanizeDuration(b.CreatedAt) + "[-]"
+		if b.Original {
+			age = "[#FFCC66]original[-]"
+		}
+		v.table.SetCell(r, 0, tview.NewTableCell(tview.Escape(displayConfigPath(b.ConfigFile))).SetExpansion(1))
+		v.table.SetCell(r, 1, tview.NewTableCell(taken))
+		v.table.SetCell(r, 2, tview.NewTableCell(age))
+		v.table.SetCell(r, 3, tview.NewTableCell(formatSize(b.Size)).SetAlign(tview.AlignRight))
+	}
+	v.table.Select(row, 0)
+	v.showDiff(row)
+	return v
+}
+
+func (v *BackupsView) showDiff(row int) {
+	if row < 1 || row > len(v.backups) {
+		return
+	}
+	b := v.backups[row-1]
+	v.diff.SetTitle(" Changes since " + b.Name() + " ")
+	diff, err := v.service.DiffBackup(b)
+	switch {
+	case err != nil:
+		v.diff.SetText("[#FF6B6B]" + tview.Escape(err.Error()) + "[-]")
+	case diff == "":
+		v.diff.SetText("[#A0FFA0]" + tview.Escape(displayConfigPath(b.ConfigFile)) + " is the same as this backup.[-]")
+	default:
+		v.diff.SetText(colorizeDiff(diff))
+	}
+	v.diff.ScrollToBeginning()
+}
+
+func (v *BackupsView) setHint() {
+	v.status.SetText("[white]↑↓[-] Navigate  • [white]Tab[-] Scroll diff  • [white]R[-] Restore  • [white]r[-] Refresh  • [white]Esc[-] Back")
+}
+
+// colorizeDiff colors the lines of a unified diff for a TextView.
+func colorizeDiff(diff string) string {
+	lines := strings.Split(strings.TrimSuffix(diff, "\n"), "\n")
+	var b strings.Builder
+	for _, line := range lines {
+		escaped := tview.Escape(line)
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			b.WriteString("[::b]" + escaped + "[::-]")
+		case strings.HasPrefix(line, "@@"):
+			b.WriteString("[#66CCFF]" + escaped + "[-]")
+		case strings.HasPrefix(line, "+"):
+			b.WriteString("[#A0FFA0]" + escaped + "[-]")
+		case strings.HasPrefix(line, "-"):
+			b.WriteString("[#FF6B6B]" + escaped + "[-]")
+		default:
+			b.WriteString(escaped)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
@@ -0,0 +1,284 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// bulkPingConcurrency bounds the servers pinged at the same time by a bulk ping.
+const bulkPingConcurrency = 8
+
+// bulkField is a setting that can be set on all marked servers at once.
+type bulkField struct {
+	name string
+	set  func(s *domain.Server, value string) error
+}
+
+func stringField(name string, field func(*domain.Server) *string) bulkField {
+	return bulkField{name: name, set: func(s *domain.Server, value string) error {
+		*field(s) = value
+		return nil
+	}}
+}
+
+var bulkFields = []bulkField{
+	stringField("User", func(s *domain.Server) *string { return &s.User }),
+	{name: "Port", set: func(s *domain.Server, value string) error {
+		if value == "" {
+			s.Port = 0
+			return nil
+		}
+		port, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("port must be a number")
+		}
+		s.Port = port
+		return nil
+	}},
+	stringField("ProxyJump", func(s *domain.Server) *string { return &s.ProxyJump }),
+	{name: "IdentityFile", set: func(s *domain.Server, value string) error {
+		s.IdentityFiles = splitCommaList(value)
+		return nil
+	}},
+	stringField("IdentitiesOnly", func(s *domain.Server) *string { return &s.IdentitiesOnly }),
+	stringField("ForwardAgent", func(s *domain.Server) *string { return &s.ForwardAgent }),
+	stringField("ServerAliveInterval", func(s *domain.Server) *string { return &s.ServerAliveInterval }),
+	stringField("ControlMaster", func(s *domain.Server) *string { return &s.ControlMaster }),
+	stringField("ControlPath", func(s *domain.Server) *string { return &s.ControlPath }),
+	stringField("ControlPersist", func(s *domain.Server) *string { return &s.ControlPersist }),
+	stringField("StrictHostKeyChecking", func(s *domain.Server) *string { return &s.StrictHostKeyChecking }),
+	stringField("UserKnownHostsFile", func(s *domain.Server) *string { return &s.UserKnownHostsFile }),
+	stringField("LogLevel", func(s *domain.Server) *string { return &s.LogLevel }),
+}
+
+// writableMarked returns the marked servers that may be changed and the number
+// of read-only ones left out.
+func (t *tui) writableMarked() ([]domain.Server, int) {
+	marked := t.serverList.MarkedServers()
+	writable := make([]domain.Server, 0, len(marked))
+	for _, s := range marked {
+		if !s.Readonly {
+			writable = append(writable, s)
+		}
+	}
+	return writable, len(marked) - len(writable)
+}
+
+// showBulkResult reports the outcome of a bulk action in the status bar.
+func (t *tui) showBulkResult(done string, skipped int, err error) {
+	if skipped > 0 {
+		done += fmt.Sprintf(" (%d read-only skipped)", skipped)
+	}
+	if err != nil {
+		t.showStatusTempColor(done+"; failed: "+strings.ReplaceAll(err.Error(), "\n", "; "), "#FF6B6B")
+		return
+	}
+	t.showStatusTemp(done)
+}
+
+func (t *tui) handleBulkTags() {
+	servers, skipped := t.writableMarked()
+	if len(servers) == 0 {
+		t.showStatusTempColor("All marked servers are read-only", "#FFCC66")
+		return
+	}
+	form := tview.NewForm()
+	form.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Tags of %d servers ", len(servers))).
+		SetTitleAlign(tview.AlignCenter)
+	form.AddInputField("Add tags (comma):", "", 40, nil, nil)
+	form.AddInputField("Remove tags (comma):", "", 40, nil, nil)
+	form.AddButton("Save", func() {
+		add := splitCommaList(form.GetFormItem(0).(*tview.InputField).GetText())
+		remove := splitCommaList(form.GetFormItem(1).(*tview.InputField).GetText())
+		err := t.serverService.UpdateServers(servers, func(s *domain.Server) {
+			s.Tags = slices.DeleteFunc(s.Tags, func(tag string) bool { return slices.Contains(remove, tag) })
+			for _, tag := range add {
+				if !slices.Contains(s.Tags, tag) {
+					s.Tags = append(s.Tags, tag)
+				}
+			}
+		})
+		t.refreshServerList()
+		t.returnToMain()
+		t.showBulkResult(fmt.Sprintf("Tags updated on %d servers", len(servers)), skipped, err)
+	})
+	form.AddButton("Cancel", t.returnToMain)
+	form.SetCancelFunc(t.returnToMain)
+	t.app.SetRoot(form, true)
+	t.app.SetFocus(form)
+}
+
+func (t *tui) handleBulkSetField() {
+	servers, skipped := t.writableMarked()
+	if len(servers) == 0 {
+		t.showStatusTempColor("All marked servers are read-only", "#FFCC66")
+		return
+	}
+	names := make([]string, 0, len(bulkFields))
+	for _, f := range bulkFields {
+		names = append(names, f.name)
+	}
+	form := tview.NewForm()
+	form.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Set a field on %d servers ", len(servers))).
+		SetTitleAlign(tview.AlignCenter)
+	form.AddDropDown("Field:", names, 0, nil)
+	form.AddInputField("Value:", "", 50, nil, nil)
+	if field, ok := form.GetFormItem(1).(*tview.InputField); ok {
+		field.SetPlaceholder("empty removes the setting")
+	}
+	form.AddButton("Apply", func() {
+		idx, _ := form.GetFormItem(0).(*tview.DropDown).GetCurrentOption()
+		field := bulkFields[idx]
+		value := strings.TrimSpace(form.GetFormItem(1).(*tview.InputField).GetText())
+		if err := field.set(&domain.Server{}, value); err != nil {
+			form.SetTitle(" Set a field — " + err.Error() + " ")
+			return
+		}
+		err := t.serverService.UpdateServers(servers, func(s *domain.Server) { _ = field.set(s, value) })
+		t.refreshServerList()
+		t.returnToMain()
+		t.showBulkResult(fmt.Sprintf("%s set on %d servers", field.name, len(servers)), skipped, err)
+	})
+	form.AddButton("Cancel", t.returnToMain)
+	form.SetCancelFunc(t.returnToMain)
+	t.app.SetRoot(form, true)
+	t.app.SetFocus(form)
+}
+
+// handleBulkPin pins all marked servers, or unpins them when all are pinned.
+func (t *tui) handleBulkPin() {
+	marked := t.serverList.MarkedServers()
+	pin := slices.ContainsFunc(marked, func(s domain.Server) bool { return s.PinnedAt.IsZero() })
+	err := t.serverService.SetPinnedServers(marked, pin)
+	t.refreshServerList()
+	action := "Pinned"
+	if !pin {
+		action = "Unpinned"
+	}
+	t.showBulkResult(fmt.Sprintf("%s %d servers", action, len(marked)), 0, err)
+}
+
+func (t *tui) handleBulkDelete() {
+	servers, skipped := t.writableMarked()
+	if len(servers) == 0 {
+		t.showStatusTempColor("All marked servers are read-only (cannot delete here)", "#FF6B6B")
+		return
+	}
+	aliases := make([]string, 0, len(servers))
+	for _, s := range servers {
+		aliases = append(aliases, s.Alias)
+	}
+	if len(aliases) > 10 {
+		aliases = append(aliases[:10], fmt.Sprintf("and %d more", len(servers)-10))
+	}
+	msg := fmt.Sprintf("Delete %d servers?\n\n%s", len(servers), strings.Join(aliases, ", "))
+	if skipped > 0 {
+		msg += fmt.Sprintf("\n\n%d read-only servers are kept.", skipped)
+	}
+	remove := func() {
+		err := t.serverService.DeleteServers(servers)
+		t.serverList.ClearMarks()
+		t.refreshServerList()
+		t.handleModalClose()
+		t.showBulkResult(fmt.Sprintf("Deleted %d servers", len(servers)), skipped, err)
+	}
+
+	modal := tview.NewModal().
+		SetText(msg).
+		AddButtons([]string{"[yellow]C[-]ancel", "[yellow]D[-]elete"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			if buttonIndex == 1 {
+				remove()
+				return
+			}
+			t.handleModalClose()
+		})
+	modal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'c', 'C':
+			t.handleModalClose()
+			return nil
+		case 'd', 'D':
+			remove()
+			return nil
+		}
+		return event
+	})
+	t.app.SetRoot(modal, true)
+}
+
+func (t *tui) handleBulkPing() {
+	marked := t.serverList.MarkedServers()
+	t.showStatusTemp(fmt.Sprintf("Pinging %d servers…", len(marked)))
+	go func() {
+		var (
+			mu   sync.Mutex
+			wg   sync.WaitGroup
+			up   int
+			down []string
+		)
+		sem := make(chan struct{}, bulkPingConcurrency)
+		for _, s := range marked {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(s domain.Server) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				ok, _, _ := t.serverService.Ping(s)
+				mu.Lock()
+				defer mu.Unlock()
+				if ok {
+					up++
+				} else {
+					down = append(down, s.Alias)
+				}
+			}(s)
+		}
+		wg.Wait()
+		t.app.QueueUpdateDraw(func() {
+			t.applyHealth()
+			if len(down) > 0 {
+				slices.Sort(down)
+				t.showStatusTempColor(fmt.Sprintf("Ping: %d up, %d down (%s)", up, len(down), strings.Join(down, ", ")), "#FF6B6B")
+				return
+			}
+			t.showStatusTempColor(fmt.Sprintf("Ping: all %d servers up", up), "#A0FFA0")
+		})
+	}()
+}
+
+func (t *tui) handleBulkInstallKey() {
+	marked := t.serverList.MarkedServers()
+	aliases := make([]string, 0, len(marked))
+	for _, s := range marked {
+		aliases = append(aliases, s.Alias)
+	}
+	var wizard *KeyWizard
+	wizard = NewKeyWizard(NewAppHeader(t.version, t.commit, RepoURL), aliases).
+		OnSubmit(func(req KeyRequest) { t.handleKeyDeploy(wizard, req) }).
+		OnCancel(t.returnToMain)
+	t.app.SetRoot(wizard, true)
+}
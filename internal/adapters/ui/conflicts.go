@@ -0,0 +1,138 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// startWatch watches the config files of the current workspace and reloads the
+// server list when they are changed outside lazyssh.
+func (t *tui) startWatch() {
+	ss := t.serverService
+	ss.StartWatch(func() {
+		t.app.QueueUpdateDraw(func() {
+			if t.serverService == ss {
+				t.handleConfigChange()
+			}
+		})
+	})
+}
+
+// handleConfigChange reloads the list after an outside change. While a form,
+// modal or another screen is open the reload waits until the list is shown
+// again, so nothing is swapped underneath what is being edited; saving it in
+// the meantime reports the conflict.
+func (t *tui) handleConfigChange() {
+	t.configChanged = true
+	if t.onMainScreen() {
+		t.reloadChangedConfig()
+	}
+}
+
+// reloadChangedConfig reloads the list after an outside change.
+func (t *tui) reloadChangedConfig() {
+	t.configChanged = false
+	t.reloadServerList()
+	t.showStatusTempColor("Config changed on disk, reloaded", "#FFCC66")
+}
+
+// reloadServerList reloads the list, keeping the selection.
+func (t *tui) reloadServerList() {
+	selected, ok := t.serverList.GetSelectedServer()
+	t.refreshServerList()
+	if ok {
+		t.serverList.SelectAlias(selected.Alias)
+	}
+}
+
+// onMainScreen reports whether the server list is showing and nothing is open over it.
+func (t *tui) onMainScreen() bool {
+	focus := t.app.GetFocus()
+	return focus == t.serverList || focus == t.serverList.List || focus == t.searchBar || focus == t.searchBar.InputField
+}
+
+// handleConflict offers to reload, merge or overwrite when err says a config
+// file was changed outside lazyssh since it was listed. retry repeats the
+// write and done runs once it succeeded. It reports whether err was a conflict.
+func (t *tui) handleConflict(err error, retry func() error, done func()) bool {
+	var conflict *domain.ConfigConflictError
+	if !errors.As(err, &conflict) {
+		return false
+	}
+	t.showConflictModal(conflict, retry, done)
+	return true
+}
+
+func (t *tui) showConflictModal(conflict *domain.ConfigConflictError, retry func() error, done func()) {
+	msg := fmt.Sprintf("%s was changed outside lazyssh since it was loaded.\n\n"+
+		"Reload: drop your change and show the file as it is now.\n"+
+		"Merge: apply your change on top of the other changes.\n"+
+		"Overwrite: drop the other changes (a backup is kept).", conflict.Path)
+
+	reload := func() {
+		t.configChanged = false
+		t.returnToMain()
+		t.reloadServerList()
+		t.showStatusTempColor("Reloaded, your change was not saved", "#FFCC66")
+	}
+	resolve := func(resolution domain.ConflictResolution) {
+		if err := t.serverService.ResolveConflict(conflict.Path, resolution, retry); err != nil {
+			modal := tview.NewModal().
+				SetText(fmt.Sprintf("Save failed: %v", err)).
+				AddButtons([]string{"Close"}).
+				SetDoneFunc(func(int, string) { t.handleModalClose() })
+			t.app.SetRoot(modal, true)
+			return
+		}
+		// The list is reloaded by done with the change written on top.
+		t.configChanged = false
+		done()
+	}
+
+	modal := tview.NewModal().
+		SetText(msg).
+		AddButtons([]string{"[yellow]R[-]eload", "[yellow]M[-]erge", "[yellow]O[-]verwrite"}).
+		SetDoneFunc(func(buttonIndex int, _ string) {
+			switch buttonIndex {
+			case 1:
+				resolve(domain.ConflictMerge)
+			case 2:
+				resolve(domain.ConflictOverwrite)
+			default:
+				reload()
+			}
+		})
+	modal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'r', 'R':
+			reload()
+			return nil
+		case 'm', 'M':
+			resolve(domain.ConflictMerge)
+			return nil
+		case 'o', 'O':
+			resolve(domain.ConflictOverwrite)
+			return nil
+		}
+		return event
+	})
+	t.app.SetRoot(modal, true)
+}
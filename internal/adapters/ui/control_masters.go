@@ -0,0 +1,256 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/core/ports"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// ControlMastersView lists the multiplexing masters (ControlMaster/ControlPath)
+// of the servers and lets the user stop, exit or kill them.
+type ControlMastersView struct {
+	*tview.Flex
+	app     *tview.Application
+	service ports.ServerService
+
+	table   *tview.Table
+	details *tview.TextView
+	status  *tview.TextView
+
+	masters []domain.ControlMaster
+	loading bool
+	onExit  func(domain.ControlMaster)
+	onStop  func(domain.ControlMaster)
+	onKill  func(domain.ControlMaster)
+	onClose func()
+}
+
+func NewControlMastersView(header *AppHeader, app *tview.Application, ss ports.ServerService) *ControlMastersView {
+	v := &ControlMastersView{
+		Flex:    tview.NewFlex().SetDirection(tview.FlexRow),
+		app:     app,
+		service: ss,
+		table:   tview.NewTable(),
+		details: tview.NewTextView(),
+		status:  tview.NewTextView(),
+	}
+	v.build(header)
+	return v
+}
+
+func (v *ControlMastersView) build(header *AppHeader) {
+	v.table.SetBorder(true).
+		SetTitle(" Control Masters ").
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+	v.table.SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetSelectedStyle(tcell.StyleDefault.Background(tcell.Color24).Foreground(tcell.Color255))
+	v.table.SetSelectionChangedFunc(func(row, _ int) {
+		v.showMaster(row)
+	})
+
+	v.details.SetDynamicColors(true).
+		SetWrap(true).
+		SetBorder(true).
+		SetTitle(" Details ").
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+
+	v.status.SetDynamicColors(true)
+	v.status.SetBackgroundColor(tcell.Color235)
+	v.status.SetTextAlign(tview.AlignCenter)
+	v.setHint()
+
+	v.Flex.AddItem(header, 2, 0, false).
+		AddItem(v.table, 0, 1, true).
+		AddItem(v.details, 11, 0, false).
+		AddItem(v.status, 1, 0, false)
+
+	v.Flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			if v.onClose != nil {
+				v.onClose()
+			}
+			return nil
+		}
+		switch event.Rune() {
+		case 'q':
+			if v.onClose != nil {
+				v.onClose()
+			}
+			return nil
+		case 'r':
+			v.Load("")
+			return nil
+		case 'x':
+			v.withSelected(v.onExit)
+			return nil
+		case 's':
+			v.withSelected(v.onStop)
+			return nil
+		case 'K':
+			v.withSelected(v.onKill)
+			return nil
+		}
+		return event
+	})
+}
+
+// Load checks the masters in the background and selects the one of alias, if given.
+func (v *ControlMastersView) Load(alias string) *ControlMastersView {
+	if v.loading {
+		return v
+	}
+	if row, _ := v.table.GetSelection(); alias == "" && row >= 1 && row <= len(v.masters) {
+		alias = v.masters[row-1].Alias
+	}
+	v.loading = true
+	v.setHint()
+	v.table.SetTitle(" Control Masters — checking… ")
+	go func() {
+		masters, err := v.service.ListControlMasters()
+		v.app.QueueUpdateDraw(func() {
+			v.loading = false
+			v.table.SetTitle(" Control Masters ")
+			if err != nil {
+				v.ShowError(err)
+			}
+			v.setMasters(masters, alias)
+		})
+	}()
+	return v
+}
+
+func (v *ControlMastersView) OnExit(fn func(domain.ControlMaster)) *ControlMastersView {
+	v.onExit = fn
+	return v
+}
+
+func (v *ControlMastersView) OnStop(fn func(domain.ControlMaster)) *ControlMastersView {
+	v.onStop = fn
+	return v
+}
+
+func (v *ControlMastersView) OnKill(fn func(domain.ControlMaster)) *ControlMastersView {
+	v.onKill = fn
+	return v
+}
+
+func (v *ControlMastersView) OnClose(fn func()) *ControlMastersView {
+	v.onClose = fn
+	return v
+}
+
+// ShowError displays err in the hint bar until the next action.
+func (v *ControlMastersView) ShowError(err error) *ControlMastersView {
+	v.status.SetText("[#FF6B6B]" + tview.Escape(err.Error()) + "[-]")
+	return v
+}
+
+func (v *ControlMastersView) withSelected(fn func(domain.ControlMaster)) {
+	row, _ := v.table.GetSelection()
+	if fn == nil || row < 1 || row > len(v.masters) {
+		return
+	}
+	fn(v.masters[row-1])
+}
+
+func (v *ControlMastersView) setMasters(masters []domain.ControlMaster, alias string) {
+	v.masters = masters
+	v.table.Clear()
+	for i, h := range []string{"Server", "State", "PID", "Sessions", "Persist", "Control path"} {
+		v.table.SetCell(0, i, tview.NewTableCell("[::b]"+h).SetSelectable(false).SetTextColor(tcell.Color250))
+	}
+	if len(masters) == 0 {
+		v.table.SetCell(1, 0, tview.NewTableCell("").SetSelectable(false))
+		v.details.SetText("No server uses a ControlPath.\n\nSet [white]ControlMaster auto[-], [white]ControlPath[-] (e.g. ~/.ssh/cm-%C) " +
+			"and [white]ControlPersist[-] on a server to reuse one connection for all its sessions.")
+		return
+	}
+
+	row := 1
+	for i, m := range masters {
+		r := i + 1
+		if m.Alias == alias {
+			row = r
+		}
+		pid, sessions := "", ""
+		if m.PID != 0 {
+			pid = strconv.Itoa(m.PID)
+		}
+		if m.State == domain.ControlAlive {
+			sessions = "?"
+			if m.Sessions >= 0 {
+				sessions = strconv.Itoa(m.Sessions)
+			}
+		}
+		v.table.SetCell(r, 0, tview.NewTableCell(tview.Escape(m.Alias)))
+		v.table.SetCell(r, 1, tview.NewTableCell(controlStateLabel(m.State)))
+		v.table.SetCell(r, 2, tview.NewTableCell(pid).SetAlign(tview.AlignRight))
+		v.table.SetCell(r, 3, tview.NewTableCell(sessions).SetAlign(tview.AlignRight))
+		v.table.SetCell(r, 4, tview.NewTableCell(tview.Escape(m.Persist)))
+		v.table.SetCell(r, 5, tview.NewTableCell(tview.Escape(m.Path)).SetExpansion(1))
+	}
+	v.table.Select(row, 0)
+	v.showMaster(row)
+}
+
+func (v *ControlMastersView) showMaster(row int) {
+	if row < 1 || row > len(v.masters) {
+		return
+	}
+	m := v.masters[row-1]
+	text := fmt.Sprintf("[::b]%s[-]  %s\n\n  ControlPath: [white]%s[-]\n  ControlMaster: [white]%s[-]  ControlPersist: [white]%s[-]\n",
+		tview.Escape(m.Alias), controlStateLabel(m.State), tview.Escape(m.Path), m.Mode, m.Persist)
+	switch m.State {
+	case domain.ControlAlive:
+		text += "\n  [white]s[-] stops accepting new sessions and exits after the last one, [white]x[-] exits now and closes them.\n"
+	case domain.ControlStale:
+		text += "\n  The socket is left over from a master that is gone; new connections may fail until it is removed ([white]K[-]).\n"
+	case domain.ControlHung:
+		text += "\n  The master does not answer; new connections to this server will hang. Press [white]K[-] to kill it.\n"
+	case domain.ControlNotRunning:
+	}
+	if m.Error != "" {
+		text += "\n  Error: [#FF6B6B]" + tview.Escape(m.Error) + "[-]\n"
+	}
+	v.details.SetText(text)
+}
+
+func (v *ControlMastersView) setHint() {
+	v.status.SetText("[white]↑↓[-] Navigate  • [white]s[-] Stop (graceful)  • [white]x[-] Exit  • [white]K[-] Kill stale/hung  • [white]r[-] Refresh  • [white]Esc[-] Back")
+}
+
+func controlStateLabel(s domain.ControlMasterState) string {
+	switch s {
+	case domain.ControlAlive:
+		return "[#A0FFA0]● alive[-]"
+	case domain.ControlStale:
+		return "[#FFCC66]✗ stale socket[-]"
+	case domain.ControlHung:
+		return "[#FF6B6B]⚠ hung[-]"
+	default:
+		return "[#888888]not running[-]"
+	}
+}
@@ -0,0 +1,348 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/core/ports"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	defaultExecConcurrency = 10
+	defaultExecTimeout     = 30 * time.Second
+	// execMaxLines bounds the output kept per server.
+	execMaxLines = 5000
+)
+
+// execHost is the progress and output of one server in an ExecView.
+type execHost struct {
+	result domain.ExecResult
+	lines  []string
+}
+
+// ExecView runs a command on several servers and shows their status next to
+// the output of the selected one.
+type ExecView struct {
+	*tview.Flex
+	app     *tview.Application
+	service ports.ServerService
+	req     domain.ExecRequest
+
+	table  *tview.Table
+	output *tview.TextView
+	status *tview.TextView
+
+	hosts   []*execHost
+	byAlias map[string]*execHost
+	started time.Time
+	cancel  context.CancelFunc
+	onClose func()
+}
+
+func NewExecView(header *AppHeader, app *tview.Application, ss ports.ServerService, req domain.ExecRequest) *ExecView {
+	v := &ExecView{
+		Flex:    tview.NewFlex().SetDirection(tview.FlexRow),
+		app:     app,
+		service: ss,
+		req:     req,
+		table:   tview.NewTable(),
+		output:  tview.NewTextView(),
+		status:  tview.NewTextView(),
+	}
+	v.build(header)
+	return v
+}
+
+func (v *ExecView) build(header *AppHeader) {
+	v.table.SetBorder(true).
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+	v.table.SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetSelectedStyle(tcell.StyleDefault.Background(tcell.Color24).Foreground(tcell.Color255))
+	v.table.SetSelectionChangedFunc(func(row, _ int) {
+		v.showOutput(row)
+	})
+	v.table.SetTitle(" " + tview.Escape(v.req.Command) + " ")
+
+	v.output.SetDynamicColors(true).
+		SetWrap(true).
+		SetBorder(true).
+		SetTitle(" Output ").
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+
+	v.status.SetDynamicColors(true)
+	v.status.SetBackgroundColor(tcell.Color235)
+	v.status.SetTextAlign(tview.AlignCenter)
+
+	split := tview.NewFlex().
+		AddItem(v.table, 0, 2, true).
+		AddItem(v.output, 0, 3, false)
+	v.Flex.AddItem(header, 2, 0, false).
+		AddItem(split, 0, 1, true).
+		AddItem(v.status, 1, 0, false)
+
+	v.Flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			v.close()
+			return nil
+		case tcell.KeyTab:
+			if v.output.HasFocus() {
+				v.app.SetFocus(v.table)
+			} else {
+				v.app.SetFocus(v.output)
+			}
+			return nil
+		default:
+		}
+		switch event.Rune() {
+		case 'q':
+			v.close()
+			return nil
+		case 'c':
+			if v.cancel != nil {
+				v.cancel()
+			}
+			return nil
+		case 'r':
+			if v.cancel == nil {
+				v.Run()
+			}
+			return nil
+		}
+		return event
+	})
+}
+
+// Run starts the command on the servers in the background.
+func (v *ExecView) Run() *ExecView {
+	ctx, cancel := context.WithCancel(context.Background())
+	v.cancel = cancel
+	v.hosts = nil
+	v.byAlias = make(map[string]*execHost)
+	v.started = time.Now()
+	v.output.SetText("")
+	v.render()
+	go func() {
+		_, err := v.service.Exec(ctx, v.req, func(e domain.ExecEvent) {
+			v.app.QueueUpdateDraw(func() { v.apply(e) })
+		})
+		cancel()
+		v.app.QueueUpdateDraw(func() {
+			v.cancel = nil
+			if err != nil {
+				v.status.SetText("[#FF6B6B]" + tview.Escape(err.Error()) + "[-]  • [white]Esc[-] Back")
+				return
+			}
+			v.render()
+		})
+	}()
+	return v
+}
+
+func (v *ExecView) OnClose(fn func()) *ExecView {
+	v.onClose = fn
+	return v
+}
+
+func (v *ExecView) close() {
+	if v.cancel != nil {
+		v.cancel()
+	}
+	if v.onClose != nil {
+		v.onClose()
+	}
+}
+
+func (v *ExecView) apply(e domain.ExecEvent) {
+	h, ok := v.byAlias[e.Alias]
+	if !ok {
+		h = &execHost{result: domain.ExecResult{Alias: e.Alias, ExitCode: -1}}
+		v.byAlias[e.Alias] = h
+		v.hosts = append(v.hosts, h)
+	}
+	switch e.Kind {
+	case domain.ExecQueued:
+	case domain.ExecStarted:
+		h.result.State = domain.ExecRunning
+	case domain.ExecOutput:
+		line := tview.Escape(e.Line)
+		if e.Stderr {
+			line = "[#FF6B6B]" + line + "[-]"
+		}
+		h.lines = append(h.lines, line)
+		if extra := len(h.lines) - execMaxLines; extra > 0 {
+			h.lines = h.lines[extra:]
+		}
+		if row, _ := v.table.GetSelection(); row >= 1 && row <= len(v.hosts) && v.hosts[row-1] == h {
+			_, _ = fmt.Fprintln(v.output, line)
+		}
+		return
+	case domain.ExecFinished:
+		h.result = e.Result
+	}
+	v.render()
+}
+
+func (v *ExecView) render() {
+	row, _ := v.table.GetSelection()
+	v.table.Clear()
+	for i, h := range []string{"Server", "Status", "Exit", "Time"} {
+		v.table.SetCell(0, i, tview.NewTableCell("[::b]"+h).SetSelectable(false).SetTextColor(tcell.Color250))
+	}
+	counts := map[domain.ExecState]int{}
+	for i, h := range v.hosts {
+		r := h.result
+		counts[r.State]++
+		exit, took := "", ""
+		if r.ExitCode >= 0 {
+			exit = strconv.Itoa(r.ExitCode)
+		}
+		if r.State.Done() {
+			took = formatLatency(r.Duration)
+		}
+		v.table.SetCell(i+1, 0, tview.NewTableCell(tview.Escape(r.Alias)).SetExpansion(1))
+		v.table.SetCell(i+1, 1, tview.NewTableCell(execStateLabel(r.State)))
+		v.table.SetCell(i+1, 2, tview.NewTableCell(exit).SetAlign(tview.AlignRight))
+		v.table.SetCell(i+1, 3, tview.NewTableCell(took).SetAlign(tview.AlignRight))
+	}
+	if len(v.hosts) > 0 {
+		row = min(max(row, 1), len(v.hosts))
+		if selected, _ := v.table.GetSelection(); selected != row {
+			v.table.Select(row, 0)
+		} else {
+			v.showOutput(row)
+		}
+	}
+
+	summary := fmt.Sprintf("[#A0FFA0]%d ok[-]", counts[domain.ExecSucceeded])
+	if n := counts[domain.ExecFailed]; n > 0 {
+		summary += fmt.Sprintf("  [#FF6B6B]%d failed[-]", n)
+	}
+	if n := counts[domain.ExecError] + counts[domain.ExecTimedOut]; n > 0 {
+		summary += fmt.Sprintf("  [#FFCC66]%d error/timeout[-]", n)
+	}
+	if n := counts[domain.ExecCanceled]; n > 0 {
+		summary += fmt.Sprintf("  [#888888]%d canceled[-]", n)
+	}
+	keys := "[white]c[-] Cancel  • [white]Esc[-] Cancel and back"
+	if v.cancel == nil {
+		summary += fmt.Sprintf("  [#888888]in %s[-]", formatLatency(time.Since(v.started)))
+		keys = "[white]r[-] Run again  • [white]Esc[-] Back"
+	} else if n := counts[domain.ExecRunning]; n > 0 {
+		summary += fmt.Sprintf("  [#FFCC66]%d running[-]", n)
+	}
+	v.status.SetText(summary + "   [white]↑↓[-] Server  • [white]Tab[-] Scroll output  • " + keys)
+}
+
+func (v *ExecView) showOutput(row int) {
+	if row < 1 || row > len(v.hosts) {
+		return
+	}
+	h := v.hosts[row-1]
+	text := strings.Join(h.lines, "\n")
+	if r := h.result; r.Error != "" && r.State != domain.ExecError {
+		text += "\n[#FFCC66]" + tview.Escape(r.Error) + "[-]"
+	}
+	v.output.SetTitle(" " + tview.Escape(h.result.Alias) + " ")
+	v.output.SetText(text)
+	v.output.ScrollToEnd()
+}
+
+func execStateLabel(s domain.ExecState) string {
+	switch s {
+	case domain.ExecSucceeded:
+		return "[#A0FFA0]✓ ok[-]"
+	case domain.ExecFailed:
+		return "[#FF6B6B]✗ failed[-]"
+	case domain.ExecError, domain.ExecTimedOut:
+		return "[#FF6B6B]⚠ " + s.String() + "[-]"
+	case domain.ExecRunning:
+		return "[#FFCC66]◌ running[-]"
+	default:
+		return "[#888888]" + s.String() + "[-]"
+	}
+}
+
+// NewExecForm asks for a command and the servers to run it on, by alias and tag.
+func NewExecForm(aliases, tags string, onSubmit func(domain.ExecRequest), onCancel func()) *tview.Form {
+	form := tview.NewForm()
+	form.SetBorder(true).
+		SetTitle(" Run command on servers ").
+		SetTitleAlign(tview.AlignCenter)
+
+	form.AddInputField("Command:", "", 60, nil, nil)
+	form.AddInputField("Servers:", aliases, 60, nil, nil)
+	form.AddInputField("Tags:", tags, 40, nil, nil)
+	form.AddInputField("Concurrency:", strconv.Itoa(defaultExecConcurrency), 6, tview.InputFieldInteger, nil)
+	form.AddInputField("Timeout:", defaultExecTimeout.String(), 10, nil, nil)
+	for i, placeholder := range []string{"uptime", "web-01, web-02", "prod"} {
+		if field, ok := form.GetFormItem(i).(*tview.InputField); ok {
+			field.SetPlaceholder(placeholder)
+		}
+	}
+
+	text := func(i int) string {
+		return strings.TrimSpace(form.GetFormItem(i).(*tview.InputField).GetText())
+	}
+	fail := func(msg string) {
+		form.SetTitle(" Run command on servers — " + msg + " ")
+	}
+	form.AddButton("Run", func() {
+		req := domain.ExecRequest{
+			Command: text(0),
+			Aliases: splitCommaList(text(1)),
+			Tags:    splitCommaList(text(2)),
+		}
+		if req.Command == "" {
+			fail("enter a command")
+			return
+		}
+		if len(req.Aliases)+len(req.Tags) == 0 {
+			fail("enter servers or tags")
+			return
+		}
+		n, err := strconv.Atoi(text(3))
+		if err != nil || n < 1 {
+			fail("concurrency must be at least 1")
+			return
+		}
+		req.Concurrency = n
+		if text(4) != "" {
+			d, err := time.ParseDuration(text(4))
+			if err != nil || d < 0 {
+				fail("timeout must be a duration like 30s")
+				return
+			}
+			req.Timeout = d
+		}
+		onSubmit(req)
+	})
+	form.AddButton("Cancel", onCancel)
+	form.SetCancelFunc(onCancel)
+	return form
+}
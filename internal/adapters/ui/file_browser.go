@@ -0,0 +1,501 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/core/ports"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// queueRefreshInterval is how often the transfer queue is redrawn while the browser is open.
+const queueRefreshInterval = 500 * time.Millisecond
+
+// filePane is one side of the file browser.
+type filePane struct {
+	table   *tview.Table
+	name    string
+	dir     string
+	entries []domain.FileEntry
+	remote  bool
+}
+
+// FileBrowserView is a dual-pane file manager: local files on the left, the
+// server's files on the right and the transfer queue below. Remote listings use
+// sftp and copies scp, both through the server's SSH config alias.
+type FileBrowserView struct {
+	*tview.Flex
+	app     *tview.Application
+	service ports.ServerService
+	alias   string
+
+	local  *filePane
+	remote *filePane
+	queue  *tview.Table
+	status *tview.TextView
+
+	transfers []domain.Transfer
+	stop      chan struct{}
+	onClose   func()
+}
+
+func NewFileBrowserView(header *AppHeader, app *tview.Application, ss ports.ServerService, alias string) *FileBrowserView {
+	v := &FileBrowserView{
+		Flex:    tview.NewFlex().SetDirection(tview.FlexRow),
+		app:     app,
+		service: ss,
+		alias:   alias,
+		local:   &filePane{table: tview.NewTable(), name: "Local"},
+		remote:  &filePane{table: tview.NewTable(), name: alias, remote: true},
+		queue:   tview.NewTable(),
+		status:  tview.NewTextView(),
+	}
+	v.build(header)
+	return v
+}
+
+func (v *FileBrowserView) build(header *AppHeader) {
+	for _, p := range []*filePane{v.local, v.remote} {
+		pane := p
+		pane.table.SetBorder(true).
+			SetTitleAlign(tview.AlignLeft).
+			SetBorderColor(tcell.Color238).
+			SetTitleColor(tcell.Color250)
+		pane.table.SetSelectable(true, false).
+			SetFixed(1, 0).
+			SetSelectedStyle(tcell.StyleDefault.Background(tcell.Color24).Foreground(tcell.Color255))
+		pane.table.SetSelectedFunc(func(row, _ int) {
+			v.open(pane, row)
+		})
+	}
+
+	v.queue.SetBorder(true).
+		SetTitle(" Transfers ").
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+	v.queue.SetSelectable(true, false).
+		SetSelectedStyle(tcell.StyleDefault.Background(tcell.Color24).Foreground(tcell.Color255))
+
+	v.status.SetDynamicColors(true)
+	v.status.SetBackgroundColor(tcell.Color235)
+	v.status.SetTextAlign(tview.AlignCenter)
+	v.setHint()
+
+	panes := tview.NewFlex().SetDirection(tview.FlexColumn).
+		AddItem(v.local.table, 0, 1, true).
+		AddItem(v.remote.table, 0, 1, false)
+
+	v.Flex.AddItem(header, 2, 0, false).
+		AddItem(panes, 0, 1, true).
+		AddItem(v.queue, 8, 0, false).
+		AddItem(v.status, 1, 0, false)
+
+	v.Flex.SetInputCapture(v.handleKey)
+}
+
+func (v *FileBrowserView) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyEscape:
+		v.close()
+		return nil
+	case tcell.KeyTab:
+		v.cycleFocus()
+		return nil
+	case tcell.KeyBackspace, tcell.KeyBackspace2, tcell.KeyLeft:
+		if p := v.focusedPane(); p != nil {
+			v.load(p, v.parentDir(p))
+		}
+		return nil
+	case tcell.KeyF5:
+		v.copySelected()
+		return nil
+	}
+	switch event.Rune() {
+	case 'q':
+		v.close()
+		return nil
+	case 'c':
+		v.copySelected()
+		return nil
+	case 'r':
+		v.load(v.local, v.local.dir)
+		v.load(v.remote, v.remote.dir)
+		return nil
+	case 'x':
+		v.cancelSelected()
+		return nil
+	}
+	return event
+}
+
+// Start lists the local working directory and the remote home directory and
+// starts redrawing the transfer queue.
+func (v *FileBrowserView) Start() *FileBrowserView {
+	dir, err := os.Getwd()
+	if err != nil {
+		dir = ""
+	}
+	v.load(v.local, dir)
+	v.load(v.remote, "")
+	v.refreshQueue()
+
+	v.stop = make(chan struct{})
+	go func(stop chan struct{}) {
+		ticker := time.NewTicker(queueRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				v.app.QueueUpdateDraw(v.refreshQueue)
+			}
+		}
+	}(v.stop)
+	return v
+}
+
+func (v *FileBrowserView) OnClose(fn func()) *FileBrowserView {
+	v.onClose = fn
+	return v
+}
+
+func (v *FileBrowserView) close() {
+	if v.stop != nil {
+		close(v.stop)
+		v.stop = nil
+	}
+	if v.onClose != nil {
+		v.onClose()
+	}
+}
+
+// load lists dir in the pane; remote listings run in the background.
+func (v *FileBrowserView) load(p *filePane, dir string) {
+	if !p.remote {
+		if dir != "" {
+			if abs, err := filepath.Abs(dir); err == nil {
+				dir = abs
+			}
+		}
+		entries, err := v.service.ListLocalDir(dir)
+		if err != nil {
+			v.showError(err)
+			return
+		}
+		if dir == "" {
+			dir, _ = os.UserHomeDir()
+		}
+		v.setEntries(p, dir, entries)
+		return
+	}
+
+	p.table.SetTitle(fmt.Sprintf(" %s: loading… ", tview.Escape(p.name)))
+	go func() {
+		resolved, entries, err := v.service.ListRemoteDir(v.alias, dir)
+		v.app.QueueUpdateDraw(func() {
+			if err != nil {
+				v.setPaneTitle(p)
+				v.showError(err)
+				return
+			}
+			v.setEntries(p, resolved, entries)
+		})
+	}()
+}
+
+func (v *FileBrowserView) setEntries(p *filePane, dir string, entries []domain.FileEntry) {
+	p.dir = dir
+	p.entries = entries
+	v.setPaneTitle(p)
+
+	p.table.Clear()
+	header := []string{"Name", "Size", "Modified"}
+	for col, h := range header {
+		p.table.SetCell(0, col, tview.NewTableCell(h).
+			SetTextColor(tcell.Color245).
+			SetSelectable(false).
+			SetExpansion(boolToInt(col == 0)))
+	}
+	p.table.SetCell(1, 0, tview.NewTableCell("[#55D7FF]../[-]").SetExpansion(1))
+	for i, e := range entries {
+		row := i + 2
+		name := tview.Escape(e.Name)
+		size := formatSize(e.Size)
+		switch {
+		case e.Dir:
+			name = "[#55D7FF]" + name + "/[-]"
+			size = ""
+		case e.Link:
+			name = "[#A78BFA]" + name + "@[-]"
+		}
+		p.table.SetCell(row, 0, tview.NewTableCell(name).SetExpansion(1))
+		p.table.SetCell(row, 1, tview.NewTableCell(size).SetAlign(tview.AlignRight))
+		p.table.SetCell(row, 2, tview.NewTableCell(e.Modified).SetTextColor(tcell.Color245))
+	}
+	p.table.Select(1, 0)
+	p.table.ScrollToBeginning()
+}
+
+func (v *FileBrowserView) setPaneTitle(p *filePane) {
+	dir := p.dir
+	if !p.remote {
+		dir = displayConfigPath(dir)
+	}
+	p.table.SetTitle(fmt.Sprintf(" %s: %s ", tview.Escape(p.name), tview.Escape(dir)))
+}
+
+// open enters the directory at row, or the parent for the ".." row.
+func (v *FileBrowserView) open(p *filePane, row int) {
+	if row == 1 {
+		v.load(p, v.parentDir(p))
+		return
+	}
+	e, ok := entryAt(p, row)
+	if !ok {
+		return
+	}
+	if e.Dir || (e.Link && p.remote) {
+		v.load(p, v.join(p, e.Name))
+	}
+}
+
+// copySelected queues a copy of the selected entry into the other pane's directory.
+func (v *FileBrowserView) copySelected() {
+	p := v.focusedPane()
+	if p == nil {
+		return
+	}
+	row, _ := p.table.GetSelection()
+	e, ok := entryAt(p, row)
+	if !ok {
+		return
+	}
+	other := v.remote
+	direction := domain.Upload
+	if p.remote {
+		other = v.local
+		direction = domain.Download
+	}
+	if other.dir == "" {
+		v.showError(fmt.Errorf("%s is not listed yet", other.name))
+		return
+	}
+	_, err := v.service.EnqueueTransfer(domain.Transfer{
+		Alias:     v.alias,
+		Direction: direction,
+		Source:    v.join(p, e.Name),
+		Target:    other.dir,
+		Recursive: e.Dir,
+	})
+	if err != nil {
+		v.showError(err)
+		return
+	}
+	v.refreshQueue()
+}
+
+func (v *FileBrowserView) cancelSelected() {
+	row, _ := v.queue.GetSelection()
+	if row < 0 || row >= len(v.transfers) {
+		return
+	}
+	if err := v.service.CancelTransfer(v.transfers[row].ID); err != nil {
+		v.showError(err)
+	}
+	v.refreshQueue()
+}
+
+// refreshQueue redraws the transfers of this server, most recent first. When a
+// transfer finishes, the pane it copied into is listed again.
+func (v *FileBrowserView) refreshQueue() {
+	all := v.service.ListTransfers()
+	transfers := make([]domain.Transfer, 0, len(all))
+	for i := len(all) - 1; i >= 0; i-- {
+		if all[i].Alias == v.alias {
+			transfers = append(transfers, all[i])
+		}
+	}
+	for _, t := range transfers {
+		if t.State == domain.TransferDone && wasActive(v.transfers, t.ID) {
+			if t.Direction == domain.Upload && t.Target == v.remote.dir {
+				v.load(v.remote, v.remote.dir)
+			} else if t.Direction == domain.Download && t.Target == v.local.dir {
+				v.load(v.local, v.local.dir)
+			}
+		}
+	}
+	v.transfers = transfers
+
+	row, _ := v.queue.GetSelection()
+	v.queue.Clear()
+	if len(transfers) == 0 {
+		v.queue.SetCell(0, 0, tview.NewTableCell("[#888888]No transfers yet. Select a file or directory and press c to copy it to the other side.[-]").SetSelectable(false))
+		return
+	}
+	for i, t := range transfers {
+		v.queue.SetCell(i, 0, tview.NewTableCell(transferStateLabel(t.State)))
+		v.queue.SetCell(i, 1, tview.NewTableCell(transferRoute(t)).SetExpansion(1).SetMaxWidth(80))
+		v.queue.SetCell(i, 2, tview.NewTableCell(transferProgress(t)).SetAlign(tview.AlignRight))
+	}
+	if row >= len(transfers) {
+		row = len(transfers) - 1
+	}
+	v.queue.Select(max(row, 0), 0)
+}
+
+func wasActive(transfers []domain.Transfer, id int) bool {
+	for _, t := range transfers {
+		if t.ID == id {
+			return t.State == domain.TransferRunning || t.State == domain.TransferQueued
+		}
+	}
+	return false
+}
+
+func (v *FileBrowserView) cycleFocus() {
+	switch {
+	case v.local.table.HasFocus():
+		v.app.SetFocus(v.remote.table)
+	case v.remote.table.HasFocus():
+		v.app.SetFocus(v.queue)
+	default:
+		v.app.SetFocus(v.local.table)
+	}
+	v.setHint()
+}
+
+func (v *FileBrowserView) focusedPane() *filePane {
+	switch {
+	case v.local.table.HasFocus():
+		return v.local
+	case v.remote.table.HasFocus():
+		return v.remote
+	default:
+		return nil
+	}
+}
+
+func (v *FileBrowserView) parentDir(p *filePane) string {
+	if p.remote {
+		return path.Dir(p.dir)
+	}
+	return filepath.Dir(p.dir)
+}
+
+func (v *FileBrowserView) join(p *filePane, name string) string {
+	if p.remote {
+		return path.Join(p.dir, name)
+	}
+	return filepath.Join(p.dir, name)
+}
+
+func (v *FileBrowserView) setHint() {
+	if v.queue.HasFocus() {
+		v.status.SetText("[white]↑↓[-] Select transfer  • [white]x[-] Cancel  • [white]Tab[-] Switch pane  • [white]Esc[-] Back")
+		return
+	}
+	v.status.SetText("[white]Enter[-] Open  • [white]⌫[-] Up  • [white]c/F5[-] Copy to other side  • [white]r[-] Refresh  • [white]Tab[-] Switch pane  • [white]Esc[-] Back")
+}
+
+func (v *FileBrowserView) showError(err error) {
+	v.status.SetText("[#FF6B6B]" + tview.Escape(err.Error()) + "[-]")
+	time.AfterFunc(3*time.Second, func() {
+		v.app.QueueUpdateDraw(v.setHint)
+	})
+}
+
+// entryAt returns the entry shown at a table row (row 0 is the header, row 1 "..").
+func entryAt(p *filePane, row int) (domain.FileEntry, bool) {
+	i := row - 2
+	if i < 0 || i >= len(p.entries) {
+		return domain.FileEntry{}, false
+	}
+	return p.entries[i], true
+}
+
+func transferStateLabel(s domain.TransferState) string {
+	switch s {
+	case domain.TransferQueued:
+		return "[#AAAAAA]⏳ queued[-]"
+	case domain.TransferRunning:
+		return "[#55D7FF]▶ running[-]"
+	case domain.TransferDone:
+		return "[#A0FFA0]✓ done[-]"
+	case domain.TransferFailed:
+		return "[#FF6B6B]✗ failed[-]"
+	default:
+		return "[#888888]■ canceled[-]"
+	}
+}
+
+func transferRoute(t domain.Transfer) string {
+	if t.Direction == domain.Upload {
+		return tview.Escape(fmt.Sprintf("↑ %s → %s:%s", t.Source, t.Alias, t.Target))
+	}
+	return tview.Escape(fmt.Sprintf("↓ %s:%s → %s", t.Alias, t.Source, t.Target))
+}
+
+func transferProgress(t domain.Transfer) string {
+	switch t.State {
+	case domain.TransferRunning:
+		text := fmt.Sprintf("%3d%%", t.Percent)
+		if t.Recursive && t.File != "" {
+			text = tview.Escape(t.File) + " " + text
+		}
+		if t.Rate != "" {
+			text += "  " + t.Rate
+		}
+		if t.ETA != "" {
+			text += "  ETA " + t.ETA
+		}
+		return text
+	case domain.TransferFailed:
+		return "[#FF6B6B]" + tview.Escape(t.Error) + "[-]"
+	case domain.TransferDone:
+		return t.FinishedAt.Sub(t.StartedAt).Round(time.Second).String()
+	default:
+		return ""
+	}
+}
+
+// formatSize renders a byte count like ls -h.
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%c", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
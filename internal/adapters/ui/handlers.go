@@ -15,7 +15,10 @@
 package ui
 
 import (
+	"bufio"
 	"fmt"
+	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -35,7 +38,25 @@ func (t *tui) handleGlobalKeys(event *tcell.EventKey) *tcell.EventKey {
 		return event
 	}
 
+	switch event.Key() {
+	case tcell.KeyCtrlA:
+		t.serverList.ToggleMarkAll()
+		return nil
+	case tcell.KeyCtrlR:
+		t.handleRedo()
+		return nil
+	case tcell.KeyEscape:
+		if t.serverList.MarkedCount() > 0 {
+			t.serverList.ClearMarks()
+			return nil
+		}
+	default:
+	}
+
 	switch event.Rune() {
+	case ' ':
+		t.serverList.ToggleMark()
+		return nil
 	case 'q':
 		t.handleQuit()
 		return nil
@@ -72,6 +93,9 @@ func (t *tui) handleGlobalKeys(event *tcell.EventKey) *tcell.EventKey {
 	case 't':
 		t.handleTagsEdit()
 		return nil
+	case 'w':
+		t.handleWorkspaces()
+		return nil
 	case 'j':
 		t.handleNavigateDown()
 		return nil
@@ -81,6 +105,45 @@ func (t *tui) handleGlobalKeys(event *tcell.EventKey) *tcell.EventKey {
 	case 'K':
 		t.handleInstallSSHKey()
 		return nil
+	case 'M':
+		t.handleMatchBlocks()
+		return nil
+	case 'P':
+		t.handleProfiles("")
+		return nil
+	case 'G':
+		t.handleEffectiveToggle()
+		return nil
+	case 'f':
+		t.handleFileBrowser()
+		return nil
+	case 'i':
+		t.handleKeys()
+		return nil
+	case 'A':
+		t.handleAgent("")
+		return nil
+	case 'H':
+		t.handleKnownHosts()
+		return nil
+	case 'T':
+		t.handleTunnels()
+		return nil
+	case 'C':
+		t.handleControlMasters()
+		return nil
+	case 'v':
+		t.handleProbe()
+		return nil
+	case 'X':
+		t.handleExec()
+		return nil
+	case 'u':
+		t.handleUndo()
+		return nil
+	case 'B':
+		t.handleBackups()
+		return nil
 	}
 
 	if event.Key() == tcell.KeyEnter {
@@ -95,7 +158,31 @@ func (t *tui) handleQuit() {
 	t.app.Stop()
 }
 
+func (t *tui) handleUndo() {
+	summary, err := t.serverService.Undo()
+	if err != nil {
+		t.showStatusTempColor("Undo: "+err.Error(), "#FF6B6B")
+		return
+	}
+	t.refreshServerList()
+	t.showStatusTemp("Undone: " + summary + " (Ctrl-R to redo)")
+}
+
+func (t *tui) handleRedo() {
+	summary, err := t.serverService.Redo()
+	if err != nil {
+		t.showStatusTempColor("Redo: "+err.Error(), "#FF6B6B")
+		return
+	}
+	t.refreshServerList()
+	t.showStatusTemp("Redone: " + summary)
+}
+
 func (t *tui) handleServerPin() {
+	if t.serverList.MarkedCount() > 0 {
+		t.handleBulkPin()
+		return
+	}
 	if server, ok := t.serverList.GetSelectedServer(); ok {
 		pinned := server.PinnedAt.IsZero()
 		_ = t.serverService.SetPinned(server.Alias, pinned)
@@ -129,6 +216,10 @@ func (t *tui) handleCopyCommand() {
 }
 
 func (t *tui) handleTagsEdit() {
+	if t.serverList.MarkedCount() > 0 {
+		t.handleBulkTags()
+		return
+	}
 	if server, ok := t.serverList.GetSelectedServer(); ok {
 		if server.Readonly {
 			t.showStatusTempColor(fmt.Sprintf("Read-only: %s is defined in %s", server.Alias, server.SourceFile), "#FFCC66")
@@ -201,10 +292,181 @@ func (t *tui) handleServerConnect() {
 
 func (t *tui) handleServerSelectionChange(server domain.Server) {
 	t.details.UpdateServer(server)
+	if t.details.ShowingEffective() {
+		t.resolveEffective(server)
+	}
+	t.scheduleDetailsLookups(server)
+}
+
+// detailsLookupDelay is how long the selection has to rest before the slow
+// lookups of the details panel start, so scrolling through the list does not
+// start them for every server passed.
+const detailsLookupDelay = 250 * time.Millisecond
+
+// scheduleDetailsLookups explains the server's blocks, checks the agent and
+// reads the git history of the server in the background once the selection has
+// rested. Results that arrive after the selection moved on are dropped.
+func (t *tui) scheduleDetailsLookups(server domain.Server) {
+	t.selection++
+	selection := t.selection
+	current := func() bool { return selection == t.selection }
+	if t.lookupTimer != nil {
+		t.lookupTimer.Stop()
+	}
+	t.lookupTimer = time.AfterFunc(detailsLookupDelay, func() {
+		go t.explainServer(server, current)
+		go t.checkAgent(server, current)
+		t.loadServerLog(server, current)
+	})
+}
+
+// explainServer finds the Host/Match blocks and inherited profile settings of
+// the server; it parses the config and every Include file, so it runs off the
+// UI goroutine.
+func (t *tui) explainServer(server domain.Server, current func() bool) {
+	blocks, err := t.serverService.ExplainServer(server)
+	if err != nil {
+		return
+	}
+	inherited := t.serverService.ExplainInheritance(server, blocks)
+	t.app.QueueUpdateDraw(func() {
+		if current() {
+			t.details.SetBlocks(blocks)
+			t.details.SetInherited(inherited)
+		}
+	})
+}
+
+// checkAgent looks for IdentitiesOnly problems; it asks ssh-agent and
+// ssh-keygen about every IdentityFile, so it runs off the UI goroutine.
+func (t *tui) checkAgent(server domain.Server, current func() bool) {
+	warnings := t.serverService.AgentWarnings(server)
+	t.app.QueueUpdateDraw(func() {
+		if current() {
+			t.details.SetAgentWarnings(server.Alias, warnings)
+		}
+	})
+}
+
+// loadServerLog reads the git history of the server's Host block; it runs off
+// the UI goroutine.
+func (t *tui) loadServerLog(server domain.Server, current func() bool) {
+	commits, err := t.serverService.ServerLog(server)
+	if err != nil {
+		return
+	}
+	t.app.QueueUpdateDraw(func() {
+		if current() {
+			t.details.SetCommits(server.Alias, commits)
+		}
+	})
+}
+
+func (t *tui) handleEffectiveToggle() {
+	if !t.details.ToggleEffective() {
+		return
+	}
+	if server, ok := t.serverList.GetSelectedServer(); ok {
+		t.resolveEffective(server)
+	}
+}
+
+// resolveEffective runs ssh -G in the background, since Match exec blocks may be slow.
+func (t *tui) resolveEffective(server domain.Server) {
+	go func() {
+		cfg, err := t.serverService.ResolveServer(server)
+		t.app.QueueUpdateDraw(func() {
+			t.details.SetEffective(server.Alias, cfg, err)
+		})
+	}()
+}
+
+func (t *tui) handleMatchBlocks() {
+	blocks, err := t.serverService.ListConfigBlocks()
+	if err != nil {
+		t.showStatusTempColor(fmt.Sprintf("Failed to read config blocks: %v", err), "#FF6B6B")
+		return
+	}
+	view := NewMatchBlocksView(NewAppHeader(t.version, t.commit, RepoURL)).
+		SetBlocks(blocks).
+		OnClose(t.returnToMain)
+	t.app.SetRoot(view, true)
+}
+
+// handleProfiles opens the profiles screen and selects the profile with the given patterns.
+func (t *tui) handleProfiles(selectAlias string) {
+	profiles, err := t.serverService.ListProfiles()
+	if err != nil {
+		t.showStatusTempColor(fmt.Sprintf("Failed to read profiles: %v", err), "#FF6B6B")
+		return
+	}
+	blocks, _ := t.serverService.ListConfigBlocks()
+	view := NewProfilesView(NewAppHeader(t.version, t.commit, RepoURL)).
+		SetProfiles(profiles, blocks).
+		Select(selectAlias).
+		OnAdd(t.handleProfileAdd).
+		OnEdit(t.handleProfileEdit).
+		OnDelete(t.showDeleteProfileConfirmModal).
+		OnClose(func() {
+			t.refreshServerList()
+			t.returnToMain()
+		})
+	t.app.SetRoot(view, true)
+}
+
+func (t *tui) handleProfileAdd() {
+	files, _ := t.serverService.ListConfigFiles()
+	form := NewServerForm(ServerFormAdd, nil).
+		ForProfile().
+		SetConfigFiles(files).
+		SetApp(t.app).
+		SetVersionInfo(t.version, t.commit).
+		OnSave(t.handleProfileSave).
+		OnCancel(func() { t.handleProfiles("") })
+	t.app.SetRoot(form, true)
+}
+
+func (t *tui) handleProfileEdit(profile domain.Server) {
+	if profile.Readonly {
+		t.showProfileError(profile.Alias, fmt.Sprintf("Read-only: %s is defined in %s (cannot edit here)", profile.Alias, profile.SourceFile))
+		return
+	}
+	form := NewServerForm(ServerFormEdit, &profile).
+		ForProfile().
+		SetApp(t.app).
+		SetVersionInfo(t.version, t.commit).
+		OnSave(t.handleProfileSave).
+		OnCancel(func() { t.handleProfiles(profile.Alias) })
+	t.app.SetRoot(form, true)
+}
+
+func (t *tui) handleProfileSave(profile domain.Server, original *domain.Server) {
+	var err error
+	if original != nil {
+		err = t.serverService.UpdateProfile(*original, profile)
+	} else {
+		err = t.serverService.AddProfile(profile)
+	}
+	if err != nil {
+		t.showProfileError(profile.Alias, fmt.Sprintf("Save failed: %v", err))
+		return
+	}
+	t.handleProfiles(profile.Alias)
+}
+
+// showProfileError shows an error modal and returns to the profiles screen.
+func (t *tui) showProfileError(selectAlias, msg string) {
+	modal := tview.NewModal().
+		SetText(msg).
+		AddButtons([]string{"Close"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) { t.handleProfiles(selectAlias) })
+	t.app.SetRoot(modal, true)
 }
 
 func (t *tui) handleServerAdd() {
+	files, _ := t.serverService.ListConfigFiles()
 	form := NewServerForm(ServerFormAdd, nil).
+		SetConfigFiles(files).
 		SetApp(t.app).
 		SetVersionInfo(t.version, t.commit).
 		OnSave(t.handleServerSave).
@@ -213,6 +475,10 @@ func (t *tui) handleServerAdd() {
 }
 
 func (t *tui) handleServerEdit() {
+	if t.serverList.MarkedCount() > 0 {
+		t.handleBulkSetField()
+		return
+	}
 	if server, ok := t.serverList.GetSelectedServer(); ok {
 		if server.Readonly {
 			t.showStatusTempColor(fmt.Sprintf("Read-only: %s is defined in %s (cannot edit here)", server.Alias, server.SourceFile), "#FFCC66")
@@ -228,13 +494,24 @@ func (t *tui) handleServerEdit() {
 }
 
 func (t *tui) handleServerSave(server domain.Server, original *domain.Server) {
-	var err error
-	if original != nil {
-		// Edit mode
-		err = t.serverService.UpdateServer(*original, server)
-	} else {
+	save := func() error {
+		if original != nil {
+			// Edit mode
+			return t.serverService.UpdateServer(*original, server)
+		}
 		// Add mode
-		err = t.serverService.AddServer(server)
+		return t.serverService.AddServer(server)
+	}
+	saved := func() {
+		t.refreshServerList()
+		t.handleFormCancel()
+		if original == nil {
+			t.prefetchHostKeys(server)
+		}
+	}
+	err := save()
+	if t.handleConflict(err, save, saved) {
+		return
 	}
 	if err != nil {
 		// Stay on form; show a small modal with the error
@@ -245,12 +522,14 @@ func (t *tui) handleServerSave(server domain.Server, original *domain.Server) {
 		t.app.SetRoot(modal, true)
 		return
 	}
-
-	t.refreshServerList()
-	t.handleFormCancel()
+	saved()
 }
 
 func (t *tui) handleServerDelete() {
+	if t.serverList.MarkedCount() > 0 {
+		t.handleBulkDelete()
+		return
+	}
 	if server, ok := t.serverList.GetSelectedServer(); ok {
 		if server.Readonly {
 			t.showStatusTempColor(fmt.Sprintf("Read-only: %s is defined in %s (cannot delete here)", server.Alias, server.SourceFile), "#FF6B6B")
@@ -265,6 +544,10 @@ func (t *tui) handleFormCancel() {
 }
 
 func (t *tui) handlePingSelected() {
+	if t.serverList.MarkedCount() > 0 {
+		t.handleBulkPing()
+		return
+	}
 	if server, ok := t.serverList.GetSelectedServer(); ok {
 		alias := server.Alias
 
@@ -272,6 +555,7 @@ func (t *tui) handlePingSelected() {
 		go func() {
 			up, dur, err := t.serverService.Ping(server)
 			t.app.QueueUpdateDraw(func() {
+				t.applyHealth()
 				if err != nil {
 					t.showStatusTempColor(fmt.Sprintf("Ping %s: DOWN (%v)", alias, err), "#FF6B6B")
 					return
@@ -286,15 +570,543 @@ func (t *tui) handlePingSelected() {
 	}
 }
 
+func (t *tui) handleProbe() {
+	server, ok := t.serverList.GetSelectedServer()
+	if !ok {
+		return
+	}
+	view := NewProbeView(NewAppHeader(t.version, t.commit, RepoURL), t.app, t.serverService, server).
+		OnDone(t.applyHealth).
+		OnClose(t.returnToMain).
+		Run(false)
+	t.app.SetRoot(view, true)
+}
+
+func (t *tui) handleExec() {
+	aliases := ""
+	if marked := t.serverList.MarkedServers(); len(marked) > 0 {
+		names := make([]string, 0, len(marked))
+		for _, s := range marked {
+			names = append(names, s.Alias)
+		}
+		aliases = strings.Join(names, ", ")
+	} else if server, ok := t.serverList.GetSelectedServer(); ok {
+		aliases = server.Alias
+	}
+	form := NewExecForm(aliases, "", func(req domain.ExecRequest) {
+		view := NewExecView(NewAppHeader(t.version, t.commit, RepoURL), t.app, t.serverService, req).
+			OnClose(t.returnToMain).
+			Run()
+		t.app.SetRoot(view, true)
+	}, t.returnToMain)
+	t.app.SetRoot(form, true)
+	t.app.SetFocus(form)
+}
+
+// startMonitor starts the health monitor of the current workspace; each round
+// updates the status column of the server list.
+func (t *tui) startMonitor() {
+	ss := t.serverService
+	ss.StartMonitor(func() {
+		t.app.QueueUpdateDraw(func() {
+			if t.serverService == ss {
+				t.applyHealth()
+			}
+		})
+	})
+}
+
+// applyHealth shows the latest probe results. The list is reloaded when they
+// decide the order or the content (reachability sort, is: filter), otherwise
+// the lines are updated in place.
+func (t *tui) applyHealth() {
+	query := ""
+	if t.searchVisible {
+		query = t.searchBar.InputField.GetText()
+	}
+	if t.sortMode == SortByReachabilityAsc || t.sortMode == SortByReachabilityDesc || strings.Contains(query, "is:") {
+		t.reloadServerList()
+		return
+	}
+	health := t.serverService.ServerHealth()
+	t.serverList.SetHealth(health)
+	if selected, ok := t.serverList.GetSelectedServer(); ok {
+		t.details.SetHealth(selected.Alias, health[selected.Alias])
+	}
+}
+
 func (t *tui) handleInstallSSHKey() {
-	if server, ok := t.serverList.GetSelectedServer(); ok {
-		alias := server.Alias
-		t.showStatusTemp(fmt.Sprintf("Installing key to %s…", alias))
+	if t.serverList.MarkedCount() > 0 {
+		t.handleBulkInstallKey()
+		return
+	}
+	server, ok := t.serverList.GetSelectedServer()
+	if !ok {
+		return
+	}
+	var wizard *KeyWizard
+	wizard = NewKeyWizard(NewAppHeader(t.version, t.commit, RepoURL), []string{server.Alias}).
+		OnSubmit(func(req KeyRequest) { t.handleKeyDeploy(wizard, req) }).
+		OnCancel(t.returnToMain)
+	t.app.SetRoot(wizard, true)
+}
+
+// handleKeyDeploy generates the key if asked, installs it on every server with
+// ssh-copy-id (which may prompt for passwords) and finally points the servers
+// at the new key.
+func (t *tui) handleKeyDeploy(wizard *KeyWizard, req KeyRequest) {
+	servers, _ := t.serverService.ListServers("")
+	byAlias := make(map[string]domain.Server, len(servers))
+	for _, s := range servers {
+		byAlias[s.Alias] = s
+	}
+	for _, alias := range req.Aliases {
+		if _, ok := byAlias[alias]; !ok {
+			wizard.ShowError(fmt.Sprintf("unknown server %q", alias))
+			return
+		}
+	}
+
+	publicKey := req.PublicKey
+	if req.Source == KeyGenerate {
+		path, err := t.serverService.GenerateKey(req.Spec)
+		if err != nil {
+			wizard.ShowError(err.Error())
+			return
+		}
+		publicKey = path
+	}
+
+	var deployed, failed []string
+	t.app.Suspend(func() {
+		for _, alias := range req.Aliases {
+			fmt.Printf("\n==> Installing %s on %s\n", describeKeyRequest(req), alias)
+			var err error
+			if req.Source == KeyPaste {
+				err = t.serverService.CopyPublicKey(alias, req.Pasted)
+			} else {
+				err = t.serverService.CopySSHKey(alias, publicKey)
+			}
+			if err != nil {
+				fmt.Printf("%s: %v\n", alias, err)
+				failed = append(failed, alias)
+				continue
+			}
+			deployed = append(deployed, alias)
+		}
+		if len(failed) > 0 {
+			fmt.Print("\nPress Enter to return to lazyssh…")
+			_, _ = bufio.NewReader(os.Stdin).ReadString('\n')
+		}
+	})
+
+	if req.SetIdentity && len(deployed) > 0 {
+		identity := displayConfigPath(privateKeyPath(publicKey))
+		targets := make([]domain.Server, 0, len(deployed))
+		for _, alias := range deployed {
+			targets = append(targets, byAlias[alias])
+		}
+		err := t.serverService.UpdateServers(targets, func(s *domain.Server) {
+			if !slices.Contains(s.IdentityFiles, identity) {
+				s.IdentityFiles = append(s.IdentityFiles, identity)
+			}
+			if req.IdentitiesOnly {
+				s.IdentitiesOnly = "yes"
+			}
+		})
+		if err != nil {
+			failed = append(failed, "config: "+strings.ReplaceAll(err.Error(), "\n", "; "))
+		}
+	}
+
+	t.refreshServerList()
+	t.returnToMain()
+	if len(failed) > 0 {
+		t.showStatusTempColor(fmt.Sprintf("Key installed on %d server(s); failed: %s", len(deployed), strings.Join(failed, ", ")), "#FF6B6B")
+		return
+	}
+	t.showStatusTemp(fmt.Sprintf("Key installed on %s", strings.Join(deployed, ", ")))
+}
+
+func (t *tui) handleKeys() {
+	keys, err := t.serverService.ListKeys()
+	if err != nil {
+		t.showStatusTempColor(fmt.Sprintf("Failed to list keys: %v", err), "#FF6B6B")
+		return
+	}
+	var view *KeysView
+	view = NewKeysView(NewAppHeader(t.version, t.commit, RepoURL)).
+		SetKeys(keys).
+		OnRefresh(func() {
+			if keys, err := t.serverService.ListKeys(); err == nil {
+				view.SetKeys(keys)
+			}
+		}).
+		OnClose(t.returnToMain)
+	t.app.SetRoot(view, true)
+}
+
+// handleAgent opens the ssh-agent screen, showing msg in red when it is not empty.
+func (t *tui) handleAgent(msg string) {
+	keys, err := t.serverService.ListAgentKeys()
+	view := NewAgentView(NewAppHeader(t.version, t.commit, RepoURL)).
+		SetKeys(keys, err).
+		OnAdd(t.handleAgentAdd).
+		OnRemove(t.showRemoveAgentKeyModal).
+		OnRemoveAll(t.showRemoveAllAgentKeysModal).
+		OnRefresh(func() { t.handleAgent("") }).
+		OnClose(func() {
+			t.refreshServerList()
+			t.returnToMain()
+		})
+	if msg != "" {
+		view.ShowError(msg)
+	}
+	t.app.SetRoot(view, true)
+}
+
+func (t *tui) handleAgentAdd() {
+	form := NewAgentAddForm(GetAvailableSSHKeys(), func(req AgentAddRequest) {
+		var err error
 		t.app.Suspend(func() {
-			_ = t.serverService.CopySSHKey(alias)
+			err = t.serverService.AddAgentKey(req.Path, req.Lifetime, req.Confirm)
 		})
-		t.refreshServerList()
+		if err != nil {
+			t.handleAgent(err.Error())
+			return
+		}
+		t.handleAgent("")
+	}, func() { t.handleAgent("") })
+	t.app.SetRoot(form, true)
+	t.app.SetFocus(form)
+}
+
+func (t *tui) showRemoveAgentKeyModal(key domain.AgentKey) {
+	name := key.Comment
+	if name == "" {
+		name = key.Fingerprint
+	}
+	t.showAgentConfirmModal(fmt.Sprintf("Remove %s from ssh-agent?", name), func() error {
+		return t.serverService.RemoveAgentKey(key.Fingerprint)
+	})
+}
+
+func (t *tui) showRemoveAllAgentKeysModal() {
+	t.showAgentConfirmModal("Remove all identities from ssh-agent?", t.serverService.RemoveAllAgentKeys)
+}
+
+func (t *tui) showAgentConfirmModal(text string, remove func() error) {
+	confirm := func() {
+		if err := remove(); err != nil {
+			t.handleAgent(err.Error())
+			return
+		}
+		t.handleAgent("")
+	}
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons([]string{"[yellow]C[-]ancel", "[yellow]R[-]emove"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			if buttonIndex == 1 {
+				confirm()
+				return
+			}
+			t.handleAgent("")
+		})
+	modal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'c', 'C':
+			t.handleAgent("")
+			return nil
+		case 'r', 'R':
+			confirm()
+			return nil
+		}
+		return event
+	})
+	t.app.SetRoot(modal, true)
+}
+
+func (t *tui) handleKnownHosts() {
+	server, ok := t.serverList.GetSelectedServer()
+	if !ok {
+		return
+	}
+	t.showKnownHosts(server, nil, nil)
+}
+
+// showKnownHosts opens the known_hosts screen of a server. When check is not
+// nil it is shown as is (with err), otherwise the recorded keys are read again.
+func (t *tui) showKnownHosts(server domain.Server, check *domain.HostKeyCheck, err error) {
+	var view *KnownHostsView
+	view = NewKnownHostsView(NewAppHeader(t.version, t.commit, RepoURL), server.Alias).
+		OnCheck(func() {
+			view.SetChecking()
+			go func() {
+				c, err := t.serverService.CheckHostKeys(server)
+				t.app.QueueUpdateDraw(func() { view.SetCheck(c, err) })
+			}()
+		}).
+		OnTrust(func(c domain.HostKeyCheck) {
+			t.showTrustHostKeysModal(server, c, func(err error) { t.showKnownHosts(server, &c, err) })
+		}).
+		OnRemove(func(c domain.HostKeyCheck) { t.showRemoveKnownHostModal(server, c) }).
+		OnClose(t.returnToMain)
+	if check != nil {
+		view.SetCheck(*check, err)
+	} else {
+		view.SetCheck(t.serverService.KnownHosts(server))
+	}
+	t.app.SetRoot(view, true)
+}
+
+// showTrustHostKeysModal asks to record the scanned keys of check; back is
+// called with the error, if any, when the dialog closes without recording.
+func (t *tui) showTrustHostKeysModal(server domain.Server, check domain.HostKeyCheck, back func(error)) {
+	text := fmt.Sprintf("Record these host keys for %s?\n\n%s", check.Host, formatHostKeys(check.Scanned))
+	if len(check.Recorded) > 0 {
+		text += "\n\nThe currently recorded keys are removed (a backup is kept)."
+	}
+	trust := func() {
+		if err := t.serverService.TrustHostKeys(server, check.Scanned); err != nil {
+			back(fmt.Errorf("failed to record host keys: %w", err))
+			return
+		}
+		t.showKnownHosts(server, nil, nil)
+	}
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons([]string{"[yellow]C[-]ancel", "[yellow]T[-]rust"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			if buttonIndex == 1 {
+				trust()
+				return
+			}
+			back(nil)
+		})
+	modal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'c', 'C':
+			back(nil)
+			return nil
+		case 't', 'T':
+			trust()
+			return nil
+		}
+		return event
+	})
+	t.app.SetRoot(modal, true)
+}
+
+func (t *tui) showRemoveKnownHostModal(server domain.Server, check domain.HostKeyCheck) {
+	remove := func() {
+		if _, err := t.serverService.RemoveKnownHost(server); err != nil {
+			t.showKnownHosts(server, &check, fmt.Errorf("failed to remove host keys: %w", err))
+			return
+		}
+		t.showKnownHosts(server, nil, nil)
+	}
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Remove the recorded host keys of %s?\n\nA backup of each changed known_hosts file is kept.", check.Host)).
+		AddButtons([]string{"[yellow]C[-]ancel", "[yellow]R[-]emove"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			if buttonIndex == 1 {
+				remove()
+				return
+			}
+			t.showKnownHosts(server, &check, nil)
+		})
+	modal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'c', 'C':
+			t.showKnownHosts(server, &check, nil)
+			return nil
+		case 'r', 'R':
+			remove()
+			return nil
+		}
+		return event
+	})
+	t.app.SetRoot(modal, true)
+}
+
+// prefetchHostKeys fetches the host keys of a newly added server in the
+// background and offers to record them, unless the user has left the list.
+func (t *tui) prefetchHostKeys(server domain.Server) {
+	go func() {
+		check, err := t.serverService.CheckHostKeys(server)
+		if err != nil || check.State != domain.HostKeyUnknown {
+			return
+		}
+		t.app.QueueUpdateDraw(func() {
+			if !t.serverList.HasFocus() {
+				return
+			}
+			t.showTrustHostKeysModal(server, check, func(err error) {
+				t.returnToMain()
+				if err != nil {
+					t.showStatusTempColor(err.Error(), "#FF6B6B")
+				}
+			})
+		})
+	}()
+}
+
+func (t *tui) handleFileBrowser() {
+	server, ok := t.serverList.GetSelectedServer()
+	if !ok {
+		return
+	}
+	view := NewFileBrowserView(NewAppHeader(t.version, t.commit, RepoURL), t.app, t.serverService, server.Alias).
+		OnClose(t.returnToMain).
+		Start()
+	t.app.SetRoot(view, true)
+}
+
+func (t *tui) handleTunnels() {
+	alias := ""
+	if server, ok := t.serverList.GetSelectedServer(); ok {
+		alias = server.Alias
 	}
+	t.showTunnels(alias, nil)
+}
+
+func (t *tui) showTunnels(alias string, err error) {
+	view := NewTunnelsView(NewAppHeader(t.version, t.commit, RepoURL), t.app, t.serverService, alias).
+		OnAdHoc(t.handleTunnelAdHoc).
+		OnClose(t.returnToMain).
+		Start()
+	if err != nil {
+		view.ShowError(err)
+	}
+	t.app.SetRoot(view, true)
+}
+
+func (t *tui) handleTunnelAdHoc(alias string) {
+	form := NewTunnelForm(alias, func(req TunnelRequest) {
+		_, err := t.serverService.StartTunnel(domain.TunnelSpec{
+			Alias:           req.Alias,
+			LocalForwards:   req.Local,
+			RemoteForwards:  req.Remote,
+			DynamicForwards: req.Dynamic,
+		})
+		t.showTunnels(alias, err)
+	}, func() { t.showTunnels(alias, nil) })
+	t.app.SetRoot(form, true)
+	t.app.SetFocus(form)
+}
+
+func (t *tui) handleControlMasters() {
+	alias := ""
+	if server, ok := t.serverList.GetSelectedServer(); ok {
+		alias = server.Alias
+	}
+	t.showControlMasters(alias, nil)
+}
+
+func (t *tui) showControlMasters(alias string, err error) {
+	view := NewControlMastersView(NewAppHeader(t.version, t.commit, RepoURL), t.app, t.serverService).
+		OnStop(func(m domain.ControlMaster) {
+			t.showControlMasters(m.Alias, t.serverService.StopControlMaster(m.Alias))
+		}).
+		OnExit(func(m domain.ControlMaster) {
+			t.showControlMasterModal(m, fmt.Sprintf("Exit the master of %s now?\nSessions multiplexed over it are closed.", m.Alias), func() error {
+				return t.serverService.ExitControlMaster(m.Alias)
+			})
+		}).
+		OnKill(func(m domain.ControlMaster) {
+			text := fmt.Sprintf("Remove the stale socket %s?", m.Path)
+			if m.PID > 0 {
+				text = fmt.Sprintf("Kill the master of %s (pid %d) and remove its socket?", m.Alias, m.PID)
+			}
+			t.showControlMasterModal(m, text, func() error {
+				return t.serverService.KillControlMaster(m)
+			})
+		}).
+		OnClose(t.returnToMain).
+		Load(alias)
+	if err != nil {
+		view.ShowError(err)
+	}
+	t.app.SetRoot(view, true)
+}
+
+func (t *tui) showControlMasterModal(m domain.ControlMaster, text string, action func() error) {
+	confirm := func() { t.showControlMasters(m.Alias, action()) }
+	cancel := func() { t.showControlMasters(m.Alias, nil) }
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons([]string{"[yellow]C[-]ancel", "[yellow]Y[-]es"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			if buttonIndex == 1 {
+				confirm()
+				return
+			}
+			cancel()
+		})
+	modal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'c', 'C':
+			cancel()
+			return nil
+		case 'y', 'Y':
+			confirm()
+			return nil
+		}
+		return event
+	})
+	t.app.SetRoot(modal, true)
+}
+
+func (t *tui) handleBackups() {
+	t.showBackups("")
+}
+
+// showBackups opens the backups screen with the backup at path selected.
+func (t *tui) showBackups(path string) *BackupsView {
+	view := NewBackupsView(NewAppHeader(t.version, t.commit, RepoURL), t.app, t.serverService).
+		OnRestore(t.handleBackupRestore).
+		OnClose(func() {
+			t.refreshServerList()
+			t.returnToMain()
+		}).
+		Load(path)
+	t.app.SetRoot(view, true)
+	return view
+}
+
+func (t *tui) handleBackupRestore(b domain.Backup) {
+	text := fmt.Sprintf("Restore %s from %s?\n\nThe current content is backed up first.", displayConfigPath(b.ConfigFile), b.CreatedAt.Format("2006-01-02 15:04:05"))
+	restore := func() {
+		if err := t.serverService.RestoreBackup(b); err != nil {
+			t.showBackups(b.Path).ShowError(err)
+			return
+		}
+		t.showBackups(b.Path).ShowMessage("Restored " + displayConfigPath(b.ConfigFile) + " from " + b.Name())
+	}
+	cancel := func() { t.showBackups(b.Path) }
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons([]string{"[yellow]C[-]ancel", "[yellow]R[-]estore"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			if buttonIndex == 1 {
+				restore()
+				return
+			}
+			cancel()
+		})
+	modal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'c', 'C':
+			cancel()
+			return nil
+		case 'r', 'R':
+			restore()
+			return nil
+		}
+		return event
+	})
+	t.app.SetRoot(modal, true)
 }
 
 func (t *tui) handleModalClose() {
@@ -329,7 +1141,7 @@ func (t *tui) handleRefreshBackground() {
 			if prevIdx >= 0 && prevIdx < t.serverList.List.GetItemCount() {
 				t.serverList.SetCurrentItem(prevIdx)
 				if srv, ok := t.serverList.GetSelectedServer(); ok {
-					t.details.UpdateServer(srv)
+					t.handleServerSelectionChange(srv)
 				}
 			}
 			t.showStatusTemp(fmt.Sprintf("Refreshed %d servers", len(servers)))
@@ -353,13 +1165,24 @@ func (t *tui) showDeleteConfirmModal(server domain.Server) {
 	msg := fmt.Sprintf("Delete server %s (%s@%s:%d)?\n\nThis action cannot be undone.",
 		server.Alias, server.User, server.Host, server.Port)
 
+	del := func() error { return t.serverService.DeleteServer(server) }
+	deleted := func() {
+		t.refreshServerList()
+		t.handleModalClose()
+	}
+	confirm := func() {
+		if !t.handleConflict(del(), del, deleted) {
+			deleted()
+		}
+	}
+
 	modal := tview.NewModal().
 		SetText(msg).
 		AddButtons([]string{"[yellow]C[-]ancel", "[yellow]D[-]elete"}).
 		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
 			if buttonIndex == 1 {
-				_ = t.serverService.DeleteServer(server)
-				t.refreshServerList()
+				confirm()
+				return
 			}
 			t.handleModalClose()
 		})
@@ -373,9 +1196,7 @@ func (t *tui) showDeleteConfirmModal(server domain.Server) {
 			return nil
 		case 'd', 'D':
 			// Delete
-			_ = t.serverService.DeleteServer(server)
-			t.refreshServerList()
-			t.handleModalClose()
+			confirm()
 			return nil
 		}
 		// ESC key already handled by default modal behavior
@@ -385,6 +1206,44 @@ func (t *tui) showDeleteConfirmModal(server domain.Server) {
 	t.app.SetRoot(modal, true)
 }
 
+func (t *tui) showDeleteProfileConfirmModal(profile domain.Server) {
+	if profile.Readonly {
+		t.showProfileError(profile.Alias, fmt.Sprintf("Read-only: %s is defined in %s (cannot delete here)", profile.Alias, profile.SourceFile))
+		return
+	}
+	remove := func() {
+		if err := t.serverService.DeleteProfile(profile); err != nil {
+			t.showProfileError(profile.Alias, fmt.Sprintf("Delete failed: %v", err))
+			return
+		}
+		t.handleProfiles("")
+	}
+
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Delete profile Host %s?\n\nServers matching it will no longer inherit its settings.", profile.Alias)).
+		AddButtons([]string{"[yellow]C[-]ancel", "[yellow]D[-]elete"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			if buttonIndex == 1 {
+				remove()
+				return
+			}
+			t.handleProfiles(profile.Alias)
+		})
+	modal.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'c', 'C':
+			t.handleProfiles(profile.Alias)
+			return nil
+		case 'd', 'D':
+			remove()
+			return nil
+		}
+		return event
+	})
+
+	t.app.SetRoot(modal, true)
+}
+
 func (t *tui) showEditTagsForm(server domain.Server) {
 	form := tview.NewForm()
 	form.SetBorder(true).
@@ -406,11 +1265,16 @@ func (t *tui) showEditTagsForm(server domain.Server) {
 
 		newServer := server
 		newServer.Tags = tags
-		_ = t.serverService.UpdateServer(server, newServer)
-		// Refresh UI and go back
-		t.refreshServerList()
-		t.returnToMain()
-		t.showStatusTemp("Tags updated")
+		save := func() error { return t.serverService.UpdateServer(server, newServer) }
+		saved := func() {
+			// Refresh UI and go back
+			t.refreshServerList()
+			t.returnToMain()
+			t.showStatusTemp("Tags updated")
+		}
+		if !t.handleConflict(save(), save, saved) {
+			saved()
+		}
 	})
 	form.AddButton("Cancel", func() { t.returnToMain() })
 	form.SetCancelFunc(func() { t.returnToMain() })
@@ -449,6 +1313,9 @@ func (t *tui) refreshServerList() {
 
 func (t *tui) returnToMain() {
 	t.app.SetRoot(t.root, true)
+	if t.configChanged {
+		t.reloadChangedConfig()
+	}
 }
 
 // showStatusTemp displays a temporary message in the status bar (default green) and then restores the default text.
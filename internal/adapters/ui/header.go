@@ -27,6 +27,8 @@ type AppHeader struct {
 	version   string
 	gitCommit string
 	repoURL   string
+	workspace string
+	center    *tview.TextView
 }
 
 func NewAppHeader(version, gitCommit, repoURL string) *AppHeader {
@@ -70,11 +72,22 @@ func (h *AppHeader) buildLeftSection(bg tcell.Color) *tview.TextView {
 }
 
 func (h *AppHeader) buildCenterSection(bg tcell.Color) *tview.TextView {
-	center := tview.NewTextView().
+	h.center = tview.NewTextView().
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignCenter)
-	center.SetBackgroundColor(bg)
+	h.center.SetBackgroundColor(bg)
+	h.renderCenter()
+	return h.center
+}
+
+// SetWorkspace shows the active workspace next to the version; empty hides it.
+func (h *AppHeader) SetWorkspace(name string) *AppHeader {
+	h.workspace = name
+	h.renderCenter()
+	return h
+}
 
+func (h *AppHeader) renderCenter() {
 	commit := shortCommit(h.gitCommit)
 
 	// Build tag-like chips for version, commit, and build time
@@ -88,9 +101,11 @@ func (h *AppHeader) buildCenterSection(bg tcell.Color) *tview.TextView {
 	if commitTag != "" {
 		text += "  " + commitTag
 	}
+	if h.workspace != "" {
+		text = makeTag("📂 "+h.workspace, "#F59E0B") + "  " + text // amber
+	}
 
-	center.SetText(text)
-	return center
+	h.center.SetText(text)
 }
 
 func (h *AppHeader) buildRightSection(bg tcell.Color) *tview.TextView {
@@ -0,0 +1,329 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// KeySource tells where the key deployed by the wizard comes from.
+type KeySource int
+
+const (
+	KeyExisting KeySource = iota
+	KeyGenerate
+	KeyPaste
+)
+
+var (
+	keySourceLabels = []string{"Existing public key", "Generate new keypair", "Paste public key"}
+	keyTypes        = []string{domain.KeyTypeEd25519, domain.KeyTypeECDSA, domain.KeyTypeRSA}
+	unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+)
+
+// KeyRequest is what the key wizard asks to do once submitted.
+type KeyRequest struct {
+	Source         KeySource
+	PublicKey      string // existing .pub file (KeyExisting)
+	Spec           domain.KeySpec
+	Pasted         string
+	Aliases        []string
+	SetIdentity    bool // add the key to the IdentityFiles of the deployed servers
+	IdentitiesOnly bool // with SetIdentity, also write IdentitiesOnly yes
+}
+
+// KeyWizard picks, generates or takes a pasted public key and the servers to
+// install it on. The fields after the source drop-down change with the source.
+type KeyWizard struct {
+	*tview.Flex
+	form   *tview.Form
+	status *tview.TextView
+
+	req         KeyRequest
+	confirm     string
+	servers     string
+	pubKeys     []string
+	defaultPath string
+
+	onSubmit func(KeyRequest)
+	onCancel func()
+}
+
+// NewKeyWizard opens the wizard for the given servers. Existing public keys are
+// looked up in ~/.ssh.
+func NewKeyWizard(header *AppHeader, aliases []string) *KeyWizard {
+	w := &KeyWizard{
+		Flex:    tview.NewFlex().SetDirection(tview.FlexRow),
+		form:    tview.NewForm(),
+		status:  tview.NewTextView(),
+		servers: strings.Join(aliases, ", "),
+		pubKeys: availablePublicKeys(),
+	}
+	w.req.Spec.Type = domain.KeyTypeEd25519
+	if len(w.pubKeys) == 0 {
+		w.req.Source = KeyGenerate
+	} else {
+		w.req.PublicKey = w.pubKeys[0]
+	}
+	w.defaultPath = defaultKeyPath(w.req.Spec.Type, aliases)
+	w.req.Spec.Path = w.defaultPath
+	w.build(header)
+	return w
+}
+
+func (w *KeyWizard) build(header *AppHeader) {
+	w.form.SetBorder(true).
+		SetTitle(" SSH Key ").
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+	w.form.AddDropDown("Key source:", keySourceLabels, int(w.req.Source), func(_ string, index int) {
+		if index < 0 || KeySource(index) == w.req.Source {
+			return
+		}
+		w.req.Source = KeySource(index)
+		w.rebuildFields()
+	})
+	w.rebuildFields()
+	w.form.AddButton("Deploy", w.submit)
+	w.form.AddButton("Cancel", w.cancel)
+	w.form.SetCancelFunc(w.cancel)
+
+	w.status.SetDynamicColors(true)
+	w.status.SetBackgroundColor(tcell.Color235)
+	w.status.SetTextAlign(tview.AlignCenter)
+	w.setHint()
+
+	w.Flex.AddItem(header, 2, 0, false).
+		AddItem(w.form, 0, 1, true).
+		AddItem(w.status, 1, 0, false)
+}
+
+// rebuildFields replaces every field after the source drop-down.
+func (w *KeyWizard) rebuildFields() {
+	for w.form.GetFormItemCount() > 1 {
+		w.form.RemoveFormItem(1)
+	}
+
+	switch w.req.Source {
+	case KeyExisting:
+		if len(w.pubKeys) == 0 {
+			w.form.AddTextView("Public key:", "[#888888]no public keys found in ~/.ssh[-]", 0, 1, true, false)
+			break
+		}
+		current := 0
+		for i, k := range w.pubKeys {
+			if k == w.req.PublicKey {
+				current = i
+			}
+		}
+		w.form.AddDropDown("Public key:", w.pubKeys, current, func(option string, index int) {
+			if index >= 0 {
+				w.req.PublicKey = option
+			}
+		})
+	case KeyGenerate:
+		current := 0
+		for i, t := range keyTypes {
+			if t == w.req.Spec.Type {
+				current = i
+			}
+		}
+		w.form.AddDropDown("Type:", keyTypes, current, func(option string, index int) {
+			if index < 0 || option == w.req.Spec.Type {
+				return
+			}
+			w.req.Spec.Type = option
+			w.req.Spec.Bits = 0
+			if bits, ok := w.form.GetFormItemByLabel("Bits:").(*tview.InputField); ok {
+				bits.SetText("")
+				bits.SetPlaceholder(defaultBitsHint(option))
+			}
+			// Follow the type in the file name unless the user changed it.
+			if w.req.Spec.Path == w.defaultPath {
+				w.defaultPath = defaultKeyPath(option, w.aliases())
+				w.req.Spec.Path = w.defaultPath
+				if path, ok := w.form.GetFormItemByLabel("File:").(*tview.InputField); ok {
+					path.SetText(w.defaultPath)
+				}
+			}
+		})
+		bits := ""
+		if w.req.Spec.Bits > 0 {
+			bits = strconv.Itoa(w.req.Spec.Bits)
+		}
+		w.form.AddInputField("Bits:", bits, 8, tview.InputFieldInteger, func(text string) {
+			w.req.Spec.Bits, _ = strconv.Atoi(text)
+		})
+		if field, ok := w.form.GetFormItemByLabel("Bits:").(*tview.InputField); ok {
+			field.SetPlaceholder(defaultBitsHint(w.req.Spec.Type))
+		}
+		w.form.AddInputField("File:", w.req.Spec.Path, 50, nil, func(text string) {
+			w.req.Spec.Path = strings.TrimSpace(text)
+		})
+		w.form.AddInputField("Comment:", w.req.Spec.Comment, 50, nil, func(text string) {
+			w.req.Spec.Comment = text
+		})
+		w.form.AddPasswordField("Passphrase:", w.req.Spec.Passphrase, 50, '*', func(text string) {
+			w.req.Spec.Passphrase = text
+		})
+		w.form.AddPasswordField("Confirm passphrase:", w.confirm, 50, '*', func(text string) {
+			w.confirm = text
+		})
+	case KeyPaste:
+		w.form.AddTextArea("Public key:", w.req.Pasted, 70, 4, 0, func(text string) {
+			w.req.Pasted = text
+		})
+	}
+
+	w.form.AddInputField("Servers:", w.servers, 50, nil, func(text string) {
+		w.servers = text
+	})
+	if w.req.Source != KeyPaste {
+		w.form.AddCheckbox("Use for these servers:", w.req.SetIdentity, func(checked bool) {
+			w.req.SetIdentity = checked
+		})
+		w.form.AddCheckbox("IdentitiesOnly yes:", w.req.IdentitiesOnly, func(checked bool) {
+			w.req.IdentitiesOnly = checked
+		})
+	}
+}
+
+func (w *KeyWizard) submit() {
+	req := w.req
+	req.Aliases = w.aliases()
+	if len(req.Aliases) == 0 {
+		w.ShowError("enter at least one server")
+		return
+	}
+	switch req.Source {
+	case KeyExisting:
+		if req.PublicKey == "" {
+			w.ShowError("no public key selected")
+			return
+		}
+	case KeyGenerate:
+		if req.Spec.Passphrase != w.confirm {
+			w.ShowError("passphrases do not match")
+			return
+		}
+	case KeyPaste:
+		req.SetIdentity = false
+		req.IdentitiesOnly = false
+		if strings.TrimSpace(req.Pasted) == "" {
+			w.ShowError("paste a public key")
+			return
+		}
+	}
+	if w.onSubmit != nil {
+		w.onSubmit(req)
+	}
+}
+
+func (w *KeyWizard) cancel() {
+	if w.onCancel != nil {
+		w.onCancel()
+	}
+}
+
+func (w *KeyWizard) aliases() []string {
+	return strings.FieldsFunc(w.servers, func(r rune) bool { return r == ',' || r == ' ' })
+}
+
+func (w *KeyWizard) OnSubmit(fn func(KeyRequest)) *KeyWizard {
+	w.onSubmit = fn
+	return w
+}
+
+func (w *KeyWizard) OnCancel(fn func()) *KeyWizard {
+	w.onCancel = fn
+	return w
+}
+
+// ShowError replaces the hint bar with an error until the next key press.
+func (w *KeyWizard) ShowError(msg string) {
+	w.status.SetText("[#FF6B6B]" + tview.Escape(msg) + "[-]")
+	w.form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		w.setHint()
+		w.form.SetInputCapture(nil)
+		return event
+	})
+}
+
+func (w *KeyWizard) setHint() {
+	w.status.SetText("[white]Tab[-] Next field  • [white]Enter[-] Select  • [white]Esc[-] Cancel")
+}
+
+// availablePublicKeys lists the .pub files in ~/.ssh, in tilde notation.
+func availablePublicKeys() []string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	matches, _ := filepath.Glob(filepath.Join(home, ".ssh", "*.pub"))
+	keys := make([]string, 0, len(matches))
+	for _, m := range matches {
+		keys = append(keys, "~/.ssh/"+filepath.Base(m))
+	}
+	return keys
+}
+
+// defaultKeyPath suggests ~/.ssh/id_<type>_<alias> for a key made for one server.
+func defaultKeyPath(keyType string, aliases []string) string {
+	name := "id_" + keyType
+	if len(aliases) == 1 {
+		if suffix := strings.Trim(unsafeFileChars.ReplaceAllString(aliases[0], "_"), "_."); suffix != "" {
+			name += "_" + suffix
+		}
+	}
+	return "~/.ssh/" + name
+}
+
+func defaultBitsHint(keyType string) string {
+	switch keyType {
+	case domain.KeyTypeECDSA:
+		return "256"
+	case domain.KeyTypeRSA:
+		return "4096"
+	default:
+		return "n/a"
+	}
+}
+
+// privateKeyPath returns the private key matching a .pub file.
+func privateKeyPath(publicKey string) string {
+	return strings.TrimSuffix(publicKey, ".pub")
+}
+
+// describeKeyRequest summarises the key for status messages.
+func describeKeyRequest(req KeyRequest) string {
+	switch req.Source {
+	case KeyGenerate:
+		return fmt.Sprintf("%s key %s", req.Spec.Type, req.Spec.Path)
+	case KeyPaste:
+		return "pasted key"
+	default:
+		return req.PublicKey
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// KeysView lists the private keys in ~/.ssh and those referenced by servers,
+// with their details and the servers that use them.
+type KeysView struct {
+	*tview.Flex
+	list      *tview.List
+	details   *tview.TextView
+	keys      []domain.SSHKey
+	onRefresh func()
+	onClose   func()
+}
+
+func NewKeysView(header *AppHeader) *KeysView {
+	v := &KeysView{
+		Flex:    tview.NewFlex().SetDirection(tview.FlexRow),
+		list:    tview.NewList(),
+		details: tview.NewTextView(),
+	}
+	v.build(header)
+	return v
+}
+
+func (v *KeysView) build(header *AppHeader) {
+	v.list.ShowSecondaryText(false)
+	v.list.SetBorder(true).
+		SetTitle(" Keys ").
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+	v.list.
+		SetSelectedBackgroundColor(tcell.Color24).
+		SetSelectedTextColor(tcell.Color255).
+		SetHighlightFullLine(true)
+	v.list.SetChangedFunc(func(index int, _ string, _ string, _ rune) {
+		v.showKey(index)
+	})
+
+	v.details.SetDynamicColors(true).
+		SetWrap(true).
+		SetBorder(true).
+		SetTitle(" Details ").
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+
+	hint := tview.NewTextView().SetDynamicColors(true)
+	hint.SetBackgroundColor(tcell.Color235)
+	hint.SetTextAlign(tview.AlignCenter)
+	hint.SetText("[white]↑↓[-] Navigate  • [white]r[-] Refresh  • [white]Esc[-] Back")
+
+	content := tview.NewFlex().SetDirection(tview.FlexColumn).
+		AddItem(v.list, 0, 3, true).
+		AddItem(v.details, 0, 2, false)
+
+	v.Flex.AddItem(header, 2, 0, false).
+		AddItem(content, 0, 1, true).
+		AddItem(hint, 1, 0, false)
+
+	v.Flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || event.Rune() == 'q' {
+			if v.onClose != nil {
+				v.onClose()
+			}
+			return nil
+		}
+		if event.Rune() == 'r' && v.onRefresh != nil {
+			v.onRefresh()
+			return nil
+		}
+		return event
+	})
+}
+
+// SetKeys shows the given keys, keeping the selection on the same path when possible.
+func (v *KeysView) SetKeys(keys []domain.SSHKey) *KeysView {
+	selected := ""
+	if i := v.list.GetCurrentItem(); i >= 0 && i < len(v.keys) {
+		selected = v.keys[i].Path
+	}
+	v.keys = keys
+
+	v.list.Clear()
+	current := 0
+	for i, k := range keys {
+		v.list.AddItem(formatKeyLine(k), "", 0, nil)
+		if k.Path == selected {
+			current = i
+		}
+	}
+	if len(keys) == 0 {
+		v.details.SetText("No private keys found in ~/.ssh and no server references an IdentityFile.\n\nPress [white]K[-] on a server to generate one.")
+		return v
+	}
+	v.list.SetCurrentItem(current)
+	v.showKey(current)
+	return v
+}
+
+func (v *KeysView) OnRefresh(fn func()) *KeysView {
+	v.onRefresh = fn
+	return v
+}
+
+func (v *KeysView) OnClose(fn func()) *KeysView {
+	v.onClose = fn
+	return v
+}
+
+func formatKeyLine(k domain.SSHKey) string {
+	text := fmt.Sprintf("[white::b]%s[-::-]", tview.Escape(displayConfigPath(k.Path)))
+	if k.Type != "" {
+		text += fmt.Sprintf(" [#888888]%s %d[-]", k.Type, k.Bits)
+	}
+	if k.Encrypted {
+		text += " 🔒"
+	}
+	if k.InAgent {
+		text += " [#A0FFA0]agent[-]"
+	}
+	switch {
+	case k.Missing:
+		text += " [#FF6B6B]missing[-]"
+	case k.Unused():
+		text += " [#FFCC66]unused[-]"
+	}
+	return text
+}
+
+func (v *KeysView) showKey(index int) {
+	if index < 0 || index >= len(v.keys) {
+		return
+	}
+	k := v.keys[index]
+	text := fmt.Sprintf("[::b]%s[-]\n\n", tview.Escape(displayConfigPath(k.Path)))
+	if k.Missing {
+		text += "  [#FF6B6B]The file does not exist, but servers reference it.[-]\n"
+	} else {
+		field := func(label, value string) {
+			if value == "" {
+				value = "[#888888](unknown)[-]"
+			} else {
+				value = "[white]" + tview.Escape(value) + "[-]"
+			}
+			text += fmt.Sprintf("  %s: %s\n", label, value)
+		}
+		bits := ""
+		if k.Bits > 0 {
+			bits = fmt.Sprint(k.Bits)
+		}
+		field("Type", k.Type)
+		field("Bits", bits)
+		field("Fingerprint", k.Fingerprint)
+		field("Comment", k.Comment)
+		text += fmt.Sprintf("  Public key (.pub): [white]%s[-]\n", yesNo(k.HasPublicKey))
+		text += fmt.Sprintf("  Passphrase: [white]%s[-]\n", yesNo(k.Encrypted))
+		text += fmt.Sprintf("  In ssh-agent: [white]%s[-]\n", yesNo(k.InAgent))
+	}
+
+	text += "\n[::b]Used by:[-]\n"
+	switch {
+	case len(k.Servers) > 0:
+		text += "  " + tview.Escape(strings.Join(k.Servers, ", ")) + "\n"
+	case k.Default:
+		text += "  [#888888]no IdentityFile references it, but ssh tries it by default[-]\n"
+	default:
+		text += "  [#FFCC66]unused[-]\n"
+	}
+	v.details.SetText(text)
+	v.details.ScrollToBeginning()
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,200 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// KnownHostsView shows the host keys recorded for a server and, once checked
+// with ssh-keyscan, whether the server still presents one of them.
+type KnownHostsView struct {
+	*tview.Flex
+	details  *tview.TextView
+	alias    string
+	check    domain.HostKeyCheck
+	checking bool
+	err      error
+	onCheck  func()
+	onTrust  func(domain.HostKeyCheck)
+	onRemove func(domain.HostKeyCheck)
+	onClose  func()
+}
+
+func NewKnownHostsView(header *AppHeader, alias string) *KnownHostsView {
+	v := &KnownHostsView{
+		Flex:    tview.NewFlex().SetDirection(tview.FlexRow),
+		details: tview.NewTextView(),
+		alias:   alias,
+	}
+	v.build(header)
+	return v
+}
+
+func (v *KnownHostsView) build(header *AppHeader) {
+	v.details.SetDynamicColors(true).
+		SetWrap(true).
+		SetBorder(true).
+		SetTitle(fmt.Sprintf(" Known Hosts: %s ", tview.Escape(v.alias))).
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+
+	hint := tview.NewTextView().SetDynamicColors(true)
+	hint.SetBackgroundColor(tcell.Color235)
+	hint.SetTextAlign(tview.AlignCenter)
+	hint.SetText("[white]s[-] Check server  • [white]u[-] Trust presented keys  • [white]d[-] Remove recorded keys  • [white]Esc[-] Back")
+
+	v.Flex.AddItem(header, 2, 0, false).
+		AddItem(v.details, 0, 1, true).
+		AddItem(hint, 1, 0, false)
+
+	v.Flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || event.Rune() == 'q' {
+			if v.onClose != nil {
+				v.onClose()
+			}
+			return nil
+		}
+		switch event.Rune() {
+		case 's':
+			if !v.checking && v.onCheck != nil {
+				v.onCheck()
+			}
+			return nil
+		case 'u':
+			if len(v.check.Scanned) > 0 && v.onTrust != nil {
+				v.onTrust(v.check)
+			}
+			return nil
+		case 'd':
+			if len(v.check.Recorded) > 0 && v.onRemove != nil {
+				v.onRemove(v.check)
+			}
+			return nil
+		}
+		return event
+	})
+}
+
+// SetCheck shows what is known about the server's host keys; err is the error
+// of the last lookup or scan.
+func (v *KnownHostsView) SetCheck(check domain.HostKeyCheck, err error) *KnownHostsView {
+	v.check = check
+	v.err = err
+	v.checking = false
+	v.render()
+	return v
+}
+
+// SetChecking marks a scan as running.
+func (v *KnownHostsView) SetChecking() *KnownHostsView {
+	v.checking = true
+	v.err = nil
+	v.render()
+	return v
+}
+
+func (v *KnownHostsView) OnCheck(fn func()) *KnownHostsView {
+	v.onCheck = fn
+	return v
+}
+
+func (v *KnownHostsView) OnTrust(fn func(domain.HostKeyCheck)) *KnownHostsView {
+	v.onTrust = fn
+	return v
+}
+
+func (v *KnownHostsView) OnRemove(fn func(domain.HostKeyCheck)) *KnownHostsView {
+	v.onRemove = fn
+	return v
+}
+
+func (v *KnownHostsView) OnClose(fn func()) *KnownHostsView {
+	v.onClose = fn
+	return v
+}
+
+func (v *KnownHostsView) render() {
+	c := v.check
+	files := make([]string, 0, len(c.Files))
+	for _, f := range c.Files {
+		files = append(files, displayConfigPath(f))
+	}
+	text := fmt.Sprintf("[::b]%s[-]\n\n  Looked up as: [white]%s[-]\n  Files: [white]%s[-]\n",
+		tview.Escape(v.alias), tview.Escape(c.Host), tview.Escape(strings.Join(files, ", ")))
+
+	text += "\n[::b]Recorded:[-]\n"
+	if len(c.Recorded) == 0 {
+		text += "  [#888888](none)[-]\n"
+	}
+	for _, k := range c.Recorded {
+		note := ""
+		if k.Hashed {
+			note += " hashed"
+		}
+		if k.Marker != "" {
+			note += " " + k.Marker
+		}
+		text += fmt.Sprintf("  [white]%s[-] %s [#888888]%s:%d%s[-]\n",
+			k.Type, k.Fingerprint, tview.Escape(displayConfigPath(k.File)), k.Line, note)
+	}
+
+	text += "\n[::b]Presented by the server:[-]\n"
+	switch {
+	case v.checking:
+		text += "  [#888888]Running ssh-keyscan…[-]\n"
+	case len(c.Scanned) == 0:
+		text += "  [#888888]Not checked yet. Press s to fetch the keys with ssh-keyscan.[-]\n"
+	}
+	for _, k := range c.Scanned {
+		text += fmt.Sprintf("  [white]%s[-] %s\n", k.Type, k.Fingerprint)
+	}
+
+	if len(c.Scanned) > 0 {
+		text += "\n" + describeHostKeyState(c.State) + "\n"
+	}
+	if v.err != nil {
+		text += "\n[#FF6B6B]" + tview.Escape(v.err.Error()) + "[-]\n"
+	}
+	v.details.SetText(text)
+}
+
+func describeHostKeyState(state domain.HostKeyState) string {
+	switch state {
+	case domain.HostKeyMatch:
+		return "[#A0FFA0]✓ The server presents a recorded key.[-]"
+	case domain.HostKeyMismatch:
+		return "[#FF6B6B]✗ None of the recorded keys matches. The server may have been rebuilt — or someone is intercepting the connection. Verify the fingerprints out of band before pressing u.[-]"
+	case domain.HostKeyUnknown:
+		return "[#FFCC66]? No key is recorded yet; ssh will ask to confirm on first connect. Press u to trust the keys above.[-]"
+	default:
+		return ""
+	}
+}
+
+// formatHostKeys lists keys with their fingerprints for confirmation dialogs.
+func formatHostKeys(keys []domain.HostKey) string {
+	lines := make([]string, 0, len(keys))
+	for _, k := range keys {
+		lines = append(lines, k.Type+" "+k.Fingerprint)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,171 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// MatchBlocksView lists the Match blocks of the SSH config with their criteria and settings.
+type MatchBlocksView struct {
+	*tview.Flex
+	list    *tview.List
+	details *tview.TextView
+	blocks  []domain.ConfigBlock
+	onClose func()
+}
+
+func NewMatchBlocksView(header *AppHeader) *MatchBlocksView {
+	v := &MatchBlocksView{
+		Flex:    tview.NewFlex().SetDirection(tview.FlexRow),
+		list:    tview.NewList(),
+		details: tview.NewTextView(),
+	}
+	v.build(header)
+	return v
+}
+
+func (v *MatchBlocksView) build(header *AppHeader) {
+	v.list.ShowSecondaryText(false)
+	v.list.SetBorder(true).
+		SetTitle(" Match Blocks ").
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+	v.list.
+		SetSelectedBackgroundColor(tcell.Color24).
+		SetSelectedTextColor(tcell.Color255).
+		SetHighlightFullLine(true)
+	v.list.SetChangedFunc(func(index int, _ string, _ string, _ rune) {
+		v.showBlock(index)
+	})
+
+	v.details.SetDynamicColors(true).
+		SetWrap(true).
+		SetBorder(true).
+		SetTitle(" Details ").
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+
+	hint := tview.NewTextView().SetDynamicColors(true)
+	hint.SetBackgroundColor(tcell.Color235)
+	hint.SetTextAlign(tview.AlignCenter)
+	hint.SetText("[white]↑↓[-] Navigate  • [white]Esc[-] Back")
+
+	content := tview.NewFlex().SetDirection(tview.FlexColumn).
+		AddItem(v.list, 0, 3, true).
+		AddItem(v.details, 0, 2, false)
+
+	v.Flex.AddItem(header, 2, 0, false).
+		AddItem(content, 0, 1, true).
+		AddItem(hint, 1, 0, false)
+
+	v.Flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || event.Rune() == 'q' {
+			if v.onClose != nil {
+				v.onClose()
+			}
+			return nil
+		}
+		return event
+	})
+}
+
+// SetBlocks shows the Match blocks among the given config blocks.
+func (v *MatchBlocksView) SetBlocks(blocks []domain.ConfigBlock) *MatchBlocksView {
+	v.blocks = v.blocks[:0]
+	for _, b := range blocks {
+		if b.Kind == domain.BlockMatch {
+			v.blocks = append(v.blocks, b)
+		}
+	}
+
+	v.list.Clear()
+	for _, b := range v.blocks {
+		v.list.AddItem(fmt.Sprintf("[white::b]%s[-] [#888888]%s[-]", tview.Escape(b.Header()), blockLocation(b)), "", 0, nil)
+	}
+	if len(v.blocks) == 0 {
+		v.details.SetText("No Match blocks found in the SSH config.")
+		return v
+	}
+	v.list.SetCurrentItem(0)
+	v.showBlock(0)
+	return v
+}
+
+func (v *MatchBlocksView) OnClose(fn func()) *MatchBlocksView {
+	v.onClose = fn
+	return v
+}
+
+func (v *MatchBlocksView) showBlock(index int) {
+	if index < 0 || index >= len(v.blocks) {
+		return
+	}
+	b := v.blocks[index]
+	text := fmt.Sprintf("[::b]%s[-]\n\n  Source: [white]%s[-]\n", tview.Escape(b.Header()), blockLocation(b))
+
+	text += "\n[::b]Criteria:[-]\n"
+	for _, c := range b.Criteria {
+		text += fmt.Sprintf("  %s\n", describeCriterion(c))
+	}
+
+	text += "\n[::b]Settings:[-]\n"
+	if len(b.Settings) == 0 {
+		text += "  [#888888](none)[-]\n"
+	}
+	for _, s := range b.Settings {
+		text += fmt.Sprintf("  %s: [white]%s[-]\n", s.Key, tview.Escape(s.Value))
+	}
+	v.details.SetText(text)
+	v.details.ScrollToBeginning()
+}
+
+// describeCriterion explains a Match criterion in plain words.
+func describeCriterion(c domain.MatchCriterion) string {
+	not := ""
+	if c.Negated {
+		not = "not "
+	}
+	arg := "[white]" + tview.Escape(c.Argument) + "[-]"
+	switch c.Keyword {
+	case "all":
+		return "always matches"
+	case "canonical":
+		return not + "during the canonicalization pass"
+	case "final":
+		return not + "during the final pass"
+	case "host":
+		return "target host name " + not + "matching " + arg
+	case "originalhost":
+		return "host as typed " + not + "matching " + arg
+	case "user":
+		return "remote user " + not + "matching " + arg
+	case "localuser":
+		return "local user " + not + "matching " + arg
+	case "exec":
+		if c.Negated {
+			return "command " + arg + " fails"
+		}
+		return "command " + arg + " succeeds"
+	default:
+		return tview.Escape(c.String())
+	}
+}
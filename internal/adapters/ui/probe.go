@@ -0,0 +1,193 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/core/ports"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// probeStages are the stages shown by the probe view, in order.
+var probeStages = []domain.ProbeStage{
+	domain.ProbeResolved,
+	domain.ProbeConnected,
+	domain.ProbeBanner,
+	domain.ProbeKeyExchange,
+	domain.ProbeAuthenticated,
+}
+
+// ProbeView shows an SSH-level probe of a server: how far the connection got,
+// the server's banner and host key, and why it stopped.
+type ProbeView struct {
+	*tview.Flex
+	app     *tview.Application
+	service ports.ServerService
+	server  domain.Server
+
+	result  *tview.TextView
+	status  *tview.TextView
+	running bool
+	onDone  func()
+	onClose func()
+}
+
+func NewProbeView(header *AppHeader, app *tview.Application, ss ports.ServerService, server domain.Server) *ProbeView {
+	v := &ProbeView{
+		Flex:    tview.NewFlex().SetDirection(tview.FlexRow),
+		app:     app,
+		service: ss,
+		server:  server,
+		result:  tview.NewTextView(),
+		status:  tview.NewTextView(),
+	}
+	v.build(header)
+	return v
+}
+
+func (v *ProbeView) build(header *AppHeader) {
+	v.result.SetDynamicColors(true).
+		SetWrap(true).
+		SetBorder(true).
+		SetTitle(" SSH probe: " + v.server.Alias + " ").
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+
+	v.status.SetDynamicColors(true)
+	v.status.SetBackgroundColor(tcell.Color235)
+	v.status.SetTextAlign(tview.AlignCenter)
+	v.status.SetText("[white]r[-] Probe again  • [white]a[-] Probe with authentication (ssh BatchMode)  • [white]Esc[-] Back")
+
+	v.Flex.AddItem(header, 2, 0, false).
+		AddItem(v.result, 0, 1, true).
+		AddItem(v.status, 1, 0, false)
+
+	v.Flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			if v.onClose != nil {
+				v.onClose()
+			}
+			return nil
+		}
+		switch event.Rune() {
+		case 'q':
+			if v.onClose != nil {
+				v.onClose()
+			}
+			return nil
+		case 'r':
+			v.Run(false)
+			return nil
+		case 'a':
+			v.Run(true)
+			return nil
+		}
+		return event
+	})
+}
+
+// Run probes the server in the background; with auth it also tries to log in.
+func (v *ProbeView) Run(auth bool) *ProbeView {
+	if v.running {
+		return v
+	}
+	v.running = true
+	what := "banner and host key"
+	if auth {
+		what = "authentication"
+	}
+	v.result.SetText(fmt.Sprintf("\n  Probing [white]%s[-] (%s)…", tview.Escape(v.server.Alias), what))
+	go func() {
+		r := v.service.ProbeServer(v.server, auth)
+		v.app.QueueUpdateDraw(func() {
+			v.running = false
+			v.result.SetText(renderProbe(r, auth))
+			if v.onDone != nil {
+				v.onDone()
+			}
+		})
+	}()
+	return v
+}
+
+// OnDone is called after each probe, e.g. to refresh the health column.
+func (v *ProbeView) OnDone(fn func()) *ProbeView {
+	v.onDone = fn
+	return v
+}
+
+func (v *ProbeView) OnClose(fn func()) *ProbeView {
+	v.onClose = fn
+	return v
+}
+
+func renderProbe(r domain.ProbeResult, auth bool) string {
+	var b strings.Builder
+	verdict := "[#A0FFA0]● reachable[-]"
+	if !r.OK() {
+		verdict = fmt.Sprintf("[#FF6B6B]✗ %s error[-]", r.Class)
+	}
+	fmt.Fprintf(&b, "[::b]%s[-]  %s  [#888888]in %s[-]\n\n", tview.Escape(r.Alias), verdict, formatLatency(r.Total))
+	if r.Address != "" {
+		fmt.Fprintf(&b, "  Address: [white]%s[-]\n", tview.Escape(r.Address))
+	}
+	if r.Via != "" {
+		fmt.Fprintf(&b, "  Via: [white]%s[-]\n", tview.Escape(r.Via))
+	}
+	b.WriteString("\n[::b]Stages:[-]\n")
+
+	timings := make(map[domain.ProbeStage]time.Duration, len(r.Timings))
+	for _, t := range r.Timings {
+		timings[t.Stage] = t.Duration
+	}
+	for _, stage := range probeStages {
+		d, reached := timings[stage]
+		switch {
+		case reached:
+			fmt.Fprintf(&b, "  [#A0FFA0]✓[-] %-15s [#888888]%s[-]\n", stage, formatLatency(d))
+		case !r.OK() && stage == r.Stage+1:
+			fmt.Fprintf(&b, "  [#FF6B6B]✗[-] %-15s [#FF6B6B]%s[-]\n", stage, r.Class)
+		case stage == domain.ProbeAuthenticated && !auth && r.OK():
+			fmt.Fprintf(&b, "  [#888888]–[-] %-15s [#888888]not tried (press a)[-]\n", stage)
+		case stage == domain.ProbeConnected && r.Via != "" && r.Stage > stage:
+			fmt.Fprintf(&b, "  [#A0FFA0]✓[-] %-15s [#888888]through the proxy[-]\n", stage)
+		default:
+			fmt.Fprintf(&b, "  [#888888]–[-] %s\n", stage)
+		}
+	}
+
+	if r.Banner != "" || r.HostKey != "" {
+		b.WriteString("\n[::b]Server:[-]\n")
+	}
+	if r.Banner != "" {
+		fmt.Fprintf(&b, "  Banner: [white]%s[-]\n", tview.Escape(r.Banner))
+	}
+	if r.HostKey != "" {
+		fmt.Fprintf(&b, "  Host key: [white]%s[-]\n", tview.Escape(r.HostKey))
+	}
+	if len(r.ServerHostKeys) > 0 {
+		fmt.Fprintf(&b, "  Offered host keys: [#888888]%s[-]\n", tview.Escape(strings.Join(r.ServerHostKeys, ", ")))
+	}
+	if r.Error != "" {
+		fmt.Fprintf(&b, "\n  Error: [#FF6B6B]%s[-]\n", tview.Escape(r.Error))
+	}
+	return b.String()
+}
@@ -0,0 +1,218 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// ProfilesView lists pattern Host blocks (e.g. "Host *.prod") that share settings
+// between servers, and lets the user add, edit or delete them.
+type ProfilesView struct {
+	*tview.Flex
+	list     *tview.List
+	details  *tview.TextView
+	profiles []domain.Server
+	blocks   []domain.ConfigBlock
+	onAdd    func()
+	onEdit   func(domain.Server)
+	onDelete func(domain.Server)
+	onClose  func()
+}
+
+func NewProfilesView(header *AppHeader) *ProfilesView {
+	v := &ProfilesView{
+		Flex:    tview.NewFlex().SetDirection(tview.FlexRow),
+		list:    tview.NewList(),
+		details: tview.NewTextView(),
+	}
+	v.build(header)
+	return v
+}
+
+func (v *ProfilesView) build(header *AppHeader) {
+	v.list.ShowSecondaryText(false)
+	v.list.SetBorder(true).
+		SetTitle(" Profiles ").
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+	v.list.
+		SetSelectedBackgroundColor(tcell.Color24).
+		SetSelectedTextColor(tcell.Color255).
+		SetHighlightFullLine(true)
+	v.list.SetChangedFunc(func(index int, _ string, _ string, _ rune) {
+		v.showProfile(index)
+	})
+
+	v.details.SetDynamicColors(true).
+		SetWrap(true).
+		SetBorder(true).
+		SetTitle(" Details ").
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+
+	hint := tview.NewTextView().SetDynamicColors(true)
+	hint.SetBackgroundColor(tcell.Color235)
+	hint.SetTextAlign(tview.AlignCenter)
+	hint.SetText("[white]↑↓[-] Navigate  • [white]a[-] Add  • [white]e/Enter[-] Edit  • [white]d[-] Delete  • [white]Esc[-] Back")
+
+	content := tview.NewFlex().SetDirection(tview.FlexColumn).
+		AddItem(v.list, 0, 2, true).
+		AddItem(v.details, 0, 3, false)
+
+	v.Flex.AddItem(header, 2, 0, false).
+		AddItem(content, 0, 1, true).
+		AddItem(hint, 1, 0, false)
+
+	v.Flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			if v.onClose != nil {
+				v.onClose()
+			}
+			return nil
+		}
+		if event.Key() == tcell.KeyEnter {
+			v.edit()
+			return nil
+		}
+		switch event.Rune() {
+		case 'q':
+			if v.onClose != nil {
+				v.onClose()
+			}
+			return nil
+		case 'a':
+			if v.onAdd != nil {
+				v.onAdd()
+			}
+			return nil
+		case 'e':
+			v.edit()
+			return nil
+		case 'd':
+			if p, ok := v.selected(); ok && v.onDelete != nil {
+				v.onDelete(p)
+			}
+			return nil
+		}
+		return event
+	})
+}
+
+// SetProfiles shows the given profiles. blocks are the config blocks of the same
+// files, used to display each profile's settings as written.
+func (v *ProfilesView) SetProfiles(profiles []domain.Server, blocks []domain.ConfigBlock) *ProfilesView {
+	v.profiles = profiles
+	v.blocks = blocks
+
+	v.list.Clear()
+	for _, p := range profiles {
+		text := fmt.Sprintf("[white::b]%s[-::-]", tview.Escape(p.Alias))
+		if p.Readonly {
+			text += " [#888888](read-only)[-]"
+		}
+		v.list.AddItem(text, "", 0, nil)
+	}
+	if len(profiles) == 0 {
+		v.details.SetText("No profiles yet.\n\nA profile is a Host block with wildcard patterns, e.g. [white]Host *.prod.example.com[-].\nPress [white]a[-] to add one.")
+		return v
+	}
+	v.list.SetCurrentItem(0)
+	v.showProfile(0)
+	return v
+}
+
+// Select moves the selection to the profile with the given patterns, if present.
+func (v *ProfilesView) Select(alias string) *ProfilesView {
+	for i, p := range v.profiles {
+		if p.Alias == alias {
+			v.list.SetCurrentItem(i)
+			v.showProfile(i)
+			break
+		}
+	}
+	return v
+}
+
+func (v *ProfilesView) OnAdd(fn func()) *ProfilesView {
+	v.onAdd = fn
+	return v
+}
+
+func (v *ProfilesView) OnEdit(fn func(domain.Server)) *ProfilesView {
+	v.onEdit = fn
+	return v
+}
+
+func (v *ProfilesView) OnDelete(fn func(domain.Server)) *ProfilesView {
+	v.onDelete = fn
+	return v
+}
+
+func (v *ProfilesView) OnClose(fn func()) *ProfilesView {
+	v.onClose = fn
+	return v
+}
+
+func (v *ProfilesView) selected() (domain.Server, bool) {
+	i := v.list.GetCurrentItem()
+	if i < 0 || i >= len(v.profiles) {
+		return domain.Server{}, false
+	}
+	return v.profiles[i], true
+}
+
+func (v *ProfilesView) edit() {
+	if p, ok := v.selected(); ok && v.onEdit != nil {
+		v.onEdit(p)
+	}
+}
+
+func (v *ProfilesView) showProfile(index int) {
+	if index < 0 || index >= len(v.profiles) {
+		return
+	}
+	p := v.profiles[index]
+	text := fmt.Sprintf("[::b]Host %s[-]\n\n  Source: [white]%s[-]\n  Read-only: [white]%t[-]\n",
+		tview.Escape(p.Alias), p.SourceFile, p.Readonly)
+
+	text += "\n[::b]Settings:[-]\n"
+	block, ok := v.blockFor(p)
+	if !ok || len(block.Settings) == 0 {
+		text += "  [#888888](none)[-]\n"
+	}
+	for _, s := range block.Settings {
+		text += fmt.Sprintf("  %s: [white]%s[-]\n", s.Key, tview.Escape(s.Value))
+	}
+	v.details.SetText(text)
+	v.details.ScrollToBeginning()
+}
+
+// blockFor finds the config block a profile was read from.
+func (v *ProfilesView) blockFor(p domain.Server) (domain.ConfigBlock, bool) {
+	header := "Host " + p.Alias
+	for _, b := range v.blocks {
+		if b.Kind == domain.BlockHost && b.SourceFile == p.SourceFile && b.Header() == header {
+			return b, true
+		}
+	}
+	return domain.ConfigBlock{}, false
+}
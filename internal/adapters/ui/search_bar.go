@@ -15,6 +15,8 @@
 package ui
 
 import (
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
@@ -28,8 +30,13 @@ type SearchBar struct {
 	onEnter    func(string)
 	searchTimer *time.Timer
 	searchDelay time.Duration
+	history     []string // submitted queries, oldest first
+	historyPos  int      // index into history while browsing with ↑/↓
 }
 
+// maxSearchHistory bounds the number of remembered queries.
+const maxSearchHistory = 50
+
 func NewSearchBar() *SearchBar {
 	search := &SearchBar{
 		InputField:  tview.NewInputField(),
@@ -63,6 +70,18 @@ func (s *SearchBar) build() {
 		})
 	})
 
+	s.InputField.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyUp:
+			s.recall(-1)
+			return nil
+		case tcell.KeyDown:
+			s.recall(1)
+			return nil
+		}
+		return event
+	})
+
 	s.InputField.SetDoneFunc(func(key tcell.Key) {
 		// Cancel any pending search when done (user pressed Enter/Esc)
 		if s.searchTimer != nil {
@@ -70,8 +89,9 @@ func (s *SearchBar) build() {
 			s.searchTimer = nil
 		}
 		if key == tcell.KeyEnter {
+			text := s.InputField.GetText()
+			s.remember(text)
 			if s.onEnter != nil {
-				text := s.InputField.GetText()
 				s.onEnter(text)
 			}
 		} else if key == tcell.KeyEsc {
@@ -82,6 +102,45 @@ func (s *SearchBar) build() {
 	})
 }
 
+// SetHistory replaces the remembered queries, oldest first.
+func (s *SearchBar) SetHistory(history []string) *SearchBar {
+	s.history = append([]string(nil), history...)
+	s.historyPos = len(s.history)
+	return s
+}
+
+// History returns the remembered queries, oldest first.
+func (s *SearchBar) History() []string {
+	return append([]string(nil), s.history...)
+}
+
+// remember adds a submitted query to the history, moving repeats to the end.
+func (s *SearchBar) remember(query string) {
+	query = strings.TrimSpace(query)
+	if query != "" {
+		s.history = slices.DeleteFunc(s.history, func(h string) bool { return h == query })
+		s.history = append(s.history, query)
+		if len(s.history) > maxSearchHistory {
+			s.history = s.history[len(s.history)-maxSearchHistory:]
+		}
+	}
+	s.historyPos = len(s.history)
+}
+
+// recall steps through the history with ↑ (older) and ↓ (newer).
+func (s *SearchBar) recall(step int) {
+	pos := s.historyPos + step
+	if pos < 0 || pos > len(s.history) {
+		return
+	}
+	s.historyPos = pos
+	if pos == len(s.history) {
+		s.InputField.SetText("")
+		return
+	}
+	s.InputField.SetText(s.history[pos])
+}
+
 func (s *SearchBar) OnSearch(fn func(string)) *SearchBar {
 	s.onSearch = fn
 	return s
@@ -16,7 +16,9 @@ package ui
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/Adembc/lazyssh/internal/core/domain"
 	"github.com/gdamore/tcell/v2"
@@ -25,6 +27,15 @@ import (
 
 type ServerDetails struct {
 	*tview.TextView
+	server        domain.Server
+	blocks        []domain.BlockContribution
+	inherited     []domain.InheritedSetting
+	agentWarnings []string
+	commits       []domain.ConfigCommit
+
+	showEffective bool
+	effective     *domain.EffectiveConfig
+	effectiveErr  error
 }
 
 func NewServerDetails() *ServerDetails {
@@ -57,7 +68,92 @@ func renderTagChips(tags []string) string {
 	return strings.Join(chips, " ")
 }
 
+// UpdateServer shows the given server; contributing blocks and inherited settings
+// are cleared until SetBlocks and SetInherited are called.
 func (sd *ServerDetails) UpdateServer(server domain.Server) {
+	sd.server = server
+	sd.blocks = nil
+	sd.inherited = nil
+	sd.agentWarnings = nil
+	sd.commits = nil
+	sd.effective = nil
+	sd.effectiveErr = nil
+	sd.render()
+}
+
+// SetBlocks sets the Host/Match blocks that contribute to the current server.
+func (sd *ServerDetails) SetBlocks(blocks []domain.BlockContribution) {
+	sd.blocks = blocks
+	sd.render()
+}
+
+// SetInherited sets the profile settings that apply to the current server.
+func (sd *ServerDetails) SetInherited(settings []domain.InheritedSetting) {
+	sd.inherited = settings
+	sd.render()
+}
+
+// SetAgentWarnings sets why the server's keys may not be usable; results for a
+// server that is no longer selected are ignored.
+func (sd *ServerDetails) SetAgentWarnings(alias string, warnings []string) {
+	if alias != sd.server.Alias || (len(warnings) == 0 && len(sd.agentWarnings) == 0) {
+		return
+	}
+	sd.agentWarnings = warnings
+	sd.render()
+}
+
+// SetCommits sets the git commits that changed the server's Host block; results
+// for a server that is no longer selected are ignored.
+func (sd *ServerDetails) SetCommits(alias string, commits []domain.ConfigCommit) {
+	if alias != sd.server.Alias || (len(commits) == 0 && len(sd.commits) == 0) {
+		return
+	}
+	sd.commits = commits
+	sd.render()
+}
+
+// SetHealth updates the probe history of the current server; results for a
+// server that is no longer selected are ignored.
+func (sd *ServerDetails) SetHealth(alias string, health domain.Health) {
+	if alias != sd.server.Alias || sd.showEffective {
+		return
+	}
+	sd.server.Health = health
+	sd.render()
+}
+
+// ToggleEffective switches between the server's settings and its effective
+// configuration as resolved by ssh -G. It returns true when the latter is shown.
+func (sd *ServerDetails) ToggleEffective() bool {
+	sd.showEffective = !sd.showEffective
+	sd.render()
+	return sd.showEffective
+}
+
+// ShowingEffective reports whether the effective configuration is shown.
+func (sd *ServerDetails) ShowingEffective() bool {
+	return sd.showEffective
+}
+
+// SetEffective sets the resolved configuration of alias; results for a server that
+// is no longer selected are ignored.
+func (sd *ServerDetails) SetEffective(alias string, cfg domain.EffectiveConfig, err error) {
+	if alias != sd.server.Alias {
+		return
+	}
+	sd.effective = &cfg
+	sd.effectiveErr = err
+	sd.render()
+}
+
+func (sd *ServerDetails) render() {
+	if sd.showEffective {
+		sd.renderEffective()
+		return
+	}
+	sd.TextView.SetTitle(" Details ")
+	server := sd.server
 	lastSeen := server.LastSeen.Format("2006-01-02 15:04:05")
 	if server.LastSeen.IsZero() {
 		lastSeen = "Never"
@@ -87,6 +183,8 @@ func (sd *ServerDetails) UpdateServer(server domain.Server) {
 		aliasText, hostText, userText, portText,
 		serverKey, tagsText, pinnedStr,
 		lastSeen, server.SSHCount, server.SourceFile, server.Readonly)
+	text += renderHealth(server.Health)
+	text += renderAgentWarnings(sd.agentWarnings)
 
 	// Advanced settings section (only show non-empty fields)
 	// Organized by logical grouping for better readability
@@ -212,12 +310,182 @@ func (sd *ServerDetails) UpdateServer(server domain.Server) {
 		text += advancedText
 	}
 
+	text += renderInheritedSettings(sd.inherited)
+	text += renderBlockContributions(sd.blocks)
+	text += renderCommits(sd.commits)
+
 	// Commands list
-	text += "\n[::b]Commands:[-]\n  Enter: SSH connect\n  c: Copy SSH command\n  g: Ping server\n  K: Install SSH Key\n  r: Refresh list\n  a: Add new server\n  e: Edit entry\n  t: Edit tags\n  d: Delete entry\n  p: Pin/Unpin"
+	text += "\n[::b]Commands:[-]\n  Enter: SSH connect\n  c: Copy SSH command\n  g: Ping server\n  v: SSH probe\n  K: Install SSH Key\n  i: Keys\n  A: ssh-agent\n  H: Known hosts\n  f: Browse files\n  X: Run command on servers\n  T: Tunnels\n  C: Control masters\n  r: Refresh list\n  a: Add new server\n  e: Edit entry\n  t: Edit tags\n  d: Delete entry\n  p: Pin/Unpin\n  B: Backups\n  M: Match blocks\n  P: Profiles\n  G: Effective config\n  w: Workspaces"
 
 	sd.TextView.SetText(text)
 }
 
+// renderHealth summarizes the probe history with a latency sparkline (✗ marks failures).
+func renderHealth(h domain.Health) string {
+	last, ok := h.Last()
+	if !ok {
+		return ""
+	}
+	text := "\n[::b]Health:[-]\n"
+	if last.Up {
+		text += fmt.Sprintf("  Status: [#A0FFA0]up[-] [white]%s[-] [#888888](%s)[-]\n", formatLatency(last.Latency), last.At.Format("15:04:05"))
+	} else {
+		text += fmt.Sprintf("  Status: [#FF6B6B]down[-] [#888888](%s)[-]\n", last.At.Format("15:04:05"))
+		if last.Error != "" {
+			text += "  Error: [#FF6B6B]" + tview.Escape(last.Error) + "[-]\n"
+		}
+	}
+	up, total := h.Availability()
+	text += fmt.Sprintf("  Last %d probes: [white]%d up[-]", total, up)
+	if avg := h.AverageLatency(); avg > 0 {
+		text += fmt.Sprintf(", avg [white]%s[-]", formatLatency(avg))
+	}
+	return text + "\n  " + latencySparkline(h.Samples) + "\n"
+}
+
+// latencySparkline draws one bar per sample, scaled to the slowest successful probe.
+func latencySparkline(samples []domain.HealthSample) string {
+	bars := []rune("▁▂▃▄▅▆▇█")
+	var slowest time.Duration
+	for _, s := range samples {
+		if s.Up && s.Latency > slowest {
+			slowest = s.Latency
+		}
+	}
+	var b strings.Builder
+	for _, s := range samples {
+		if !s.Up {
+			b.WriteString("[#FF6B6B]✗[-]")
+			continue
+		}
+		i := 0
+		if slowest > 0 {
+			i = int(s.Latency * time.Duration(len(bars)-1) / slowest)
+		}
+		b.WriteString("[#A0FFA0]" + string(bars[i]) + "[-]")
+	}
+	return b.String()
+}
+
+// renderAgentWarnings explains why IdentitiesOnly may make authentication fail.
+func renderAgentWarnings(warnings []string) string {
+	if len(warnings) == 0 {
+		return ""
+	}
+	text := "\n[#FFCC66::b]⚠ IdentitiesOnly yes, but none of its keys is loaded in ssh-agent:[-::-]\n"
+	for _, w := range warnings {
+		text += "  [#FFCC66]•[-] " + tview.Escape(w) + "\n"
+	}
+	return text + "  [#888888]Load the key with A (ssh-agent) or fix IdentityFile.[-]\n"
+}
+
+// renderCommits lists the git commits that changed a server's Host block, newest first.
+func renderCommits(commits []domain.ConfigCommit) string {
+	if len(commits) == 0 {
+		return ""
+	}
+	text := "\n[::b]Git History:[-]\n"
+	for _, c := range commits {
+		text += fmt.Sprintf("  [#FFCC66]%s[-] [white]%s[-] [#888888]%s, %s[-]\n",
+			c.Hash, tview.Escape(c.Subject), humanizeDuration(c.Date), tview.Escape(c.Author))
+	}
+	return text
+}
+
+// renderBlockContributions lists the Host and Match blocks that apply to a server.
+func renderBlockContributions(blocks []domain.BlockContribution) string {
+	if len(blocks) == 0 {
+		return ""
+	}
+	text := "\n[::b]Config Blocks:[-]\n"
+	for _, c := range blocks {
+		marker := "[#A0FFA0]✓[-]"
+		note := ""
+		if c.State == domain.MatchConditional {
+			marker = "[#FFCC66]?[-]"
+			note = fmt.Sprintf(" [#888888](%s)[-]", tview.Escape(c.Reason))
+		}
+		text += fmt.Sprintf("  %s [white]%s[-] [#888888]%s[-]%s\n",
+			marker, tview.Escape(c.Block.Header()), blockLocation(c.Block), note)
+	}
+	return text
+}
+
+// renderEffective shows the options ssh -G resolves for the server, compared with
+// its Host block. Options left at ssh defaults are only counted.
+func (sd *ServerDetails) renderEffective() {
+	sd.TextView.SetTitle(" Effective Config (ssh -G) ")
+	text := fmt.Sprintf("[::b]%s[-]\n\n", sd.server.Alias)
+	switch {
+	case sd.effectiveErr != nil:
+		text += fmt.Sprintf("[#FF6B6B]%s[-]\n", tview.Escape(sd.effectiveErr.Error()))
+	case sd.effective == nil:
+		text += "[#888888]Resolving…[-]\n"
+	default:
+		defaults := 0
+		for _, o := range sd.effective.Options {
+			value := tview.Escape(o.Value)
+			switch o.Source {
+			case domain.SourceDefault:
+				defaults++
+			case domain.SourceHost:
+				note := ""
+				if !strings.EqualFold(o.Literal, o.Value) {
+					note = fmt.Sprintf(" [#888888](written as %s)[-]", tview.Escape(o.Literal))
+				}
+				text += fmt.Sprintf("  [#A0FFA0]✓[-] %s: [white]%s[-]%s\n", o.Key, value, note)
+			case domain.SourceInherited:
+				text += fmt.Sprintf("  [#5FAFFF]↳[-] %s: [white]%s[-] [#888888](%s)[-]\n", o.Key, value, tview.Escape(o.From))
+			case domain.SourceOverridden:
+				text += fmt.Sprintf("  [#FFCC66]![-] %s: [white]%s[-] [#FFCC66](Host block says %s; %s wins)[-]\n",
+					o.Key, value, tview.Escape(o.Literal), tview.Escape(o.From))
+			}
+		}
+		text += fmt.Sprintf("\n  [#888888]%d more options at ssh defaults[-]\n", defaults)
+		text += "\n  [#A0FFA0]✓[-] Host block  [#5FAFFF]↳[-] inherited  [#FFCC66]![-] overridden\n"
+	}
+	text += "\n  G: Back to details"
+	sd.TextView.SetText(text)
+	sd.TextView.ScrollToBeginning()
+}
+
+// renderInheritedSettings lists profile settings grouped by profile, marking
+// whether the server inherits or overrides each value.
+func renderInheritedSettings(settings []domain.InheritedSetting) string {
+	if len(settings) == 0 {
+		return ""
+	}
+	text := "\n[::b]Inherited from Profiles:[-]\n"
+	current := ""
+	for _, s := range settings {
+		if header := s.Profile.Header(); header != current {
+			current = header
+			text += fmt.Sprintf("  [white]%s[-] [#888888]%s[-]\n", tview.Escape(header), blockLocation(s.Profile))
+		}
+		value := tview.Escape(s.Value)
+		switch s.State {
+		case domain.Inherited:
+			text += fmt.Sprintf("    [#A0FFA0]↳[-] %s: [white]%s[-]\n", s.Key, value)
+		case domain.Appended:
+			text += fmt.Sprintf("    [#A0FFA0]+[-] %s: [white]%s[-] [#888888](added)[-]\n", s.Key, value)
+		case domain.Overridden:
+			text += fmt.Sprintf("    [#888888]✗ %s: %s (overridden by %s)[-]\n", s.Key, value, tview.Escape(s.Winner))
+		case domain.Shadows:
+			text += fmt.Sprintf("    [#FFCC66]![-] %s: [white]%s[-] [#FFCC66](read before this host, wins over its own value)[-]\n", s.Key, value)
+		}
+	}
+	return text
+}
+
+// blockLocation returns "file" or "file:line" for a config block.
+func blockLocation(b domain.ConfigBlock) string {
+	name := filepath.Base(b.SourceFile)
+	if b.Line > 0 {
+		return fmt.Sprintf("%s:%d", name, b.Line)
+	}
+	return name
+}
+
 func (sd *ServerDetails) ShowEmpty() {
 	sd.TextView.SetText("No servers match the current filter.")
 }
@@ -54,7 +54,10 @@ type ServerForm struct {
 	tabs          []string
 	tabAbbrev     map[string]string // Abbreviated tab names for narrow views
 	mode          ServerFormMode
+	profile       bool // editing a pattern Host block instead of a server
 	original      *domain.Server
+	configFiles   []domain.ConfigFile // writable files a new entry can be added to
+	fileDropDown  *tview.DropDown
 	onSave        func(domain.Server, *domain.Server)
 	onCancel      func()
 	app           *tview.Application // Reference to app for showing modals
@@ -196,10 +199,14 @@ func (sf *ServerForm) build() {
 }
 
 func (sf *ServerForm) titleForMode() string {
+	kind := "Server"
+	if sf.profile {
+		kind = "Profile"
+	}
 	if sf.mode == ServerFormEdit {
-		return "Edit Server"
+		return "Edit " + kind
 	}
-	return "Add Server"
+	return "Add " + kind
 }
 
 func (sf *ServerForm) getCurrentTabIndex() int {
@@ -907,6 +914,11 @@ func (sf *ServerForm) createAlgorithmAutocomplete(suggestions []string) func(str
 // validateField validates a single field and updates the validation state
 func (sf *ServerForm) validateField(fieldName, value string) string {
 	fieldValidators := GetFieldValidators()
+	if sf.profile {
+		for name, v := range GetProfileFieldValidators() {
+			fieldValidators[name] = v
+		}
+	}
 	validator, exists := fieldValidators[fieldName]
 	if !exists {
 		// No validator for this field, it's valid
@@ -1065,11 +1077,15 @@ func (sf *ServerForm) validateAllFields() bool {
 // getDefaultValues returns default form values based on mode
 func (sf *ServerForm) getDefaultValues() ServerFormData {
 	if sf.mode == ServerFormEdit && sf.original != nil {
+		port := fmt.Sprint(sf.original.Port)
+		if sf.profile && sf.original.Port == 0 {
+			port = ""
+		}
 		return ServerFormData{
 			Alias:                sf.original.Alias,
 			Host:                 sf.original.Host,
 			User:                 sf.original.User,
-			Port:                 fmt.Sprint(sf.original.Port),
+			Port:                 port,
 			Key:                  strings.Join(sf.original.IdentityFiles, ", "),
 			Tags:                 strings.Join(sf.original.Tags, ", "),
 			ProxyJump:            sf.original.ProxyJump,
@@ -1138,6 +1154,11 @@ func (sf *ServerForm) getDefaultValues() ServerFormData {
 			LogLevel:                    sf.original.LogLevel,
 		}
 	}
+	// Profiles only carry the settings they share, so nothing is pre-filled
+	if sf.profile {
+		return ServerFormData{}
+	}
+
 	// For new servers, use empty values instead of SSH defaults
 	// SSH defaults will be applied by the SSH client if values are not specified
 	return ServerFormData{
@@ -1242,8 +1263,16 @@ func (sf *ServerForm) createBasicForm() {
 	defaultValues := sf.getDefaultValues()
 
 	// Add validated input fields
-	sf.addValidatedInputField(form, "Alias:", "Alias", defaultValues.Alias, 20, GetFieldPlaceholder("Alias"))
-	sf.addValidatedInputField(form, "Host/IP:", "Host", defaultValues.Host, 20, GetFieldPlaceholder("Host"))
+	if sf.profile {
+		sf.addValidatedInputField(form, sf.aliasLabel(), "Alias", defaultValues.Alias, 40, "*.prod.example.com !bastion")
+	} else {
+		sf.addValidatedInputField(form, sf.aliasLabel(), "Alias", defaultValues.Alias, 20, GetFieldPlaceholder("Alias"))
+	}
+	hostPlaceholder := GetFieldPlaceholder("Host")
+	if sf.profile {
+		hostPlaceholder = "optional"
+	}
+	sf.addValidatedInputField(form, "Host/IP:", "Host", defaultValues.Host, 20, hostPlaceholder)
 	sf.addValidatedInputField(form, "User:", "User", defaultValues.User, 20, GetFieldPlaceholder("User"))
 	sf.addValidatedInputField(form, "Port:", "Port", defaultValues.Port, 20, GetFieldPlaceholder("Port"))
 
@@ -1251,8 +1280,23 @@ func (sf *ServerForm) createBasicForm() {
 	keysField := sf.addValidatedInputField(form, "Keys:", "Keys", defaultValues.Key, 40, GetFieldPlaceholder("Keys"))
 	keysField.SetAutocompleteFunc(sf.createSSHKeyAutocomplete())
 
-	// Tags field
-	sf.addValidatedInputField(form, "Tags:", "Tags", defaultValues.Tags, 30, GetFieldPlaceholder("Tags"))
+	// Tags field (tags are lazyssh metadata kept per alias, so profiles have none)
+	if !sf.profile {
+		sf.addValidatedInputField(form, "Tags:", "Tags", defaultValues.Tags, 30, GetFieldPlaceholder("Tags"))
+	}
+
+	// Target file, only when adding and the config includes writable files
+	if sf.mode == ServerFormAdd && len(sf.configFiles) > 1 {
+		options := make([]string, 0, len(sf.configFiles))
+		for _, f := range sf.configFiles {
+			options = append(options, displayConfigPath(f.Path))
+		}
+		sf.fileDropDown = tview.NewDropDown().
+			SetLabel("File:").
+			SetOptions(options, nil).
+			SetCurrentOption(0)
+		form.AddFormItem(sf.fileDropDown)
+	}
 
 	// Add save and cancel buttons
 	form.AddButton("Save", sf.handleSaveButton)
@@ -1774,7 +1818,7 @@ func (sf *ServerForm) getFormData() ServerFormData {
 	}
 
 	return ServerFormData{
-		Alias: getFieldText("Alias:"),
+		Alias: getFieldText(sf.aliasLabel()),
 		Host:  getFieldText("Host/IP:"),
 		User:  getFieldText("User:"),
 		Port:  getFieldText("Port:"),
@@ -2141,6 +2185,10 @@ func (sf *ServerForm) slicesEqual(a, b reflect.Value) bool {
 
 func (sf *ServerForm) dataToServer(data ServerFormData) domain.Server {
 	port := 22
+	if sf.profile {
+		// Profiles only write a Port line when one is given
+		port = 0
+	}
 	if data.Port != "" {
 		if n, err := strconv.Atoi(data.Port); err == nil && n > 0 {
 			port = n
@@ -2246,8 +2294,15 @@ func (sf *ServerForm) dataToServer(data ServerFormData) domain.Server {
 		LogLevel:                    data.LogLevel,
 	}
 
+	if sf.fileDropDown != nil {
+		if i, _ := sf.fileDropDown.GetCurrentOption(); i >= 0 && i < len(sf.configFiles) {
+			server.SourceFile = sf.configFiles[i].Path
+		}
+	}
+
 	// Preserve metadata fields from original if in edit mode
 	if sf.mode == ServerFormEdit && sf.original != nil {
+		server.SourceFile = sf.original.SourceFile
 		server.PinnedAt = sf.original.PinnedAt
 		server.LastSeen = sf.original.LastSeen
 		server.SSHCount = sf.original.SSHCount
@@ -2258,6 +2313,34 @@ func (sf *ServerForm) dataToServer(data ServerFormData) domain.Server {
 	return server
 }
 
+// SetConfigFiles offers the writable files among files as targets for a new entry.
+// It must be called before SetVersionInfo, which builds the form.
+func (sf *ServerForm) SetConfigFiles(files []domain.ConfigFile) *ServerForm {
+	sf.configFiles = sf.configFiles[:0]
+	for _, f := range files {
+		if !f.Readonly {
+			sf.configFiles = append(sf.configFiles, f)
+		}
+	}
+	return sf
+}
+
+// aliasLabel returns the label of the alias field, which holds host patterns for profiles.
+func (sf *ServerForm) aliasLabel() string {
+	if sf.profile {
+		return "Patterns:"
+	}
+	return "Alias:"
+}
+
+// ForProfile makes the form edit a profile (pattern Host block): the alias field
+// takes host patterns, HostName is optional and there are no tags.
+// It must be called before SetVersionInfo, which builds the form.
+func (sf *ServerForm) ForProfile() *ServerForm {
+	sf.profile = true
+	return sf
+}
+
 func (sf *ServerForm) OnSave(fn func(domain.Server, *domain.Server)) *ServerForm {
 	sf.onSave = fn
 	return sf
@@ -15,6 +15,8 @@
 package ui
 
 import (
+	"sort"
+
 	"github.com/Adembc/lazyssh/internal/core/domain"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -23,13 +25,20 @@ import (
 type ServerList struct {
 	*tview.List
 	servers           []domain.Server
+	mainConfig        string
 	onSelection       func(domain.Server)
 	onSelectionChange func(domain.Server)
+
+	// marked holds the servers marked for bulk actions by alias. Marks survive
+	// filtering, so servers can be collected over several searches.
+	marked        map[string]domain.Server
+	onMarksChange func(count int)
 }
 
 func NewServerList() *ServerList {
 	list := &ServerList{
-		List: tview.NewList(),
+		List:   tview.NewList(),
+		marked: make(map[string]domain.Server),
 	}
 	list.build()
 	return list
@@ -59,7 +68,10 @@ func (sl *ServerList) UpdateServers(servers []domain.Server) {
 	sl.List.Clear()
 
 	for i := range servers {
-		primary, secondary := formatServerLine(servers[i])
+		if _, ok := sl.marked[servers[i].Alias]; ok {
+			sl.marked[servers[i].Alias] = servers[i]
+		}
+		primary, secondary := sl.formatLine(servers[i])
 		idx := i
 		sl.List.AddItem(primary, secondary, 0, func() {
 			if sl.onSelection != nil {
@@ -76,6 +88,136 @@ func (sl *ServerList) UpdateServers(servers []domain.Server) {
 	}
 }
 
+// SetHealth updates the health of the listed servers in place, keeping the selection.
+func (sl *ServerList) SetHealth(health map[string]domain.Health) {
+	for i := range sl.servers {
+		h, ok := health[sl.servers[i].Alias]
+		if !ok {
+			continue
+		}
+		sl.servers[i].Health = h
+		primary, secondary := sl.formatLine(sl.servers[i])
+		sl.List.SetItemText(i, primary, secondary)
+	}
+}
+
+// ToggleMark marks or unmarks the selected server and moves to the next one.
+func (sl *ServerList) ToggleMark() {
+	idx := sl.List.GetCurrentItem()
+	if idx < 0 || idx >= len(sl.servers) {
+		return
+	}
+	alias := sl.servers[idx].Alias
+	if _, ok := sl.marked[alias]; ok {
+		delete(sl.marked, alias)
+	} else {
+		sl.marked[alias] = sl.servers[idx]
+	}
+	sl.marksChanged()
+	if idx < len(sl.servers)-1 {
+		sl.List.SetCurrentItem(idx + 1)
+	}
+}
+
+// ToggleMarkAll marks every listed server, or unmarks them when all already are.
+func (sl *ServerList) ToggleMarkAll() {
+	all := true
+	for _, srv := range sl.servers {
+		if _, ok := sl.marked[srv.Alias]; !ok {
+			all = false
+			break
+		}
+	}
+	for _, srv := range sl.servers {
+		if all {
+			delete(sl.marked, srv.Alias)
+		} else {
+			sl.marked[srv.Alias] = srv
+		}
+	}
+	sl.marksChanged()
+}
+
+// ClearMarks unmarks every server.
+func (sl *ServerList) ClearMarks() {
+	if len(sl.marked) == 0 {
+		return
+	}
+	clear(sl.marked)
+	sl.marksChanged()
+}
+
+// MarkedServers returns the marked servers: the listed ones in list order, then
+// those hidden by the current filter by alias.
+func (sl *ServerList) MarkedServers() []domain.Server {
+	out := make([]domain.Server, 0, len(sl.marked))
+	listed := make(map[string]bool, len(sl.servers))
+	for _, srv := range sl.servers {
+		listed[srv.Alias] = true
+		if _, ok := sl.marked[srv.Alias]; ok {
+			out = append(out, srv)
+		}
+	}
+	hidden := make([]domain.Server, 0, len(sl.marked))
+	for alias, srv := range sl.marked {
+		if !listed[alias] {
+			hidden = append(hidden, srv)
+		}
+	}
+	sort.Slice(hidden, func(i, j int) bool { return hidden[i].Alias < hidden[j].Alias })
+	return append(out, hidden...)
+}
+
+// MarkedCount returns the number of marked servers.
+func (sl *ServerList) MarkedCount() int {
+	return len(sl.marked)
+}
+
+// OnMarksChange is called with the number of marked servers whenever it changes.
+func (sl *ServerList) OnMarksChange(fn func(count int)) *ServerList {
+	sl.onMarksChange = fn
+	return sl
+}
+
+func (sl *ServerList) marksChanged() {
+	for i := range sl.servers {
+		primary, secondary := sl.formatLine(sl.servers[i])
+		sl.List.SetItemText(i, primary, secondary)
+	}
+	if sl.onMarksChange != nil {
+		sl.onMarksChange(len(sl.marked))
+	}
+}
+
+// formatLine renders a server, with a mark column while any server is marked.
+func (sl *ServerList) formatLine(srv domain.Server) (string, string) {
+	primary, secondary := formatServerLine(srv, sl.mainConfig)
+	if len(sl.marked) == 0 {
+		return primary, secondary
+	}
+	if _, ok := sl.marked[srv.Alias]; ok {
+		return "[#FFCC66::b]✔[-::-] " + primary, secondary
+	}
+	return "  " + primary, secondary
+}
+
+// SelectAlias moves the selection to the server with the given alias, if listed.
+func (sl *ServerList) SelectAlias(alias string) bool {
+	for i, srv := range sl.servers {
+		if srv.Alias == alias {
+			sl.List.SetCurrentItem(i)
+			return true
+		}
+	}
+	return false
+}
+
+// SetMainConfig sets the path of the main SSH config, used to tell included servers apart.
+func (sl *ServerList) SetMainConfig(path string) *ServerList {
+	sl.mainConfig = path
+	return sl
+}
+
 func (sl *ServerList) GetSelectedServer() (domain.Server, bool) {
 	idx := sl.List.GetCurrentItem()
 	if idx >= 0 && idx < len(sl.servers) {
@@ -15,6 +15,7 @@
 package ui
 
 import (
+	"fmt"
 	"sort"
 	"strings"
 
@@ -29,8 +30,31 @@ const (
 	SortByAliasDesc
 	SortByLastSeenDesc
 	SortByLastSeenAsc
+	SortByReachabilityAsc
+	SortByReachabilityDesc
 )
 
+// ParseSortMode parses the sort setting: alias, alias-desc, last-seen (most recent
+// first), last-seen-asc, reachability (fastest first) or reachability-desc (down first).
+func ParseSortMode(s string) (SortMode, error) {
+	switch s {
+	case "", "alias":
+		return SortByAliasAsc, nil
+	case "alias-desc":
+		return SortByAliasDesc, nil
+	case "last-seen":
+		return SortByLastSeenDesc, nil
+	case "last-seen-asc":
+		return SortByLastSeenAsc, nil
+	case "reachability":
+		return SortByReachabilityAsc, nil
+	case "reachability-desc":
+		return SortByReachabilityDesc, nil
+	default:
+		return SortByAliasAsc, fmt.Errorf("unknown sort mode %q", s)
+	}
+}
+
 func (m SortMode) String() string {
 	switch m {
 	case SortByAliasAsc:
@@ -41,12 +65,16 @@ func (m SortMode) String() string {
 		return "Last SSH ↑"
 	case SortByLastSeenDesc:
 		return "Last SSH ↓"
+	case SortByReachabilityAsc:
+		return "Reachability ↑"
+	case SortByReachabilityDesc:
+		return "Reachability ↓"
 	default:
 		return "Alias ↑"
 	}
 }
 
-// ToggleField switches between Alias and LastSeen while preserving direction.
+// ToggleField cycles Alias, LastSeen and Reachability while preserving direction.
 func (m SortMode) ToggleField() SortMode {
 	switch m {
 	case SortByAliasAsc:
@@ -54,8 +82,12 @@ func (m SortMode) ToggleField() SortMode {
 	case SortByAliasDesc:
 		return SortByLastSeenDesc
 	case SortByLastSeenAsc:
-		return SortByAliasAsc
+		return SortByReachabilityAsc
 	case SortByLastSeenDesc:
+		return SortByReachabilityDesc
+	case SortByReachabilityAsc:
+		return SortByAliasAsc
+	case SortByReachabilityDesc:
 		return SortByAliasDesc
 	default:
 		return SortByAliasAsc
@@ -73,6 +105,10 @@ func (m SortMode) Reverse() SortMode {
 		return SortByLastSeenDesc
 	case SortByLastSeenDesc:
 		return SortByLastSeenAsc
+	case SortByReachabilityAsc:
+		return SortByReachabilityDesc
+	case SortByReachabilityDesc:
+		return SortByReachabilityAsc
 	default:
 		return SortByAliasAsc
 	}
@@ -114,6 +150,21 @@ func sortServersForUI(servers []domain.Server, mode SortMode) {
 			}
 			// tie-break by alias asc
 			return strings.ToLower(si.Alias) < strings.ToLower(sj.Alias)
+		case SortByReachabilityAsc, SortByReachabilityDesc:
+			if ri, rj := reachabilityRank(si.Health, mode), reachabilityRank(sj.Health, mode); ri != rj {
+				return ri < rj
+			}
+			li, lj := si.Health.Samples, sj.Health.Samples
+			if len(li) > 0 && len(lj) > 0 && si.Health.State() == domain.ReachUp {
+				a, b := li[len(li)-1].Latency, lj[len(lj)-1].Latency
+				if a != b {
+					if mode == SortByReachabilityDesc {
+						return a > b
+					}
+					return a < b
+				}
+			}
+			return strings.ToLower(si.Alias) < strings.ToLower(sj.Alias)
 		case SortByAliasAsc:
 			return strings.ToLower(si.Alias) < strings.ToLower(sj.Alias)
 		case SortByAliasDesc:
@@ -128,3 +179,22 @@ func sortServersForUI(servers []domain.Server, mode SortMode) {
 		}
 	})
 }
+
+// reachabilityRank orders servers by their last probe: fastest first when sorting
+// ascending, down first when descending. Servers never probed go to the bottom.
+func reachabilityRank(h domain.Health, mode SortMode) int {
+	switch h.State() {
+	case domain.ReachUp:
+		if mode == SortByReachabilityDesc {
+			return 1
+		}
+		return 0
+	case domain.ReachDown:
+		if mode == SortByReachabilityDesc {
+			return 0
+		}
+		return 1
+	default:
+		return 2
+	}
+}
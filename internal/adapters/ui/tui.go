@@ -15,6 +15,9 @@
 package ui
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/gdamore/tcell/v2"
 	"go.uber.org/zap"
 
@@ -48,16 +51,43 @@ type tui struct {
 
 	sortMode      SortMode
 	searchVisible bool
+
+	workspaces []Workspace
+	workspace  int // index of the active workspace
+
+	// configChanged is set when the config changed on disk while the list was
+	// not showing; it is reloaded when the list is shown again.
+	configChanged bool
+
+	// selection counts selection changes, so background lookups for the
+	// details panel can tell whether their server is still selected.
+	selection   uint64
+	lookupTimer *time.Timer
 }
 
-func NewTUI(logger *zap.SugaredLogger, ss ports.ServerService, version, commit string) App {
-	return &tui{
+// Option configures the TUI.
+type Option func(*tui)
+
+// WithSortMode sets the sort mode the server list starts with.
+func WithSortMode(mode SortMode) Option {
+	return func(t *tui) {
+		t.sortMode = mode
+	}
+}
+
+func NewTUI(logger *zap.SugaredLogger, ss ports.ServerService, version, commit string, opts ...Option) App {
+	t := &tui{
 		logger:        logger,
 		app:           tview.NewApplication(),
 		serverService: ss,
 		version:       version,
 		commit:        commit,
+		sortMode:      SortByAliasAsc,
+	}
+	for _, opt := range opts {
+		opt(t)
 	}
+	return t
 }
 
 func (t *tui) Run() error {
@@ -68,8 +98,13 @@ func (t *tui) Run() error {
 	}()
 	t.app.EnableMouse(true)
 	t.initializeTheme().buildComponents().buildLayout().bindEvents().loadInitialData()
+	t.startMonitor()
+	defer func() { t.serverService.StopMonitor() }()
+	t.startWatch()
+	defer func() { t.serverService.StopWatch() }()
 	t.app.SetRoot(t.root, true)
 	t.logger.Infow("starting TUI application", "version", t.version, "commit", t.commit)
+	defer t.stopTunnels()
 	if err := t.app.Run(); err != nil {
 		t.logger.Errorw("application run error", "error", err)
 		return err
@@ -77,6 +112,16 @@ func (t *tui) Run() error {
 	return nil
 }
 
+// stopTunnels tears down the tunnels of every workspace; they must not outlive lazyssh.
+func (t *tui) stopTunnels() {
+	t.serverService.StopAllTunnels()
+	for _, w := range t.workspaces {
+		if w.Service != t.serverService {
+			w.Service.StopAllTunnels()
+		}
+	}
+}
+
 func (t *tui) initializeTheme() *tui {
 	tview.Styles.PrimitiveBackgroundColor = tcell.Color232
 	tview.Styles.ContrastBackgroundColor = tcell.Color235
@@ -97,12 +142,13 @@ func (t *tui) buildComponents() *tui {
 		OnEnter(t.handleSearchEnter)
 	t.hintBar = NewHintBar()
 	t.serverList = NewServerList().
-		OnSelectionChange(t.handleServerSelectionChange)
+		OnSelectionChange(t.handleServerSelectionChange).
+		OnMarksChange(func(int) { t.updateListTitle() })
 	t.details = NewServerDetails()
 	t.statusBar = NewStatusBar()
-
-	// default sort mode
-	t.sortMode = SortByAliasAsc
+	if len(t.workspaces) > 1 {
+		t.header.SetWorkspace(t.workspaces[t.workspace].Name)
+	}
 
 	return t
 }
@@ -132,6 +178,9 @@ func (t *tui) bindEvents() *tui {
 }
 
 func (t *tui) loadInitialData() *tui {
+	if files, err := t.serverService.ListConfigFiles(); err == nil && len(files) > 0 {
+		t.serverList.SetMainConfig(files[0].Path)
+	}
 	servers, _ := t.serverService.ListServers("")
 	sortServersForUI(servers, t.sortMode)
 	t.updateListTitle()
@@ -142,6 +191,10 @@ func (t *tui) loadInitialData() *tui {
 
 func (t *tui) updateListTitle() {
 	if t.serverList != nil {
-		t.serverList.SetTitle(" Servers — Sort: " + t.sortMode.String() + " ")
+		title := " Servers — Sort: " + t.sortMode.String() + " "
+		if n := t.serverList.MarkedCount(); n > 0 {
+			title += fmt.Sprintf("— %d marked ", n)
+		}
+		t.serverList.SetTitle(title)
 	}
 }
@@ -0,0 +1,358 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/core/ports"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// tunnelRefreshInterval is how often the tunnel table is redrawn while the view is open.
+const tunnelRefreshInterval = time.Second
+
+// TunnelsView lists the background `ssh -N` tunnels lazyssh supervises. Tunnels
+// keep running when the view is closed and are torn down when lazyssh exits.
+type TunnelsView struct {
+	*tview.Flex
+	app     *tview.Application
+	service ports.ServerService
+	alias   string
+
+	table   *tview.Table
+	details *tview.TextView
+	status  *tview.TextView
+
+	tunnels []domain.Tunnel
+	stop    chan struct{}
+	onAdHoc func(alias string)
+	onClose func()
+}
+
+func NewTunnelsView(header *AppHeader, app *tview.Application, ss ports.ServerService, alias string) *TunnelsView {
+	v := &TunnelsView{
+		Flex:    tview.NewFlex().SetDirection(tview.FlexRow),
+		app:     app,
+		service: ss,
+		alias:   alias,
+		table:   tview.NewTable(),
+		details: tview.NewTextView(),
+		status:  tview.NewTextView(),
+	}
+	v.build(header)
+	return v
+}
+
+func (v *TunnelsView) build(header *AppHeader) {
+	v.table.SetBorder(true).
+		SetTitle(" Tunnels ").
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+	v.table.SetSelectable(true, false).
+		SetFixed(1, 0).
+		SetSelectedStyle(tcell.StyleDefault.Background(tcell.Color24).Foreground(tcell.Color255))
+	v.table.SetSelectionChangedFunc(func(row, _ int) {
+		v.showTunnel(row)
+	})
+
+	v.details.SetDynamicColors(true).
+		SetWrap(true).
+		SetBorder(true).
+		SetTitle(" Details ").
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+
+	v.status.SetDynamicColors(true)
+	v.status.SetBackgroundColor(tcell.Color235)
+	v.status.SetTextAlign(tview.AlignCenter)
+	v.setHint()
+
+	v.Flex.AddItem(header, 2, 0, false).
+		AddItem(v.table, 0, 1, true).
+		AddItem(v.details, 9, 0, false).
+		AddItem(v.status, 1, 0, false)
+
+	v.Flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			v.close()
+			return nil
+		}
+		switch event.Rune() {
+		case 'q':
+			v.close()
+			return nil
+		case 'n':
+			v.start()
+			return nil
+		case 'a':
+			if v.onAdHoc != nil {
+				v.onAdHoc(v.alias)
+			}
+			return nil
+		case 'x':
+			v.withSelected(v.service.StopTunnel)
+			return nil
+		case 'r':
+			v.withSelected(v.service.RestartTunnel)
+			return nil
+		}
+		return event
+	})
+}
+
+// Start draws the tunnels and keeps the table current while the view is open.
+func (v *TunnelsView) Start() *TunnelsView {
+	v.refresh()
+	v.stop = make(chan struct{})
+	go func(stop chan struct{}) {
+		ticker := time.NewTicker(tunnelRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				v.app.QueueUpdateDraw(v.refresh)
+			}
+		}
+	}(v.stop)
+	return v
+}
+
+// OnAdHoc is called with the selected server's alias when the user asks for a
+// tunnel with custom forwards.
+func (v *TunnelsView) OnAdHoc(fn func(alias string)) *TunnelsView {
+	v.onAdHoc = fn
+	return v
+}
+
+func (v *TunnelsView) OnClose(fn func()) *TunnelsView {
+	v.onClose = fn
+	return v
+}
+
+// ShowError displays err in the hint bar for a few seconds.
+func (v *TunnelsView) ShowError(err error) *TunnelsView {
+	v.status.SetText("[#FF6B6B]" + tview.Escape(err.Error()) + "[-]")
+	time.AfterFunc(3*time.Second, func() {
+		v.app.QueueUpdateDraw(v.setHint)
+	})
+	return v
+}
+
+func (v *TunnelsView) close() {
+	if v.stop != nil {
+		close(v.stop)
+		v.stop = nil
+	}
+	if v.onClose != nil {
+		v.onClose()
+	}
+}
+
+// start opens a tunnel with the forwards configured for the server.
+func (v *TunnelsView) start() {
+	if v.alias == "" {
+		return
+	}
+	if _, err := v.service.StartTunnel(domain.TunnelSpec{Alias: v.alias}); err != nil {
+		v.ShowError(err)
+		return
+	}
+	v.refresh()
+	v.table.Select(v.table.GetRowCount()-1, 0)
+}
+
+// withSelected runs fn on the selected tunnel in the background; stopping waits
+// for the ssh process to exit.
+func (v *TunnelsView) withSelected(fn func(id int) error) {
+	row, _ := v.table.GetSelection()
+	if row < 1 || row > len(v.tunnels) {
+		return
+	}
+	id := v.tunnels[row-1].ID
+	go func() {
+		err := fn(id)
+		v.app.QueueUpdateDraw(func() {
+			if err != nil {
+				v.ShowError(err)
+			}
+			v.refresh()
+		})
+	}()
+}
+
+func (v *TunnelsView) refresh() {
+	v.tunnels = v.service.ListTunnels()
+
+	row, _ := v.table.GetSelection()
+	v.table.Clear()
+	for i, h := range []string{"#", "Server", "Forwards", "State", "PID", "Uptime", "Restarts"} {
+		v.table.SetCell(0, i, tview.NewTableCell("[::b]"+h).SetSelectable(false).SetTextColor(tcell.Color250))
+	}
+	if len(v.tunnels) == 0 {
+		v.table.SetCell(1, 0, tview.NewTableCell("").SetSelectable(false))
+		v.details.SetText(fmt.Sprintf("No tunnels running.\n\nPress [white]n[-] to open the forwards configured for [white]%s[-] "+
+			"(LocalForward, RemoteForward, DynamicForward), or [white]a[-] to enter forwards by hand.", tview.Escape(v.alias)))
+		return
+	}
+	for i, t := range v.tunnels {
+		r := i + 1
+		pid := ""
+		if t.PID != 0 {
+			pid = strconv.Itoa(t.PID)
+		}
+		v.table.SetCell(r, 0, tview.NewTableCell(strconv.Itoa(t.ID)))
+		v.table.SetCell(r, 1, tview.NewTableCell(tview.Escape(t.Spec.Alias)))
+		v.table.SetCell(r, 2, tview.NewTableCell(tview.Escape(strings.Join(t.Forwards, ", "))).SetExpansion(1).SetMaxWidth(60))
+		v.table.SetCell(r, 3, tview.NewTableCell(tunnelStateLabel(t.State)))
+		v.table.SetCell(r, 4, tview.NewTableCell(pid).SetAlign(tview.AlignRight))
+		v.table.SetCell(r, 5, tview.NewTableCell(tunnelUptime(t)).SetAlign(tview.AlignRight))
+		v.table.SetCell(r, 6, tview.NewTableCell(strconv.Itoa(t.Restarts)).SetAlign(tview.AlignRight))
+	}
+	row = min(max(row, 1), len(v.tunnels))
+	v.table.Select(row, 0)
+	v.showTunnel(row)
+}
+
+func (v *TunnelsView) showTunnel(row int) {
+	if row < 1 || row > len(v.tunnels) {
+		return
+	}
+	t := v.tunnels[row-1]
+	kind := "configured forwards"
+	if t.Spec.AdHoc() {
+		kind = "ad-hoc"
+	}
+	text := fmt.Sprintf("[::b]%s[-] [#888888](%s)[-]  %s\n\n", tview.Escape(t.Spec.Alias), kind, tunnelStateLabel(t.State))
+	for _, f := range t.Forwards {
+		text += "  " + tview.Escape(f) + "\n"
+	}
+	if t.LastError != "" {
+		text += "\n  Last error: [#FF6B6B]" + tview.Escape(t.LastError) + "[-]\n"
+	}
+	v.details.SetText(text)
+}
+
+func (v *TunnelsView) setHint() {
+	v.status.SetText("[white]n[-] Open configured forwards  • [white]a[-] Ad-hoc  • [white]x[-] Stop  • [white]r[-] Restart  • [white]Esc[-] Back (tunnels keep running)")
+}
+
+func tunnelStateLabel(s domain.TunnelState) string {
+	switch s {
+	case domain.TunnelRunning:
+		return "[#A0FFA0]● running[-]"
+	case domain.TunnelStarting, domain.TunnelRestarting:
+		return "[#FFCC66]◌ " + s.String() + "[-]"
+	case domain.TunnelFailed:
+		return "[#FF6B6B]✗ failed[-]"
+	default:
+		return "[#888888]" + s.String() + "[-]"
+	}
+}
+
+func tunnelUptime(t domain.Tunnel) string {
+	if t.State != domain.TunnelRunning || t.StartedAt.IsZero() {
+		return ""
+	}
+	return time.Since(t.StartedAt).Truncate(time.Second).String()
+}
+
+// TunnelRequest holds the forwards entered in the ad-hoc tunnel form, in the
+// syntax of ssh -L/-R/-D.
+type TunnelRequest struct {
+	Alias   string
+	Local   []string
+	Remote  []string
+	Dynamic []string
+}
+
+// NewTunnelForm asks for a server and the forwards of an ad-hoc tunnel. Each
+// field takes a comma-separated list.
+func NewTunnelForm(alias string, onSubmit func(TunnelRequest), onCancel func()) *tview.Form {
+	form := tview.NewForm()
+	form.SetBorder(true).
+		SetTitle(" New tunnel ").
+		SetTitleAlign(tview.AlignCenter)
+
+	form.AddInputField("Server:", alias, 40, nil, nil)
+	form.AddInputField("Local forwards:", "", 50, nil, nil)
+	form.AddInputField("Remote forwards:", "", 50, nil, nil)
+	form.AddInputField("Dynamic forwards:", "", 30, nil, nil)
+	for i, placeholder := range []string{"8080:localhost:80, 127.0.0.1:5432:db:5432", "9000:localhost:3000", "1080"} {
+		if field, ok := form.GetFormItem(i + 1).(*tview.InputField); ok {
+			field.SetPlaceholder(placeholder)
+		}
+	}
+
+	text := func(i int) string {
+		return strings.TrimSpace(form.GetFormItem(i).(*tview.InputField).GetText())
+	}
+	fail := func(msg string) {
+		form.SetTitle(" New tunnel — " + msg + " ")
+	}
+	form.AddButton("Start", func() {
+		req := TunnelRequest{
+			Alias:   text(0),
+			Local:   splitCommaList(text(1)),
+			Remote:  splitCommaList(text(2)),
+			Dynamic: splitCommaList(text(3)),
+		}
+		if req.Alias == "" {
+			fail("enter a server alias")
+			return
+		}
+		if len(req.Local)+len(req.Remote)+len(req.Dynamic) == 0 {
+			fail("enter at least one forward")
+			return
+		}
+		if err := validatePortForward(text(1)); err != nil {
+			fail("local: " + err.Error())
+			return
+		}
+		for _, f := range req.Remote {
+			if validatePortForward(f) != nil && validateDynamicForward(f) != nil {
+				fail("remote: expected [bind_address:]port[:host:hostport]")
+				return
+			}
+		}
+		if err := validateDynamicForward(text(3)); err != nil {
+			fail("dynamic: " + err.Error())
+			return
+		}
+		onSubmit(req)
+	})
+	form.AddButton("Cancel", onCancel)
+	form.SetCancelFunc(onCancel)
+	return form
+}
+
+func splitCommaList(s string) []string {
+	var out []string
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			out = append(out, f)
+		}
+	}
+	return out
+}
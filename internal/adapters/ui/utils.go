@@ -78,23 +78,52 @@ func pinnedIcon(pinnedAt time.Time) string {
 	return "📌" // pinned
 }
 
-func originIcon(s domain.Server) string {
+// originIcon shows where a server is defined: 🏠 main config, 🔗 an included file,
+// 🔒 a file lazyssh cannot write.
+func originIcon(s domain.Server, mainConfig string) string {
 	if s.Readonly {
+		return "🔒"
+	}
+	if mainConfig != "" && s.SourceFile != "" && s.SourceFile != mainConfig {
 		return "🔗"
 	}
 	return "🏠"
 }
 
-func formatServerLine(s domain.Server) (primary, secondary string) {
+func formatServerLine(s domain.Server, mainConfig string) (primary, secondary string) {
 	icon := cellPad(pinnedIcon(s.PinnedAt), 2)
 	// Use a consistent color for alias; the icon reflects pinning
-	// Append an origin icon on the right: 🏠 for main file, 🔗 for included (read-only)
-	primary = fmt.Sprintf("%s [white::b]%-12s[-] [#AAAAAA]%-18s[-] [#888888]Last SSH: %s[-]  %s  %s",
-		icon, s.Alias, s.Host, humanizeDuration(s.LastSeen), renderTagBadgesForList(s.Tags), originIcon(s))
+	// Append an origin icon on the right: 🏠 for main file, 🔗 for included, 🔒 for read-only
+	primary = fmt.Sprintf("%s %s [white::b]%-12s[-] [#AAAAAA]%-18s[-] [#888888]Last SSH: %s[-]  %s  %s",
+		icon, healthBadge(s.Health), s.Alias, s.Host, humanizeDuration(s.LastSeen), renderTagBadgesForList(s.Tags), originIcon(s, mainConfig))
 	secondary = ""
 	return
 }
 
+// healthBadge renders the last probe as a colored dot and the latency, 7 cells wide.
+func healthBadge(h domain.Health) string {
+	last, ok := h.Last()
+	switch {
+	case !ok:
+		return "[#555555]○[-]      "
+	case last.Up:
+		return fmt.Sprintf("[#A0FFA0]●[-] [#888888]%5s[-]", formatLatency(last.Latency))
+	default:
+		return "[#FF6B6B]● down[-] "
+	}
+}
+
+// formatLatency fits a latency in at most 5 characters: "<1ms", "12ms", "1.5s".
+func formatLatency(d time.Duration) string {
+	if d < time.Millisecond {
+		return "<1ms"
+	}
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
+
 func humanizeDuration(t time.Time) string {
 	if t.IsZero() {
 		return "never"
@@ -468,6 +497,18 @@ func addTTYAndLoggingOptions(parts *[]string, s domain.Server) {
 	}
 }
 
+// displayConfigPath shortens a path under the home directory to ~/...
+func displayConfigPath(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	if rel, err := filepath.Rel(home, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.Join("~", rel)
+	}
+	return path
+}
+
 // quoteIfNeeded returns the value quoted if it contains spaces.
 func quoteIfNeeded(val string) string {
 	if strings.ContainsAny(val, " \t") {
@@ -227,6 +227,23 @@ func GetFieldValidators() map[string]fieldValidator {
 	return validators
 }
 
+// GetProfileFieldValidators returns the rules that replace GetFieldValidators entries
+// when editing a profile: the alias holds host patterns and HostName is optional
+// (it may use tokens such as %h).
+func GetProfileFieldValidators() map[string]fieldValidator {
+	return map[string]fieldValidator{
+		"Alias": {
+			Required: true,
+			Pattern:  regexp.MustCompile(`^!?[a-zA-Z0-9._*?%-]+( +!?[a-zA-Z0-9._*?%-]+)*$`),
+			Message:  "Patterns are required: space-separated host names with * and ? wildcards, prefix ! to negate",
+		},
+		"Host": {
+			Pattern: regexp.MustCompile(`^[^\s"#]+$`),
+			Message: "HostName must not contain spaces, quotes or '#'",
+		},
+	}
+}
+
 // validatePort validates port number
 func validatePort(value string) error {
 	if value == "" {
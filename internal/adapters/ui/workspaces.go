@@ -0,0 +1,207 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ui
+
+import (
+	"fmt"
+
+	"github.com/Adembc/lazyssh/internal/core/ports"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Workspace is a named SSH config tree served by its own ServerService (and thus
+// its own repository and metadata). The TUI keeps the sort mode and search
+// history of each workspace while switching between them.
+type Workspace struct {
+	Name         string
+	ConfigPath   string
+	MetadataPath string
+	Service      ports.ServerService
+	SortMode     SortMode
+
+	searchHistory []string
+}
+
+// WithWorkspaces registers the workspaces the user can switch between and the
+// index of the one to start in. Its service replaces the one given to NewTUI.
+func WithWorkspaces(workspaces []Workspace, current int) Option {
+	return func(t *tui) {
+		if current < 0 || current >= len(workspaces) {
+			return
+		}
+		t.workspaces = workspaces
+		t.workspace = current
+		t.serverService = workspaces[current].Service
+		t.sortMode = workspaces[current].SortMode
+	}
+}
+
+func (t *tui) handleWorkspaces() {
+	if len(t.workspaces) < 2 {
+		t.showStatusTempColor("No other workspaces; add them under workspaces: in ~/.lazyssh/config.yaml", "#FFCC66")
+		return
+	}
+	view := NewWorkspacesView(t.header).
+		SetWorkspaces(t.workspaces, t.workspace).
+		OnSelect(func(i int) {
+			t.switchWorkspace(i)
+			t.returnToMain()
+		}).
+		OnClose(t.returnToMain)
+	t.app.SetRoot(view, true)
+}
+
+// switchWorkspace saves the view state of the current workspace and loads the other one.
+func (t *tui) switchWorkspace(i int) {
+	if i == t.workspace || i < 0 || i >= len(t.workspaces) {
+		return
+	}
+	cur := &t.workspaces[t.workspace]
+	cur.SortMode = t.sortMode
+	cur.searchHistory = t.searchBar.History()
+
+	cur.Service.StopMonitor()
+	cur.Service.StopWatch()
+	t.configChanged = false
+
+	next := t.workspaces[i]
+	t.workspace = i
+	t.serverService = next.Service
+	t.sortMode = next.SortMode
+	t.searchBar.SetHistory(next.searchHistory)
+	t.searchBar.SetText("")
+	if t.searchVisible {
+		t.hideSearchBar()
+	}
+	if t.details.ShowingEffective() {
+		t.details.ToggleEffective()
+	}
+	t.header.SetWorkspace(next.Name)
+	// Marks are aliases of the previous workspace; bulk actions must not
+	// run them through the new workspace's service.
+	t.serverList.ClearMarks()
+	t.loadInitialData()
+	t.startMonitor()
+	t.startWatch()
+	t.showStatusTemp("Workspace: " + next.Name)
+}
+
+// WorkspacesView lists the configured workspaces and switches to the chosen one.
+type WorkspacesView struct {
+	*tview.Flex
+	list       *tview.List
+	details    *tview.TextView
+	workspaces []Workspace
+	onSelect   func(int)
+	onClose    func()
+}
+
+func NewWorkspacesView(header *AppHeader) *WorkspacesView {
+	v := &WorkspacesView{
+		Flex:    tview.NewFlex().SetDirection(tview.FlexRow),
+		list:    tview.NewList(),
+		details: tview.NewTextView(),
+	}
+	v.build(header)
+	return v
+}
+
+func (v *WorkspacesView) build(header *AppHeader) {
+	v.list.ShowSecondaryText(false)
+	v.list.SetBorder(true).
+		SetTitle(" Workspaces ").
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+	v.list.
+		SetSelectedBackgroundColor(tcell.Color24).
+		SetSelectedTextColor(tcell.Color255).
+		SetHighlightFullLine(true)
+	v.list.SetChangedFunc(func(index int, _ string, _ string, _ rune) {
+		v.showWorkspace(index)
+	})
+	v.list.SetSelectedFunc(func(index int, _ string, _ string, _ rune) {
+		if v.onSelect != nil {
+			v.onSelect(index)
+		}
+	})
+
+	v.details.SetDynamicColors(true).
+		SetWrap(true).
+		SetBorder(true).
+		SetTitle(" Details ").
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.Color238).
+		SetTitleColor(tcell.Color250)
+
+	hint := tview.NewTextView().SetDynamicColors(true)
+	hint.SetBackgroundColor(tcell.Color235)
+	hint.SetTextAlign(tview.AlignCenter)
+	hint.SetText("[white]↑↓[-] Navigate  • [white]Enter[-] Switch  • [white]Esc[-] Back")
+
+	content := tview.NewFlex().SetDirection(tview.FlexColumn).
+		AddItem(v.list, 0, 2, true).
+		AddItem(v.details, 0, 3, false)
+
+	v.Flex.AddItem(header, 2, 0, false).
+		AddItem(content, 0, 1, true).
+		AddItem(hint, 1, 0, false)
+
+	v.Flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || event.Rune() == 'q' || event.Rune() == 'w' {
+			if v.onClose != nil {
+				v.onClose()
+			}
+			return nil
+		}
+		return event
+	})
+}
+
+// SetWorkspaces shows the workspaces with the current one selected.
+func (v *WorkspacesView) SetWorkspaces(workspaces []Workspace, current int) *WorkspacesView {
+	v.workspaces = workspaces
+	v.list.Clear()
+	for i, w := range workspaces {
+		text := fmt.Sprintf("[white::b]%s[-::-]", tview.Escape(w.Name))
+		if i == current {
+			text += " [#888888](current)[-]"
+		}
+		v.list.AddItem(text, "", 0, nil)
+	}
+	v.list.SetCurrentItem(current)
+	v.showWorkspace(current)
+	return v
+}
+
+func (v *WorkspacesView) OnSelect(fn func(int)) *WorkspacesView {
+	v.onSelect = fn
+	return v
+}
+
+func (v *WorkspacesView) OnClose(fn func()) *WorkspacesView {
+	v.onClose = fn
+	return v
+}
+
+func (v *WorkspacesView) showWorkspace(index int) {
+	if index < 0 || index >= len(v.workspaces) {
+		return
+	}
+	w := v.workspaces[index]
+	v.details.SetText(fmt.Sprintf("[::b]%s[-]\n\n  SSH config: [white]%s[-]\n  Metadata: [white]%s[-]\n  Sort: [white]%s[-]\n",
+		tview.Escape(w.Name), displayConfigPath(w.ConfigPath), displayConfigPath(w.MetadataPath), w.SortMode))
+}
@@ -0,0 +1,34 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"path/filepath"
+	"time"
+)
+
+// Backup is a copy of a config file that lazyssh took before changing it.
+type Backup struct {
+	Path       string // the backup file
+	ConfigFile string // the config file it is a copy of
+	Original   bool   // the one-time copy taken before lazyssh first changed the file
+	CreatedAt  time.Time
+	Size       int64
+}
+
+// Name returns the file name of the backup.
+func (b Backup) Name() string {
+	return filepath.Base(b.Path)
+}
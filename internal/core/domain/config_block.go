@@ -0,0 +1,148 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import "strings"
+
+// BlockKind distinguishes Host sections from Match sections of an SSH config.
+type BlockKind string
+
+const (
+	BlockHost  BlockKind = "Host"
+	BlockMatch BlockKind = "Match"
+)
+
+// ConfigSetting is a single keyword/value line inside a config block.
+type ConfigSetting struct {
+	Key   string
+	Value string
+}
+
+// MatchCriterion is one condition of a Match block, e.g. "host *.prod" or "!localuser root".
+// Keywords without an argument (all, canonical, final) have an empty Argument.
+type MatchCriterion struct {
+	Keyword  string
+	Negated  bool
+	Argument string
+}
+
+func (c MatchCriterion) String() string {
+	s := c.Keyword
+	if c.Negated {
+		s = "!" + s
+	}
+	if c.Argument != "" {
+		arg := c.Argument
+		if strings.ContainsAny(arg, " \t") {
+			arg = `"` + arg + `"`
+		}
+		s += " " + arg
+	}
+	return s
+}
+
+// ConfigBlock is a Host or Match section of an SSH config file, in file order.
+type ConfigBlock struct {
+	Kind       BlockKind
+	Patterns   []string         // Host patterns (Host blocks only)
+	Criteria   []MatchCriterion // Match criteria (Match blocks only)
+	Settings   []ConfigSetting
+	SourceFile string
+	Line       int // line of the Match keyword; 0 when unknown
+}
+
+// Header returns the block's opening line as it would appear in the config.
+func (b ConfigBlock) Header() string {
+	if b.Kind == BlockMatch {
+		parts := make([]string, 0, len(b.Criteria))
+		for _, c := range b.Criteria {
+			parts = append(parts, c.String())
+		}
+		return "Match " + strings.Join(parts, " ")
+	}
+	return "Host " + strings.Join(b.Patterns, " ")
+}
+
+// MatchState tells whether a block applies to a server.
+type MatchState int
+
+const (
+	MatchNo MatchState = iota
+	MatchYes
+	// MatchConditional means the block depends on something only known at
+	// connect time (exec, canonical, final, ...).
+	MatchConditional
+)
+
+// BlockContribution describes a block that applies (or may apply) to a server.
+type BlockContribution struct {
+	Block  ConfigBlock
+	State  MatchState
+	Reason string
+}
+
+// InheritState tells how a profile setting relates to the server it applies to.
+// ssh uses the first value it finds for most keywords, so block order matters.
+type InheritState int
+
+const (
+	// Inherited means the profile supplies the value ssh uses.
+	Inherited InheritState = iota
+	// Overridden means the server, or a block read earlier, sets the keyword first.
+	Overridden
+	// Shadows means the profile is read before the server's own block, so its
+	// value wins over the one the server sets.
+	Shadows
+	// Appended is used for cumulative keywords (IdentityFile, LocalForward, ...)
+	// where every value is added.
+	Appended
+)
+
+// InheritedSetting is a setting of a profile (pattern Host block) that applies to a server.
+type InheritedSetting struct {
+	Profile ConfigBlock
+	Key     string
+	Value   string
+	State   InheritState
+	Winner  string // value ssh uses instead, for Overridden settings
+}
+
+// ConfigFile is the main SSH config or one of the files it includes.
+type ConfigFile struct {
+	Path     string
+	Main     bool
+	Readonly bool
+	Hidden   bool
+}
+
+// FileAccess is what lazyssh may do with a config file.
+type FileAccess int
+
+const (
+	FileWritable FileAccess = iota
+	FileReadonly
+	FileHidden // read-only, and its servers and profiles are not listed
+)
+
+// FilePolicy assigns an access level to config files by glob. Patterns are matched
+// against the absolute path and the path relative to the main config's directory.
+// When several lists match, hidden wins over read-only, and read-only over writable.
+type FilePolicy struct {
+	Writable []string
+	Readonly []string
+	Hidden   []string
+	// IncludesReadonly makes included files that match no pattern read-only.
+	IncludesReadonly bool
+}
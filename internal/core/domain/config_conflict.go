@@ -0,0 +1,39 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import "fmt"
+
+// ConfigConflictError reports that a config file changed on disk since lazyssh
+// last read it, so writing it would drop someone else's change.
+type ConfigConflictError struct {
+	Path string
+}
+
+func (e *ConfigConflictError) Error() string {
+	return fmt.Sprintf("%s was changed outside lazyssh since it was loaded", e.Path)
+}
+
+// ConflictResolution says how to write a config file that changed on disk.
+type ConflictResolution int
+
+const (
+	// ConflictMerge applies the change to the file as it is now, keeping the
+	// changes made outside lazyssh.
+	ConflictMerge ConflictResolution = iota
+	// ConflictOverwrite discards the changes made outside lazyssh; they are
+	// kept in a backup.
+	ConflictOverwrite
+)
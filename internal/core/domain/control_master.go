@@ -0,0 +1,56 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+// ControlMasterState tells whether a multiplexing master answers on its socket.
+type ControlMasterState int
+
+const (
+	// ControlNotRunning means there is no socket at the control path.
+	ControlNotRunning ControlMasterState = iota
+	// ControlAlive means the master answered `ssh -O check`.
+	ControlAlive
+	// ControlStale means a socket exists but no master accepts connections on it.
+	ControlStale
+	// ControlHung means the master accepted the check but did not answer in time.
+	ControlHung
+)
+
+func (s ControlMasterState) String() string {
+	switch s {
+	case ControlAlive:
+		return "alive"
+	case ControlStale:
+		return "stale"
+	case ControlHung:
+		return "hung"
+	default:
+		return "not running"
+	}
+}
+
+// ControlMaster is the multiplexing master of a server, as resolved by `ssh -G`.
+type ControlMaster struct {
+	Alias string
+	// Path is the expanded ControlPath.
+	Path    string
+	Mode    string // ControlMaster: yes, no, auto, ask, autoask
+	Persist string // ControlPersist as reported by ssh -G, e.g. "600" or "no"
+	State   ControlMasterState
+	PID     int
+	// Sessions counts the clients multiplexed over the master, or -1 when unknown.
+	Sessions int
+	Error    string
+}
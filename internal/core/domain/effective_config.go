@@ -0,0 +1,61 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+// OptionSource tells where the value of a resolved option comes from.
+type OptionSource int
+
+const (
+	// SourceHost means the value is written in the server's own Host block.
+	SourceHost OptionSource = iota
+	// SourceOverridden means the Host block sets the option but ssh resolves another
+	// value, e.g. because a block read earlier sets it first.
+	SourceOverridden
+	// SourceInherited means the value comes from another block: a wildcard Host,
+	// a Match block or an included file.
+	SourceInherited
+	// SourceDefault means no config sets the option and ssh uses its built-in value.
+	SourceDefault
+)
+
+func (s OptionSource) String() string {
+	switch s {
+	case SourceHost:
+		return "host"
+	case SourceOverridden:
+		return "overridden"
+	case SourceInherited:
+		return "inherited"
+	default:
+		return "default"
+	}
+}
+
+// EffectiveOption is one option as resolved by `ssh -G`, compared with the Host block.
+// Options that may appear several times (IdentityFile, LocalForward, ...) have their
+// values joined with ", ".
+type EffectiveOption struct {
+	Key     string // lower-case keyword as printed by ssh -G
+	Value   string
+	Literal string // value written in the Host block, if any
+	Source  OptionSource
+	From    string // block that supplies an inherited value, e.g. "Host *.prod (config)"
+}
+
+// EffectiveConfig is the configuration ssh would use to connect to an alias.
+type EffectiveConfig struct {
+	Alias   string
+	Options []EffectiveOption
+}
@@ -0,0 +1,102 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import "time"
+
+// ExecRequest is a command to run on several servers in parallel.
+type ExecRequest struct {
+	Command string
+	// Aliases and Tags select the servers: a server runs the command when it is
+	// listed or has one of the tags.
+	Aliases []string
+	Tags    []string
+	// Concurrency bounds the servers running the command at the same time.
+	Concurrency int
+	// Timeout bounds each server's run; zero means no limit.
+	Timeout time.Duration
+}
+
+// ExecState is the progress of the command on one server.
+type ExecState int
+
+const (
+	ExecPending ExecState = iota
+	ExecRunning
+	// ExecSucceeded: the command exited with status 0.
+	ExecSucceeded
+	// ExecFailed: the command exited with a non-zero status.
+	ExecFailed
+	// ExecTimedOut: the command was killed after the timeout.
+	ExecTimedOut
+	// ExecError: ssh could not run the command (connection, auth, ...).
+	ExecError
+	// ExecCanceled: the run was canceled before the command finished.
+	ExecCanceled
+)
+
+func (s ExecState) String() string {
+	switch s {
+	case ExecRunning:
+		return "running"
+	case ExecSucceeded:
+		return "ok"
+	case ExecFailed:
+		return "failed"
+	case ExecTimedOut:
+		return "timed out"
+	case ExecError:
+		return "error"
+	case ExecCanceled:
+		return "canceled"
+	default:
+		return "pending"
+	}
+}
+
+// Done reports whether the state is final.
+func (s ExecState) Done() bool {
+	return s >= ExecSucceeded
+}
+
+// ExecResult is the outcome of the command on one server.
+type ExecResult struct {
+	Alias    string
+	State    ExecState
+	ExitCode int // -1 when the command did not exit
+	Error    string
+	Duration time.Duration
+}
+
+// ExecEventKind tells what an ExecEvent reports.
+type ExecEventKind int
+
+const (
+	// ExecQueued is sent for every selected server, in order, before any runs.
+	ExecQueued ExecEventKind = iota
+	ExecStarted
+	ExecOutput
+	ExecFinished
+)
+
+// ExecEvent reports progress of a parallel run: a server queued or starting, a
+// line of its output, or its result.
+type ExecEvent struct {
+	Kind   ExecEventKind
+	Alias  string
+	Line   string
+	Stderr bool
+	Result ExecResult // set for ExecFinished
+}
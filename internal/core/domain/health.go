@@ -0,0 +1,110 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import "time"
+
+// Reachability summarizes the latest probe of a server.
+type Reachability int
+
+const (
+	ReachUnknown Reachability = iota
+	ReachUp
+	ReachDown
+)
+
+func (r Reachability) String() string {
+	switch r {
+	case ReachUp:
+		return "up"
+	case ReachDown:
+		return "down"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseReachability parses "up", "down" or "unknown".
+func ParseReachability(s string) (Reachability, bool) {
+	switch s {
+	case "up":
+		return ReachUp, true
+	case "down":
+		return ReachDown, true
+	case "unknown":
+		return ReachUnknown, true
+	}
+	return ReachUnknown, false
+}
+
+// HealthSample is the result of one probe of a server's SSH port.
+type HealthSample struct {
+	At      time.Time
+	Up      bool
+	Latency time.Duration
+	Error   string
+}
+
+// Health is the probe history of a server, oldest sample first.
+type Health struct {
+	Samples []HealthSample
+}
+
+// Last returns the most recent sample.
+func (h Health) Last() (HealthSample, bool) {
+	if len(h.Samples) == 0 {
+		return HealthSample{}, false
+	}
+	return h.Samples[len(h.Samples)-1], true
+}
+
+// State tells whether the last probe succeeded.
+func (h Health) State() Reachability {
+	last, ok := h.Last()
+	switch {
+	case !ok:
+		return ReachUnknown
+	case last.Up:
+		return ReachUp
+	default:
+		return ReachDown
+	}
+}
+
+// Availability returns how many samples were up, out of how many.
+func (h Health) Availability() (up, total int) {
+	for _, s := range h.Samples {
+		if s.Up {
+			up++
+		}
+	}
+	return up, len(h.Samples)
+}
+
+// AverageLatency averages the latency of the successful probes.
+func (h Health) AverageLatency() time.Duration {
+	var sum time.Duration
+	n := 0
+	for _, s := range h.Samples {
+		if s.Up {
+			sum += s.Latency
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / time.Duration(n)
+}
@@ -0,0 +1,53 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+// HostKey is a public host key, either recorded in a known_hosts file or
+// presented by the server. File, Line, Hashed and Marker are only set for
+// recorded keys.
+type HostKey struct {
+	Type        string
+	Key         string // base64 blob
+	Fingerprint string // SHA256:…
+	File        string
+	Line        int
+	Hashed      bool
+	Marker      string // @cert-authority or @revoked
+}
+
+// HostKeyState is the outcome of comparing recorded keys with the server's keys.
+type HostKeyState int
+
+const (
+	// HostKeyUnchecked means the server has not been scanned.
+	HostKeyUnchecked HostKeyState = iota
+	// HostKeyUnknown means no key is recorded for the server yet.
+	HostKeyUnknown
+	// HostKeyMatch means a recorded key is one the server presents.
+	HostKeyMatch
+	// HostKeyMismatch means keys are recorded but the server presents none of
+	// them, e.g. after the machine was rebuilt.
+	HostKeyMismatch
+)
+
+// HostKeyCheck gathers what is known about a server's host keys.
+type HostKeyCheck struct {
+	// Host is the name looked up in known_hosts, "[host]:port" for non-default ports.
+	Host     string
+	Files    []string
+	Recorded []HostKey
+	Scanned  []HostKey
+	State    HostKeyState
+}
@@ -0,0 +1,98 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import "time"
+
+// ProbeStage is a step of connecting to a server, in order.
+type ProbeStage int
+
+const (
+	ProbeNone ProbeStage = iota
+	// ProbeResolved: ssh -G resolved the destination.
+	ProbeResolved
+	// ProbeConnected: the TCP connection (or the proxy) is up.
+	ProbeConnected
+	// ProbeBanner: the server sent its SSH version string.
+	ProbeBanner
+	// ProbeKeyExchange: the host key algorithm was negotiated.
+	ProbeKeyExchange
+	// ProbeAuthenticated: `ssh -o BatchMode=yes <alias> true` succeeded.
+	ProbeAuthenticated
+)
+
+func (s ProbeStage) String() string {
+	switch s {
+	case ProbeResolved:
+		return "resolve"
+	case ProbeConnected:
+		return "connect"
+	case ProbeBanner:
+		return "banner"
+	case ProbeKeyExchange:
+		return "key exchange"
+	case ProbeAuthenticated:
+		return "authentication"
+	default:
+		return "none"
+	}
+}
+
+// ProbeErrorClass tells why a probe stopped.
+type ProbeErrorClass string
+
+const (
+	ProbeOK          ProbeErrorClass = ""
+	ProbeErrConfig   ProbeErrorClass = "config"
+	ProbeErrDNS      ProbeErrorClass = "dns"
+	ProbeErrRefused  ProbeErrorClass = "refused"
+	ProbeErrTimeout  ProbeErrorClass = "timeout"
+	ProbeErrNetwork  ProbeErrorClass = "unreachable"
+	ProbeErrProxy    ProbeErrorClass = "proxy"
+	ProbeErrProtocol ProbeErrorClass = "protocol"
+	ProbeErrHostKey  ProbeErrorClass = "host key"
+	ProbeErrAuth     ProbeErrorClass = "auth"
+	ProbeErrOther    ProbeErrorClass = "other"
+)
+
+// ProbeTiming is how long a stage took.
+type ProbeTiming struct {
+	Stage    ProbeStage
+	Duration time.Duration
+}
+
+// ProbeResult is the outcome of an SSH-level probe of a server.
+type ProbeResult struct {
+	Alias string
+	// Address is the resolved host:port.
+	Address string
+	// Via names the ProxyJump or ProxyCommand the connection goes through, if any.
+	Via string
+	// Stage is the last stage reached successfully.
+	Stage   ProbeStage
+	Class   ProbeErrorClass
+	Error   string
+	Banner  string // e.g. "SSH-2.0-OpenSSH_9.6p1 Ubuntu-3"
+	HostKey string // negotiated host key algorithm, e.g. "ssh-ed25519"
+	// ServerHostKeys lists the host key algorithms the server offers.
+	ServerHostKeys []string
+	Timings        []ProbeTiming
+	Total          time.Duration
+}
+
+// OK reports whether every attempted stage succeeded.
+func (r ProbeResult) OK() bool {
+	return r.Class == ProbeOK
+}
@@ -30,6 +30,12 @@ type Server struct {
 	// Origin metadata
 	SourceFile string
 	Readonly   bool
+	// Revision identifies the content of SourceFile when the server was listed;
+	// updating or deleting the server fails with a ConfigConflictError once the
+	// file was changed since by something other than lazyssh.
+	Revision string
+	// Health is the probe history kept by the health monitor; it is not persisted.
+	Health Health
 
 	// Additional SSH config fields
 	// Connection and proxy settings
@@ -0,0 +1,63 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+// Key types ssh-keygen can create from the key wizard.
+const (
+	KeyTypeEd25519 = "ed25519"
+	KeyTypeECDSA   = "ecdsa"
+	KeyTypeRSA     = "rsa"
+)
+
+// KeySpec describes a keypair to generate. Bits is ignored for ed25519 and
+// defaults to the ssh-keygen default for the other types when zero.
+type KeySpec struct {
+	Type       string
+	Bits       int
+	Path       string
+	Comment    string
+	Passphrase string
+}
+
+// SSHKey is a private key found in ~/.ssh or referenced by a server's
+// IdentityFile, cross-referenced with ssh-agent and the servers using it.
+type SSHKey struct {
+	Path         string
+	Type         string
+	Bits         int
+	Fingerprint  string
+	Comment      string
+	HasPublicKey bool
+	Encrypted    bool
+	InAgent      bool
+	// Default is set for the key names ssh tries when no IdentityFile is given.
+	Default bool
+	// Missing is set when servers reference the path but no file exists.
+	Missing bool
+	Servers []string
+}
+
+// Unused reports whether no server references the key and ssh would not try it by default.
+func (k SSHKey) Unused() bool {
+	return !k.Missing && !k.Default && len(k.Servers) == 0
+}
+
+// AgentKey is an identity loaded in ssh-agent.
+type AgentKey struct {
+	Type        string
+	Bits        int
+	Fingerprint string
+	Comment     string
+}
@@ -0,0 +1,84 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import "time"
+
+// FileEntry is a file or directory in a local or remote listing.
+type FileEntry struct {
+	Name     string
+	Dir      bool
+	Link     bool
+	Size     int64
+	Mode     string
+	Modified string
+}
+
+// TransferDirection tells whether a transfer copies to or from the server.
+type TransferDirection int
+
+const (
+	Upload TransferDirection = iota
+	Download
+)
+
+// TransferState is the lifecycle of a queued transfer.
+type TransferState int
+
+const (
+	TransferQueued TransferState = iota
+	TransferRunning
+	TransferDone
+	TransferFailed
+	TransferCanceled
+)
+
+func (s TransferState) String() string {
+	switch s {
+	case TransferQueued:
+		return "queued"
+	case TransferRunning:
+		return "running"
+	case TransferDone:
+		return "done"
+	case TransferFailed:
+		return "failed"
+	case TransferCanceled:
+		return "canceled"
+	default:
+		return "unknown"
+	}
+}
+
+// Transfer copies Source into the directory Target with scp. For uploads Source is
+// local and Target remote; for downloads the other way round.
+type Transfer struct {
+	ID        int
+	Alias     string
+	Direction TransferDirection
+	Source    string
+	Target    string
+	Recursive bool
+
+	State   TransferState
+	File    string // file being copied, as reported by scp
+	Percent int
+	Rate    string
+	ETA     string
+	Error   string
+
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
@@ -0,0 +1,73 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import "time"
+
+// TunnelSpec asks for a background `ssh -N` to a server. Forwards use the
+// syntax of ssh -L/-R/-D, e.g. "8080:localhost:80" and "1080", like the
+// forwarding fields of Server. When all lists are empty the server's
+// configured forwards are used.
+type TunnelSpec struct {
+	Alias           string
+	LocalForwards   []string
+	RemoteForwards  []string
+	DynamicForwards []string
+}
+
+// AdHoc reports whether the spec names its own forwards.
+func (s TunnelSpec) AdHoc() bool {
+	return len(s.LocalForwards)+len(s.RemoteForwards)+len(s.DynamicForwards) > 0
+}
+
+// TunnelState is the lifecycle of a supervised tunnel.
+type TunnelState int
+
+const (
+	TunnelStarting TunnelState = iota
+	TunnelRunning
+	TunnelRestarting
+	TunnelFailed
+	TunnelStopped
+)
+
+func (s TunnelState) String() string {
+	switch s {
+	case TunnelStarting:
+		return "starting"
+	case TunnelRunning:
+		return "running"
+	case TunnelRestarting:
+		return "restarting"
+	case TunnelFailed:
+		return "failed"
+	default:
+		return "stopped"
+	}
+}
+
+// Tunnel is a supervised `ssh -N` process and the forwards it holds open.
+type Tunnel struct {
+	ID   int
+	Spec TunnelSpec
+	// Forwards lists the forwards in effect, e.g. "L 8080 → localhost:80".
+	Forwards []string
+	State    TunnelState
+	PID      int
+	// StartedAt is when the current process was started.
+	StartedAt time.Time
+	Restarts  int
+	LastError string
+}
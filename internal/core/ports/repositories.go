@@ -23,4 +23,28 @@ type ServerRepository interface {
 	DeleteServer(server domain.Server) error
 	SetPinned(alias string, pinned bool) error
 	RecordSSH(alias string) error
+	ListConfigBlocks() ([]domain.ConfigBlock, error)
+	ListConfigFiles() ([]domain.ConfigFile, error)
+	ListProfiles() ([]domain.Server, error)
+	AddProfile(profile domain.Server) error
+	UpdateProfile(profile domain.Server, newProfile domain.Server) error
+	DeleteProfile(profile domain.Server) error
+	// Batch runs fn with one backup per config file for all the writes it makes.
+	Batch(fn func() error) error
+	// Undo reverts the last recorded change and returns its description; Redo
+	// applies the last undone one again.
+	Undo() (string, error)
+	Redo() (string, error)
+	ListBackups() ([]domain.Backup, error)
+	ReadBackup(backup domain.Backup) ([]byte, error)
+	RestoreBackup(backup domain.Backup) error
+	// ServerLog returns the most recent commits that changed the Host block of
+	// server, or nothing when git versioning is off or its file is not in a repository.
+	ServerLog(server domain.Server) ([]domain.ConfigCommit, error)
+	// ResolveConflict lets the next write of a config file that changed on disk
+	// go ahead, merging with or overwriting the outside changes.
+	ResolveConflict(path string, resolution domain.ConflictResolution) error
+	// Watch calls onChange when the config files or the metadata change on disk
+	// other than through the repository, until stop is closed.
+	Watch(stop <-chan struct{}, onChange func()) error
 }
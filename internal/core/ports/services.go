@@ -15,6 +15,7 @@
 package ports
 
 import (
+	"context"
 	"time"
 
 	"github.com/Adembc/lazyssh/internal/core/domain"
@@ -25,8 +26,60 @@ type ServerService interface {
 	UpdateServer(server domain.Server, newServer domain.Server) error
 	AddServer(server domain.Server) error
 	DeleteServer(server domain.Server) error
+	UpdateServers(servers []domain.Server, change func(*domain.Server)) error
+	DeleteServers(servers []domain.Server) error
+	SetPinnedServers(servers []domain.Server, pinned bool) error
 	SetPinned(alias string, pinned bool) error
+	Undo() (string, error)
+	Redo() (string, error)
+	ListBackups() ([]domain.Backup, error)
+	DiffBackup(backup domain.Backup) (string, error)
+	RestoreBackup(backup domain.Backup) error
+	ServerLog(server domain.Server) ([]domain.ConfigCommit, error)
+	ResolveConflict(path string, resolution domain.ConflictResolution, retry func() error) error
+	StartWatch(onChange func())
+	StopWatch()
 	SSH(alias string) error
-	CopySSHKey(alias string) error
+	CopySSHKey(alias, publicKey string) error
+	CopyPublicKey(alias, key string) error
+	GenerateKey(spec domain.KeySpec) (string, error)
+	ListKeys() ([]domain.SSHKey, error)
+	ListAgentKeys() ([]domain.AgentKey, error)
+	AddAgentKey(path string, lifetime time.Duration, confirm bool) error
+	RemoveAgentKey(fingerprint string) error
+	RemoveAllAgentKeys() error
+	AgentWarnings(server domain.Server) []string
+	KnownHosts(server domain.Server) (domain.HostKeyCheck, error)
+	CheckHostKeys(server domain.Server) (domain.HostKeyCheck, error)
+	RemoveKnownHost(server domain.Server) (int, error)
+	TrustHostKeys(server domain.Server, keys []domain.HostKey) error
+	StartTunnel(spec domain.TunnelSpec) (int, error)
+	StopTunnel(id int) error
+	RestartTunnel(id int) error
+	ListTunnels() []domain.Tunnel
+	StopAllTunnels()
+	ListControlMasters() ([]domain.ControlMaster, error)
+	ExitControlMaster(alias string) error
+	StopControlMaster(alias string) error
+	KillControlMaster(master domain.ControlMaster) error
 	Ping(server domain.Server) (bool, time.Duration, error)
+	ProbeServer(server domain.Server, auth bool) domain.ProbeResult
+	Exec(ctx context.Context, req domain.ExecRequest, onEvent func(domain.ExecEvent)) ([]domain.ExecResult, error)
+	StartMonitor(onRound func())
+	StopMonitor()
+	ServerHealth() map[string]domain.Health
+	ListConfigBlocks() ([]domain.ConfigBlock, error)
+	ListConfigFiles() ([]domain.ConfigFile, error)
+	ExplainServer(server domain.Server) ([]domain.BlockContribution, error)
+	ListProfiles() ([]domain.Server, error)
+	AddProfile(profile domain.Server) error
+	UpdateProfile(profile domain.Server, newProfile domain.Server) error
+	DeleteProfile(profile domain.Server) error
+	ExplainInheritance(server domain.Server, contributions []domain.BlockContribution) []domain.InheritedSetting
+	ResolveServer(server domain.Server) (domain.EffectiveConfig, error)
+	ListLocalDir(path string) ([]domain.FileEntry, error)
+	ListRemoteDir(alias, path string) (string, []domain.FileEntry, error)
+	EnqueueTransfer(transfer domain.Transfer) (int, error)
+	ListTransfers() []domain.Transfer
+	CancelTransfer(id int) error
 }
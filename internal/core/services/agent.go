@@ -0,0 +1,272 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+// ErrNoAgent is returned when SSH_AUTH_SOCK does not point at a running ssh-agent.
+var ErrNoAgent = errors.New("no ssh-agent: SSH_AUTH_SOCK is not set or the agent is not running")
+
+// ListAgentKeys returns the identities loaded in the ssh-agent at SSH_AUTH_SOCK.
+func (s *serverService) ListAgentKeys() ([]domain.AgentKey, error) {
+	return listAgentKeys(os.Getenv("SSH_AUTH_SOCK"))
+}
+
+// listAgentKeys returns the identities loaded in the ssh-agent listening on socket.
+func listAgentKeys(socket string) ([]domain.AgentKey, error) {
+	out, err := runSSHAddAt(socket, "-l")
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]domain.AgentKey, 0, 4)
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		if bits, fp, comment, keyType, ok := parseKeyFingerprint(sc.Text()); ok {
+			keys = append(keys, domain.AgentKey{Type: keyType, Bits: bits, Fingerprint: fp, Comment: comment})
+		}
+	}
+	return keys, nil
+}
+
+// AddAgentKey loads a private key into ssh-agent, optionally for a limited time
+// and requiring confirmation on each use. ssh-add runs on the terminal so it can
+// ask for the key's passphrase.
+func (s *serverService) AddAgentKey(path string, lifetime time.Duration, confirm bool) error {
+	if os.Getenv("SSH_AUTH_SOCK") == "" {
+		return ErrNoAgent
+	}
+	if _, err := exec.LookPath("ssh-add"); err != nil {
+		return fmt.Errorf("ssh-add not found; install OpenSSH")
+	}
+	s.logger.Infow("ssh-add start", "path", path, "lifetime", lifetime, "confirm", confirm)
+	// #nosec G204 -- path is a key file chosen by the user
+	cmd := exec.Command("ssh-add", agentAddArgs(expandHome(path), lifetime, confirm)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		s.logger.Errorw("ssh-add failed", "path", path, "error", err)
+		return fmt.Errorf("ssh-add failed: %w", err)
+	}
+	s.logger.Infow("ssh-add end", "path", path)
+	return nil
+}
+
+// RemoveAgentKey unloads the identity with the given fingerprint from ssh-agent.
+func (s *serverService) RemoveAgentKey(fingerprint string) error {
+	keys, err := s.ListAgentKeys()
+	if err != nil {
+		return err
+	}
+	// ssh-add -L prints the public keys in the same order as -l.
+	out, err := runSSHAdd("-L")
+	if err != nil {
+		return err
+	}
+	lines := nonEmptyLines(string(out))
+	index := -1
+	for i, k := range keys {
+		if k.Fingerprint == fingerprint {
+			index = i
+		}
+	}
+	if index < 0 || index >= len(lines) {
+		return fmt.Errorf("key %s is not loaded in ssh-agent", fingerprint)
+	}
+
+	f, err := os.CreateTemp("", "lazyssh-agent-*.pub")
+	if err != nil {
+		return fmt.Errorf("failed to write public key: %w", err)
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+	if _, err := f.WriteString(lines[index] + "\n"); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write public key: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write public key: %w", err)
+	}
+	if _, err := runSSHAdd("-d", f.Name()); err != nil {
+		s.logger.Errorw("ssh-add -d failed", "fingerprint", fingerprint, "error", err)
+		return err
+	}
+	s.logger.Infow("removed agent key", "fingerprint", fingerprint)
+	return nil
+}
+
+// RemoveAllAgentKeys unloads every identity from ssh-agent.
+func (s *serverService) RemoveAllAgentKeys() error {
+	if _, err := runSSHAdd("-D"); err != nil {
+		s.logger.Errorw("ssh-add -D failed", "error", err)
+		return err
+	}
+	s.logger.Infow("removed all agent keys")
+	return nil
+}
+
+// AgentWarnings explains why authentication would fail for a server that uses
+// IdentitiesOnly yes while none of its IdentityFiles is usable: ssh then offers
+// neither the other agent keys nor the default ones. IdentitiesOnly, the
+// IdentityFiles and the IdentityAgent asked come from ssh -G, so values inherited
+// from profiles count; the server's own fields are used when ssh -G fails.
+func (s *serverService) AgentWarnings(server domain.Server) []string {
+	identitiesOnly, identityFiles := server.IdentitiesOnly, server.IdentityFiles
+	identityAgent := expandHome(server.IdentityAgent)
+	if opts, err := runSSHG(s.sshArgs(server.Alias)...); err == nil {
+		identitiesOnly, _ = lookupOption(opts, "identitiesonly")
+		identityFiles = optionValues(opts, "identityfile")
+		if v, ok := lookupOption(opts, "identityagent"); ok {
+			identityAgent = v
+		}
+	}
+	if !strings.EqualFold(identitiesOnly, "yes") || len(identityFiles) == 0 {
+		return nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	var agentKeys []domain.AgentKey
+	var agentErr error
+	if socket, ok := agentSocket(identityAgent); !ok {
+		agentErr = errors.New("IdentityAgent none: ssh does not use ssh-agent for this server")
+	} else {
+		agentKeys, agentErr = listAgentKeys(socket)
+		if errors.Is(agentErr, ErrNoAgent) && socket != os.Getenv("SSH_AUTH_SOCK") {
+			agentErr = fmt.Errorf("no ssh-agent at IdentityAgent %s", identityAgent)
+		}
+	}
+	loaded := make(map[string]bool, len(agentKeys))
+	for _, k := range agentKeys {
+		loaded[k.Fingerprint] = true
+	}
+
+	var warnings []string
+	localUser := currentUsername()
+	for _, idf := range identityFiles {
+		path, ok := resolveIdentityPath(idf, home, localUser)
+		if !ok {
+			continue
+		}
+		k := domain.SSHKey{Path: path}
+		inspectKey(&k)
+		switch {
+		case k.Fingerprint != "" && loaded[k.Fingerprint]:
+			// One usable identity is enough.
+			return nil
+		case k.Missing:
+			warnings = append(warnings, idf+": file does not exist")
+		case k.Encrypted:
+			warnings = append(warnings, idf+": not in ssh-agent; ssh will ask for its passphrase")
+		case !isPrivateKeyFile(path):
+			warnings = append(warnings, idf+": no private key and not loaded in ssh-agent")
+		default:
+			// An unencrypted private key on disk works without the agent.
+			return nil
+		}
+	}
+	if agentErr != nil && len(warnings) > 0 {
+		warnings = append(warnings, agentErr.Error())
+	}
+	return warnings
+}
+
+// agentAddArgs builds the ssh-add command line for AddAgentKey.
+func agentAddArgs(path string, lifetime time.Duration, confirm bool) []string {
+	var args []string
+	if lifetime > 0 {
+		args = append(args, "-t", strconv.Itoa(int(lifetime.Round(time.Second).Seconds())))
+	}
+	if confirm {
+		args = append(args, "-c")
+	}
+	return append(args, path)
+}
+
+// agentSocket returns the ssh-agent socket ssh uses with the IdentityAgent value
+// resolved by ssh -G, and false when it is "none" and no agent is used.
+func agentSocket(identityAgent string) (string, bool) {
+	switch {
+	case identityAgent == "" || identityAgent == "SSH_AUTH_SOCK":
+		return os.Getenv("SSH_AUTH_SOCK"), true
+	case strings.EqualFold(identityAgent, "none"):
+		return "", false
+	case strings.HasPrefix(identityAgent, "$"):
+		// The name of an environment variable holding the socket path.
+		return os.Getenv(strings.Trim(identityAgent[1:], "{}")), true
+	}
+	return identityAgent, true
+}
+
+// runSSHAdd runs a non-interactive ssh-add command against the agent at SSH_AUTH_SOCK.
+func runSSHAdd(args ...string) ([]byte, error) {
+	return runSSHAddAt(os.Getenv("SSH_AUTH_SOCK"), args...)
+}
+
+// runSSHAddAt runs a non-interactive ssh-add command against the agent listening
+// on socket. Exit status 1 of a listing means the agent has no identities and
+// yields empty output.
+func runSSHAddAt(socket string, args ...string) ([]byte, error) {
+	if socket == "" {
+		return nil, ErrNoAgent
+	}
+	if _, err := exec.LookPath("ssh-add"); err != nil {
+		return nil, fmt.Errorf("ssh-add not found; install OpenSSH")
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command("ssh-add", args...)
+	cmd.Env = append(os.Environ(), "SSH_AUTH_SOCK="+socket)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err == nil {
+		return out, nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		switch {
+		case exitErr.ExitCode() == 1 && (args[0] == "-l" || args[0] == "-L"):
+			return nil, nil
+		case exitErr.ExitCode() == 2:
+			return nil, ErrNoAgent
+		}
+	}
+	msg := strings.TrimSpace(stderr.String())
+	if msg == "" {
+		msg = err.Error()
+	}
+	return nil, fmt.Errorf("ssh-add %s: %s", args[0], lastLine(msg))
+}
+
+func nonEmptyLines(s string) []string {
+	var lines []string
+	for _, l := range strings.Split(s, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,129 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestAgentAddArgs(t *testing.T) {
+	tests := []struct {
+		lifetime time.Duration
+		confirm  bool
+		want     []string
+	}{
+		{0, false, []string{"/k"}},
+		{time.Hour, false, []string{"-t", "3600", "/k"}},
+		{90 * time.Second, true, []string{"-t", "90", "-c", "/k"}},
+	}
+	for _, tt := range tests {
+		if got := agentAddArgs("/k", tt.lifetime, tt.confirm); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("agentAddArgs(%v, %t) = %q, want %q", tt.lifetime, tt.confirm, got, tt.want)
+		}
+	}
+}
+
+func TestAgentSocket(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "/run/agent.sock")
+	t.Setenv("MY_AGENT", "/run/my.sock")
+	tests := []struct {
+		identityAgent string
+		want          string
+		ok            bool
+	}{
+		{"", "/run/agent.sock", true},
+		{"SSH_AUTH_SOCK", "/run/agent.sock", true},
+		{"none", "", false},
+		{"$MY_AGENT", "/run/my.sock", true},
+		{"${MY_AGENT}", "/run/my.sock", true},
+		{"/home/me/.1password/agent.sock", "/home/me/.1password/agent.sock", true},
+	}
+	for _, tt := range tests {
+		if got, ok := agentSocket(tt.identityAgent); got != tt.want || ok != tt.ok {
+			t.Errorf("agentSocket(%q) = %q, %t; want %q, %t", tt.identityAgent, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestAgentWarnings(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	dir := t.TempDir()
+	encrypted := filepath.Join(dir, "id_enc")
+	data := pem.EncodeToMemory(&pem.Block{
+		Type:    "RSA PRIVATE KEY",
+		Headers: map[string]string{"Proc-Type": "4,ENCRYPTED", "DEK-Info": "AES-128-CBC,00"},
+		Bytes:   []byte{1},
+	})
+	if err := os.WriteFile(encrypted, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "id_missing")
+	cfg := filepath.Join(dir, "config")
+	config := "Host plain\n  IdentityFile " + missing + "\n\n" +
+		"Host keys\n  IdentitiesOnly yes\n  IdentityFile " + missing + "\n  IdentityFile " + encrypted + "\n\n" +
+		"Host noagent\n  IdentitiesOnly yes\n  IdentityAgent none\n  IdentityFile " + encrypted + "\n\n" +
+		"Host *.prod\n  IdentitiesOnly yes\n  IdentityFile " + encrypted + "\n"
+	if err := os.WriteFile(cfg, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &serverService{sshConfigFile: cfg}
+	tests := []struct {
+		name   string
+		server domain.Server
+		want   []string
+	}{
+		{
+			name:   "identities only not set",
+			server: domain.Server{Alias: "plain", IdentityFiles: []string{missing}},
+		},
+		{
+			name:   "missing and encrypted keys",
+			server: domain.Server{Alias: "keys", IdentitiesOnly: "yes", IdentityFiles: []string{missing, encrypted}},
+			want:   []string{"file does not exist", "ssh will ask for its passphrase", "no ssh-agent"},
+		},
+		{
+			name:   "identity agent none",
+			server: domain.Server{Alias: "noagent", IdentitiesOnly: "yes", IdentityAgent: "none", IdentityFiles: []string{encrypted}},
+			want:   []string{"ssh will ask for its passphrase", "IdentityAgent none"},
+		},
+		{
+			name:   "inherited from a profile",
+			server: domain.Server{Alias: "web.prod"},
+			want:   []string{"ssh will ask for its passphrase", "no ssh-agent"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.AgentWarnings(tt.server)
+			if len(got) != len(tt.want) {
+				t.Fatalf("AgentWarnings() = %q, want %d warnings", got, len(tt.want))
+			}
+			for i, w := range tt.want {
+				if !strings.Contains(got[i], w) {
+					t.Errorf("warning %d = %q, want it to mention %q", i, got[i], w)
+				}
+			}
+		})
+	}
+}
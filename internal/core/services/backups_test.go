@@ -0,0 +1,120 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/core/ports"
+	"go.uber.org/zap"
+)
+
+func numberedLines(from, to int) string {
+	var b strings.Builder
+	for i := from; i <= to; i++ {
+		b.WriteString("line")
+		b.WriteString(string(rune('a' + i - 1)))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	base := numberedLines(1, 12) // linea … linel
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{name: "equal", a: base, b: base, want: ""},
+		{
+			name: "changed line",
+			a:    base,
+			b:    strings.Replace(base, "linef\n", "lineF\n", 1),
+			want: "--- a\n+++ b\n@@ -3,7 +3,7 @@\n linec\n lined\n linee\n-linef\n+lineF\n lineg\n lineh\n linei\n",
+		},
+		{
+			name: "appended line",
+			a:    "Host web\n",
+			b:    "Host web\n    User admin\n",
+			want: "--- a\n+++ b\n@@ -1 +1,2 @@\n Host web\n+    User admin\n",
+		},
+		{
+			name: "from empty",
+			a:    "",
+			b:    "Host web\n",
+			want: "--- a\n+++ b\n@@ -0,0 +1 @@\n+Host web\n",
+		},
+		{
+			name: "distant changes get separate hunks",
+			a:    base,
+			b:    strings.Replace(strings.Replace(base, "linea\n", "", 1), "linel\n", "lineL\n", 1),
+			want: "--- a\n+++ b\n@@ -1,4 +1,3 @@\n-linea\n lineb\n linec\n lined\n" +
+				"@@ -9,4 +8,4 @@\n linei\n linej\n linek\n-linel\n+lineL\n",
+		},
+		{
+			name: "close changes share a hunk",
+			a:    base,
+			b:    strings.Replace(strings.Replace(base, "lineb\n", "", 1), "lineh\n", "lineH\n", 1),
+			want: "--- a\n+++ b\n@@ -1,11 +1,10 @@\n linea\n-lineb\n linec\n lined\n linee\n linef\n lineg\n-lineh\n+lineH\n linei\n linej\n linek\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff("a", "b", tt.a, tt.b); got != tt.want {
+				t.Errorf("unifiedDiff() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// backupRepo reads backups straight from disk.
+type backupRepo struct {
+	ports.ServerRepository
+}
+
+func (backupRepo) ReadBackup(b domain.Backup) ([]byte, error) {
+	return os.ReadFile(b.Path)
+}
+
+func TestDiffBackup(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, "config")
+	backup := filepath.Join(dir, "config-1700000000000-lazyssh.backup")
+	if err := os.WriteFile(backup, []byte("Host web\n    User deploy\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(config, []byte("Host web\n    User admin\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s := &serverService{logger: zap.NewNop().Sugar(), serverRepository: backupRepo{}}
+
+	got, err := s.DiffBackup(domain.Backup{Path: backup, ConfigFile: config})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "--- " + backup + "\n+++ " + config + "\n@@ -1,2 +1,2 @@\n Host web\n-    User deploy\n+    User admin\n"
+	if got != want {
+		t.Errorf("DiffBackup() =\n%s\nwant:\n%s", got, want)
+	}
+
+	if _, err := s.DiffBackup(domain.Backup{Path: filepath.Join(dir, "missing"), ConfigFile: config}); err == nil {
+		t.Error("DiffBackup() of a missing backup succeeded")
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+// UpdateServers applies change to each server and saves the servers it changed
+// as one batch, with one backup per config file. A failing server does not stop
+// the others; the failures are returned together.
+func (s *serverService) UpdateServers(servers []domain.Server, change func(*domain.Server)) error {
+	return s.serverRepository.Batch(func() error {
+		var errs []error
+		updated := 0
+		for _, srv := range servers {
+			next := cloneServer(srv)
+			change(&next)
+			if reflect.DeepEqual(srv, next) {
+				continue
+			}
+			if err := s.UpdateServer(srv, next); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", srv.Alias, err))
+				continue
+			}
+			updated++
+		}
+		s.logger.Infow("bulk update", "servers", len(servers), "updated", updated, "failed", len(errs))
+		return errors.Join(errs...)
+	})
+}
+
+// DeleteServers removes the servers as one batch, with one backup per config file.
+func (s *serverService) DeleteServers(servers []domain.Server) error {
+	return s.serverRepository.Batch(func() error {
+		var errs []error
+		for _, srv := range servers {
+			if err := s.DeleteServer(srv); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", srv.Alias, err))
+			}
+		}
+		s.logger.Infow("bulk delete", "servers", len(servers), "failed", len(errs))
+		return errors.Join(errs...)
+	})
+}
+
+// SetPinnedServers pins or unpins the servers as one batch, so a single undo
+// reverts them all.
+func (s *serverService) SetPinnedServers(servers []domain.Server, pinned bool) error {
+	return s.serverRepository.Batch(func() error {
+		var errs []error
+		for _, srv := range servers {
+			if err := s.SetPinned(srv.Alias, pinned); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", srv.Alias, err))
+			}
+		}
+		s.logger.Infow("bulk pin", "servers", len(servers), "pinned", pinned, "failed", len(errs))
+		return errors.Join(errs...)
+	})
+}
+
+// cloneServer copies the list fields of a server so a change does not write
+// through to the original.
+func cloneServer(srv domain.Server) domain.Server {
+	v := reflect.ValueOf(&srv).Elem()
+	for i := range v.NumField() {
+		if f := v.Field(i); f.Kind() == reflect.Slice && !f.IsNil() {
+			f.Set(reflect.AppendSlice(reflect.MakeSlice(f.Type(), 0, f.Len()), f))
+		}
+	}
+	srv.Health.Samples = slices.Clone(srv.Health.Samples)
+	return srv
+}
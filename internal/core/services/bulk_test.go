@@ -0,0 +1,119 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/Adembc/lazyssh/internal/core/ports"
+	"go.uber.org/zap"
+)
+
+// batchRepo records the writes made through it and the batches around them.
+type batchRepo struct {
+	ports.ServerRepository
+	batches int
+	inBatch bool
+	updated []domain.Server
+	deleted []string
+	pinned  []string
+}
+
+func (r *batchRepo) Batch(fn func() error) error {
+	r.batches++
+	r.inBatch = true
+	defer func() { r.inBatch = false }()
+	return fn()
+}
+
+func (r *batchRepo) UpdateServer(_ domain.Server, newServer domain.Server) error {
+	if !r.inBatch {
+		return errors.New("write outside the batch")
+	}
+	if newServer.Readonly {
+		return errors.New("read-only")
+	}
+	r.updated = append(r.updated, newServer)
+	return nil
+}
+
+func (r *batchRepo) DeleteServer(server domain.Server) error {
+	if !r.inBatch {
+		return errors.New("write outside the batch")
+	}
+	r.deleted = append(r.deleted, server.Alias)
+	return nil
+}
+
+func (r *batchRepo) SetPinned(alias string, _ bool) error {
+	if !r.inBatch {
+		return errors.New("write outside the batch")
+	}
+	r.pinned = append(r.pinned, alias)
+	return nil
+}
+
+func TestUpdateServers(t *testing.T) {
+	repo := &batchRepo{}
+	s := &serverService{logger: zap.NewNop().Sugar(), serverRepository: repo}
+	servers := []domain.Server{
+		{Alias: "web", Host: "10.0.0.1", Tags: []string{"prod"}},
+		{Alias: "db", Host: "10.0.0.2", Tags: []string{"prod", "eu"}},
+		{Alias: "ro", Host: "10.0.0.3", Readonly: true},
+	}
+	err := s.UpdateServers(servers, func(srv *domain.Server) {
+		if !slices.Contains(srv.Tags, "eu") {
+			srv.Tags = append(srv.Tags, "eu")
+		}
+	})
+	if err == nil || !strings.Contains(err.Error(), "ro: read-only") {
+		t.Errorf("UpdateServers() error = %v, want the read-only failure", err)
+	}
+	if repo.batches != 1 {
+		t.Errorf("got %d batches, want 1", repo.batches)
+	}
+	if len(repo.updated) != 1 || repo.updated[0].Alias != "web" || strings.Join(repo.updated[0].Tags, ",") != "prod,eu" {
+		t.Errorf("updated = %+v, want only web with the new tag (db is unchanged)", repo.updated)
+	}
+	if strings.Join(servers[0].Tags, ",") != "prod" {
+		t.Errorf("the change wrote through to the original: %v", servers[0].Tags)
+	}
+}
+
+func TestDeleteServers(t *testing.T) {
+	repo := &batchRepo{}
+	s := &serverService{logger: zap.NewNop().Sugar(), serverRepository: repo}
+	if err := s.DeleteServers([]domain.Server{{Alias: "web"}, {Alias: "db"}}); err != nil {
+		t.Fatal(err)
+	}
+	if repo.batches != 1 || strings.Join(repo.deleted, ",") != "web,db" {
+		t.Errorf("batches = %d, deleted = %v", repo.batches, repo.deleted)
+	}
+}
+
+func TestSetPinnedServers(t *testing.T) {
+	repo := &batchRepo{}
+	s := &serverService{logger: zap.NewNop().Sugar(), serverRepository: repo}
+	if err := s.SetPinnedServers([]domain.Server{{Alias: "web"}, {Alias: "db"}}, true); err != nil {
+		t.Fatal(err)
+	}
+	if repo.batches != 1 || strings.Join(repo.pinned, ",") != "web,db" {
+		t.Errorf("batches = %d, pinned = %v", repo.batches, repo.pinned)
+	}
+}
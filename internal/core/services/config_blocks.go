@@ -0,0 +1,179 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"os/user"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+// ListConfigBlocks returns every Host and Match block of the SSH config in file order.
+func (s *serverService) ListConfigBlocks() ([]domain.ConfigBlock, error) {
+	blocks, err := s.serverRepository.ListConfigBlocks()
+	if err != nil {
+		s.logger.Errorw("failed to list config blocks", "error", err)
+	}
+	return blocks, err
+}
+
+// ListConfigFiles returns the main SSH config followed by the files it includes.
+func (s *serverService) ListConfigFiles() ([]domain.ConfigFile, error) {
+	files, err := s.serverRepository.ListConfigFiles()
+	if err != nil {
+		s.logger.Errorw("failed to list config files", "error", err)
+	}
+	return files, err
+}
+
+// ExplainServer reports the Host and Match blocks that contribute to the server's
+// effective settings, in evaluation order (for each keyword ssh uses the first value found).
+func (s *serverService) ExplainServer(server domain.Server) ([]domain.BlockContribution, error) {
+	blocks, err := s.ListConfigBlocks()
+	if err != nil {
+		return nil, err
+	}
+	localUser := currentUsername()
+	out := make([]domain.BlockContribution, 0, 4)
+	for _, b := range blocks {
+		state, reason := evaluateBlock(b, server, localUser)
+		if state == domain.MatchNo {
+			continue
+		}
+		out = append(out, domain.BlockContribution{Block: b, State: state, Reason: reason})
+	}
+	return out, nil
+}
+
+// evaluateBlock decides whether a block applies to the server without connecting.
+func evaluateBlock(b domain.ConfigBlock, server domain.Server, localUser string) (domain.MatchState, string) {
+	if b.Kind == domain.BlockHost {
+		if hostPatternsMatch(b.Patterns, server.Alias) {
+			return domain.MatchYes, ""
+		}
+		return domain.MatchNo, ""
+	}
+
+	state := domain.MatchYes
+	var reasons []string
+	for _, c := range b.Criteria {
+		st, reason := evaluateCriterion(c, server, localUser)
+		switch st {
+		case domain.MatchNo:
+			return domain.MatchNo, ""
+		case domain.MatchConditional:
+			state = domain.MatchConditional
+			reasons = append(reasons, reason)
+		case domain.MatchYes:
+		}
+	}
+	return state, strings.Join(reasons, "; ")
+}
+
+func evaluateCriterion(c domain.MatchCriterion, server domain.Server, localUser string) (domain.MatchState, string) {
+	var matched bool
+	switch c.Keyword {
+	case "all":
+		matched = true
+	case "host":
+		target := server.Host
+		if target == "" {
+			target = server.Alias
+		}
+		matched = patternListMatch(c.Argument, target)
+	case "originalhost":
+		matched = patternListMatch(c.Argument, server.Alias)
+	case "user":
+		remoteUser := server.User
+		if remoteUser == "" {
+			remoteUser = localUser
+		}
+		matched = patternListMatch(c.Argument, remoteUser)
+	case "localuser":
+		matched = patternListMatch(c.Argument, localUser)
+	case "exec":
+		return domain.MatchConditional, "exec runs at connect time"
+	case "canonical", "final":
+		return domain.MatchConditional, c.Keyword + " depends on hostname canonicalization"
+	default:
+		return domain.MatchConditional, c.Keyword + " is evaluated at connect time"
+	}
+	if matched != c.Negated {
+		return domain.MatchYes, ""
+	}
+	return domain.MatchNo, ""
+}
+
+// hostPatternsMatch applies Host semantics: any positive pattern must match and
+// a matching negated pattern excludes the host.
+func hostPatternsMatch(patterns []string, name string) bool {
+	found := false
+	for _, p := range patterns {
+		if strings.HasPrefix(p, "!") {
+			if wildcardMatch(p[1:], name) {
+				return false
+			}
+			continue
+		}
+		if wildcardMatch(p, name) {
+			found = true
+		}
+	}
+	return found
+}
+
+// patternListMatch applies Match semantics to a comma-separated pattern list.
+func patternListMatch(list, name string) bool {
+	return hostPatternsMatch(strings.Split(list, ","), name)
+}
+
+// wildcardMatch implements ssh_config patterns: '*' matches any sequence and '?'
+// exactly one character. Matching is case-insensitive like hostnames.
+func wildcardMatch(pattern, name string) bool {
+	p := []rune(strings.ToLower(strings.TrimSpace(pattern)))
+	n := []rune(strings.ToLower(name))
+	pi, ni := 0, 0
+	star, mark := -1, 0
+	for ni < len(n) {
+		switch {
+		case pi < len(p) && (p[pi] == '?' || p[pi] == n[ni]):
+			pi++
+			ni++
+		case pi < len(p) && p[pi] == '*':
+			star = pi
+			mark = ni
+			pi++
+		case star != -1:
+			pi = star + 1
+			mark++
+			ni = mark
+		default:
+			return false
+		}
+	}
+	for pi < len(p) && p[pi] == '*' {
+		pi++
+	}
+	return pi == len(p)
+}
+
+func currentUsername() string {
+	u, err := user.Current()
+	if err != nil {
+		return ""
+	}
+	return u.Username
+}
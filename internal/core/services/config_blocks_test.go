@@ -0,0 +1,82 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestWildcardMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"*", "anything", true},
+		{"*.prod.example.com", "web.prod.example.com", true},
+		{"*.prod.example.com", "web.staging.example.com", false},
+		{"web-??", "web-01", true},
+		{"web-??", "web-1", false},
+		{"WEB*", "web-01", true},
+		{"db", "db1", false},
+	}
+	for _, tt := range tests {
+		if got := wildcardMatch(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("wildcardMatch(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestHostPatternsMatchNegation(t *testing.T) {
+	patterns := []string{"*", "!bastion"}
+	if !hostPatternsMatch(patterns, "web") {
+		t.Error("expected web to match")
+	}
+	if hostPatternsMatch(patterns, "bastion") {
+		t.Error("negated pattern should exclude bastion")
+	}
+}
+
+func TestEvaluateBlock(t *testing.T) {
+	server := domain.Server{Alias: "web", Host: "web.prod", User: "deploy"}
+	tests := []struct {
+		name     string
+		criteria []domain.MatchCriterion
+		want     domain.MatchState
+	}{
+		{"all", []domain.MatchCriterion{{Keyword: "all"}}, domain.MatchYes},
+		{"host matches hostname", []domain.MatchCriterion{{Keyword: "host", Argument: "*.prod"}}, domain.MatchYes},
+		{"originalhost uses alias", []domain.MatchCriterion{{Keyword: "originalhost", Argument: "*.prod"}}, domain.MatchNo},
+		{"negated user", []domain.MatchCriterion{{Keyword: "user", Negated: true, Argument: "deploy"}}, domain.MatchNo},
+		{"exec is conditional", []domain.MatchCriterion{
+			{Keyword: "host", Argument: "*.prod"},
+			{Keyword: "exec", Argument: "true"},
+		}, domain.MatchConditional},
+		{"mismatch wins over conditional", []domain.MatchCriterion{
+			{Keyword: "exec", Argument: "true"},
+			{Keyword: "user", Argument: "root"},
+		}, domain.MatchNo},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block := domain.ConfigBlock{Kind: domain.BlockMatch, Criteria: tt.criteria}
+			if got, _ := evaluateBlock(block, server, "me"); got != tt.want {
+				t.Errorf("evaluateBlock() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"sync"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+// configWatch is the running watch of the config files, if any.
+type configWatch struct {
+	mu   sync.Mutex
+	stop chan struct{}
+}
+
+// StartWatch calls onChange whenever the config files or the metadata are
+// changed on disk by something other than lazyssh. It does nothing when the
+// watch is already running.
+func (s *serverService) StartWatch(onChange func()) {
+	w := &s.watch
+	w.mu.Lock()
+	if w.stop != nil {
+		w.mu.Unlock()
+		return
+	}
+	stop := make(chan struct{})
+	w.stop = stop
+	w.mu.Unlock()
+
+	s.logger.Infow("config watch start")
+	go func() {
+		if err := s.serverRepository.Watch(stop, onChange); err != nil {
+			s.logger.Warnw("config watch failed", "error", err)
+		}
+	}()
+}
+
+// StopWatch stops watching the config files.
+func (s *serverService) StopWatch() {
+	w := &s.watch
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.stop != nil {
+		close(w.stop)
+		w.stop = nil
+		s.logger.Infow("config watch stop")
+	}
+}
+
+// ResolveConflict retries a write that failed with a ConfigConflictError for
+// path, after merging with or discarding the changes made outside lazyssh. Both
+// steps are one change, with a single backup of the file.
+func (s *serverService) ResolveConflict(path string, resolution domain.ConflictResolution, retry func() error) error {
+	err := s.serverRepository.Batch(func() error {
+		if err := s.serverRepository.ResolveConflict(path, resolution); err != nil {
+			return err
+		}
+		return retry()
+	})
+	if err != nil {
+		s.logger.Errorw("failed to resolve config conflict", "path", path, "resolution", resolution, "error", err)
+		return err
+	}
+	s.logger.Infow("resolved config conflict", "path", path, "resolution", resolution)
+	return nil
+}
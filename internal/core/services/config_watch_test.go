@@ -0,0 +1,87 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"go.uber.org/zap"
+)
+
+// conflictRepo records conflict resolutions and watches.
+type conflictRepo struct {
+	batchRepo
+	resolved []domain.ConflictResolution
+	watches  chan (<-chan struct{})
+}
+
+func (r *conflictRepo) ResolveConflict(_ string, resolution domain.ConflictResolution) error {
+	if !r.inBatch {
+		return errors.New("resolved outside the batch")
+	}
+	r.resolved = append(r.resolved, resolution)
+	return nil
+}
+
+func (r *conflictRepo) Watch(stop <-chan struct{}, _ func()) error {
+	r.watches <- stop
+	<-stop
+	return nil
+}
+
+func TestResolveConflict(t *testing.T) {
+	repo := &conflictRepo{}
+	s := &serverService{logger: zap.NewNop().Sugar(), serverRepository: repo}
+
+	retry := func() error { return repo.UpdateServer(domain.Server{}, domain.Server{Alias: "web"}) }
+	if err := s.ResolveConflict("/tmp/config", domain.ConflictOverwrite, retry); err != nil {
+		t.Fatal(err)
+	}
+	if repo.batches != 1 || len(repo.resolved) != 1 || repo.resolved[0] != domain.ConflictOverwrite || len(repo.updated) != 1 {
+		t.Errorf("batches = %d, resolved = %v, updated = %v; want the resolution and the retry in one batch",
+			repo.batches, repo.resolved, repo.updated)
+	}
+
+	failed := errors.New("still failing")
+	if err := s.ResolveConflict("/tmp/config", domain.ConflictMerge, func() error { return failed }); !errors.Is(err, failed) {
+		t.Errorf("ResolveConflict() error = %v, want the retry's error", err)
+	}
+}
+
+func TestStartStopWatch(t *testing.T) {
+	repo := &conflictRepo{watches: make(chan (<-chan struct{}), 2)}
+	s := &serverService{logger: zap.NewNop().Sugar(), serverRepository: repo}
+
+	s.StartWatch(func() {})
+	s.StartWatch(func() {})
+	var stop <-chan struct{}
+	select {
+	case stop = <-repo.watches:
+	case <-time.After(time.Second):
+		t.Fatal("the watch did not start")
+	}
+	s.StopWatch()
+	select {
+	case <-stop:
+	case <-time.After(time.Second):
+		t.Fatal("StopWatch() did not stop the watch")
+	}
+	if len(repo.watches) != 0 {
+		t.Error("StartWatch() started a second watch while one was running")
+	}
+}
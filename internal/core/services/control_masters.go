@@ -0,0 +1,191 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+const (
+	// controlCheckTimeout bounds `ssh -O`; a hung master accepts the connection but never answers.
+	controlCheckTimeout = 3 * time.Second
+	// controlWorkers bounds the ssh processes started while listing masters.
+	controlWorkers = 8
+)
+
+var masterPIDRe = regexp.MustCompile(`pid=(\d+)`)
+
+// ListControlMasters returns the multiplexing master of every server that has a
+// ControlPath, and whether it is alive.
+func (s *serverService) ListControlMasters() ([]domain.ControlMaster, error) {
+	servers, err := s.ListServers("")
+	if err != nil {
+		return nil, err
+	}
+
+	masters := make([]domain.ControlMaster, len(servers))
+	found := make([]bool, len(servers))
+	sem := make(chan struct{}, controlWorkers)
+	var wg sync.WaitGroup
+	for i, srv := range servers {
+		wg.Add(1)
+		go func(i int, alias string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			masters[i], found[i] = s.inspectControlMaster(alias)
+		}(i, srv.Alias)
+	}
+	wg.Wait()
+
+	out := make([]domain.ControlMaster, 0, len(masters))
+	for i, m := range masters {
+		if found[i] {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+// ExitControlMaster asks the master to exit now (`ssh -O exit`), closing its sessions.
+func (s *serverService) ExitControlMaster(alias string) error {
+	_, err := s.controlCommand(alias, "exit")
+	if err != nil {
+		s.logger.Errorw("ssh -O exit failed", "alias", alias, "error", err)
+		return err
+	}
+	s.logger.Infow("control master exited", "alias", alias)
+	return nil
+}
+
+// StopControlMaster asks the master to stop accepting sessions (`ssh -O stop`);
+// it exits once the last one closes.
+func (s *serverService) StopControlMaster(alias string) error {
+	_, err := s.controlCommand(alias, "stop")
+	if err != nil {
+		s.logger.Errorw("ssh -O stop failed", "alias", alias, "error", err)
+		return err
+	}
+	s.logger.Infow("control master stopped", "alias", alias)
+	return nil
+}
+
+// KillControlMaster gets rid of a master that cannot be asked to exit: a hung
+// process is killed and a socket nobody listens on is removed.
+func (s *serverService) KillControlMaster(master domain.ControlMaster) error {
+	if master.PID > 0 {
+		proc, err := os.FindProcess(master.PID)
+		if err == nil {
+			err = proc.Kill()
+		}
+		if err != nil && !errors.Is(err, os.ErrProcessDone) {
+			s.logger.Errorw("failed to kill control master", "alias", master.Alias, "pid", master.PID, "error", err)
+			return fmt.Errorf("kill %d: %w", master.PID, err)
+		}
+	}
+	// A killed master leaves its socket behind.
+	if fi, err := os.Lstat(master.Path); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return fmt.Errorf("%s is not a socket", master.Path)
+		}
+		if err := os.Remove(master.Path); err != nil {
+			s.logger.Errorw("failed to remove control socket", "path", master.Path, "error", err)
+			return err
+		}
+	}
+	s.logger.Infow("control master killed", "alias", master.Alias, "pid", master.PID, "path", master.Path)
+	return nil
+}
+
+// inspectControlMaster resolves the control settings of alias; ok is false when
+// the server does not use a control path.
+func (s *serverService) inspectControlMaster(alias string) (domain.ControlMaster, bool) {
+	opts, err := runSSHG(s.sshArgs(alias)...)
+	if err != nil {
+		s.logger.Warnw("failed to resolve control path", "alias", alias, "error", err)
+		return domain.ControlMaster{}, false
+	}
+	path, _ := lookupOption(opts, "controlpath")
+	if path == "" || strings.EqualFold(path, "none") {
+		return domain.ControlMaster{}, false
+	}
+	m := domain.ControlMaster{Alias: alias, Path: path, Sessions: -1}
+	m.Mode, _ = lookupOption(opts, "controlmaster")
+	m.Persist, _ = lookupOption(opts, "controlpersist")
+
+	if _, err := os.Lstat(path); err != nil {
+		return m, true
+	}
+	out, err := s.controlCommand(alias, "check")
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		m.State = domain.ControlHung
+		m.PID = controlSocketPID(path)
+		m.Error = fmt.Sprintf("no answer within %s", controlCheckTimeout)
+	case err != nil:
+		m.State = domain.ControlStale
+		m.Error = err.Error()
+	default:
+		m.State = domain.ControlAlive
+		m.PID = parseMasterPID(out)
+		m.Sessions = controlSessions(path)
+	}
+	return m, true
+}
+
+// controlCommand runs `ssh -O cmd alias` and returns its output.
+func (s *serverService) controlCommand(alias, cmd string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), controlCheckTimeout)
+	defer cancel()
+
+	var out bytes.Buffer
+	// #nosec G204 -- alias comes from the user's SSH config
+	c := exec.CommandContext(ctx, "ssh", s.sshArgs("-O", cmd, alias)...)
+	c.Stdout = &out
+	c.Stderr = &out
+	err := c.Run()
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	if err != nil {
+		if msg := lastLine(out.String()); msg != "" {
+			return "", errors.New(msg)
+		}
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// parseMasterPID reads the pid from "Master running (pid=1234)".
+func parseMasterPID(out string) int {
+	m := masterPIDRe.FindStringSubmatch(out)
+	if m == nil {
+		return 0
+	}
+	pid, _ := strconv.Atoi(m[1])
+	return pid
+}
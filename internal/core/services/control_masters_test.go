@@ -0,0 +1,110 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"go.uber.org/zap"
+)
+
+func TestParseMasterPID(t *testing.T) {
+	tests := []struct {
+		out  string
+		want int
+	}{
+		{"Master running (pid=4242)\n", 4242},
+		{"Control socket connect(/tmp/cm): Connection refused", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := parseMasterPID(tt.out); got != tt.want {
+			t.Errorf("parseMasterPID(%q) = %d, want %d", tt.out, got, tt.want)
+		}
+	}
+}
+
+func TestControlSessions(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("session counting reads /proc/net/unix")
+	}
+	path := filepath.Join(t.TempDir(), "cm")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	accepted := make(chan net.Conn, 2)
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			accepted <- c
+		}
+	}()
+	for range 2 {
+		c, err := net.Dial("unix", path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer c.Close()
+		defer (<-accepted).Close()
+	}
+
+	if got := controlSessions(path); got != 2 {
+		t.Errorf("controlSessions() = %d, want 2", got)
+	}
+	if got := controlSocketPID(path); got != os.Getpid() {
+		t.Errorf("controlSocketPID() = %d, want %d", got, os.Getpid())
+	}
+}
+
+func TestKillControlMasterRemovesStaleSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix sockets")
+	}
+	path := filepath.Join(t.TempDir(), "cm")
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Keep the socket file when closing, like a master that died.
+	ln.(*net.UnixListener).SetUnlinkOnClose(false)
+	_ = ln.Close()
+
+	s := &serverService{logger: zap.NewNop().Sugar()}
+	if err := s.KillControlMaster(domain.ControlMaster{Alias: "web", Path: path, State: domain.ControlStale}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Lstat(path); !os.IsNotExist(err) {
+		t.Errorf("socket still exists: %v", err)
+	}
+
+	file := filepath.Join(t.TempDir(), "not-a-socket")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.KillControlMaster(domain.ControlMaster{Alias: "web", Path: file}); err == nil {
+		t.Error("expected an error for a regular file")
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// unixSocketEntry is a line of /proc/net/unix.
+type unixSocketEntry struct {
+	inode     string
+	listening bool
+	connected bool
+	path      string
+}
+
+// parseProcNetUnix parses /proc/net/unix. Connections accepted by a listening
+// socket are reported with the listener's path.
+func parseProcNetUnix(data []byte) []unixSocketEntry {
+	var entries []unixSocketEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Scan() // header
+	for scanner.Scan() {
+		// Num RefCount Protocol Flags Type St Inode [Path]
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 8 {
+			continue
+		}
+		flags, err := strconv.ParseUint(fields[3], 16, 32)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, unixSocketEntry{
+			inode:     fields[6],
+			listening: flags&0x10000 != 0, // __SO_ACCEPTCON
+			connected: fields[5] == "03",  // SS_CONNECTED
+			path:      fields[7],
+		})
+	}
+	return entries
+}
+
+// controlSessions counts the clients connected to the master listening on path.
+func controlSessions(path string) int {
+	data, err := os.ReadFile("/proc/net/unix")
+	if err != nil {
+		return -1
+	}
+	n := 0
+	for _, e := range parseProcNetUnix(data) {
+		if e.path == path && e.connected && !e.listening {
+			n++
+		}
+	}
+	return n
+}
+
+// controlSocketPID finds the process listening on path, for masters too hung to
+// report their pid.
+func controlSocketPID(path string) int {
+	data, err := os.ReadFile("/proc/net/unix")
+	if err != nil {
+		return 0
+	}
+	target := ""
+	for _, e := range parseProcNetUnix(data) {
+		if e.path == path && e.listening {
+			target = "socket:[" + e.inode + "]"
+			break
+		}
+	}
+	if target == "" {
+		return 0
+	}
+	fds, _ := filepath.Glob("/proc/[0-9]*/fd/*")
+	for _, fd := range fds {
+		if link, err := os.Readlink(fd); err == nil && link == target {
+			pid, _ := strconv.Atoi(strings.Split(fd, "/")[2])
+			return pid
+		}
+	}
+	return 0
+}
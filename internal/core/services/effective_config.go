@@ -0,0 +1,214 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+// sshResolveTimeout bounds `ssh -G`, which may run `Match exec` commands.
+const sshResolveTimeout = 5 * time.Second
+
+// keywordAliases maps deprecated keywords to the name ssh -G prints.
+var keywordAliases = map[string]string{
+	"pubkeyacceptedkeytypes":    "pubkeyacceptedalgorithms",
+	"hostbasedkeytypes":         "hostbasedacceptedalgorithms",
+	"hostbasedacceptedkeytypes": "hostbasedacceptedalgorithms",
+}
+
+// sshOption is one keyword of `ssh -G` output; repeated keywords are merged.
+type sshOption struct {
+	key    string
+	values []string
+}
+
+// ResolveServer runs `ssh -G` for the server and compares every resolved option with
+// the literal values of its Host block, so inherited values and ssh defaults stand out.
+func (s *serverService) ResolveServer(server domain.Server) (domain.EffectiveConfig, error) {
+	resolved, err := runSSHG(s.sshArgs(server.Alias)...)
+	if err != nil {
+		s.logger.Errorw("ssh -G failed", "alias", server.Alias, "error", err)
+		return domain.EffectiveConfig{}, err
+	}
+	// The same query without any config file gives ssh's built-in defaults.
+	defaults, err := runSSHG("-F", os.DevNull, server.Alias)
+	if err != nil {
+		s.logger.Warnw("ssh -G without config failed", "alias", server.Alias, "error", err)
+	}
+	contributions, err := s.ExplainServer(server)
+	if err != nil {
+		s.logger.Warnw("failed to explain server", "alias", server.Alias, "error", err)
+	}
+
+	return domain.EffectiveConfig{
+		Alias:   server.Alias,
+		Options: compareOptions(server, resolved, defaults, contributions),
+	}, nil
+}
+
+// compareOptions classifies each resolved option by where its value comes from.
+func compareOptions(server domain.Server, resolved, defaults []sshOption, contributions []domain.BlockContribution) []domain.EffectiveOption {
+	defaultValues := make(map[string]string, len(defaults))
+	for _, o := range defaults {
+		defaultValues[o.key] = strings.Join(o.values, ", ")
+	}
+
+	own := -1
+	for i, c := range contributions {
+		if c.State == domain.MatchYes && isOwnBlock(c.Block, server) {
+			own = i
+			break
+		}
+	}
+	literals := make(map[string][]string)
+	if own >= 0 {
+		for _, st := range contributions[own].Block.Settings {
+			key := canonicalKeyword(st.Key)
+			literals[key] = append(literals[key], unquoteValue(st.Value))
+		}
+	}
+
+	options := make([]domain.EffectiveOption, 0, len(resolved))
+	for _, o := range resolved {
+		opt := domain.EffectiveOption{Key: o.key, Value: strings.Join(o.values, ", ")}
+		if lit, ok := literals[o.key]; ok {
+			opt.Literal = strings.Join(lit, ", ")
+			opt.Source = domain.SourceHost
+			// ssh normalizes some values (durations, forwards), so a different value
+			// only means "overridden" when a block read earlier sets the keyword.
+			if i := firstSettingBlock(contributions, o.key, own); i >= 0 && !strings.EqualFold(opt.Literal, opt.Value) {
+				opt.Source = domain.SourceOverridden
+				opt.From = describeBlock(contributions[i].Block)
+			}
+		} else if def, ok := defaultValues[o.key]; ok && def == opt.Value {
+			opt.Source = domain.SourceDefault
+		} else {
+			opt.Source = domain.SourceInherited
+			if i := firstSettingBlock(contributions, o.key, len(contributions)); i >= 0 && i != own {
+				opt.From = describeBlock(contributions[i].Block)
+			} else {
+				opt.From = "system-wide config or command line"
+			}
+		}
+		options = append(options, opt)
+	}
+	return options
+}
+
+// firstSettingBlock returns the index of the first contributing block before limit
+// that sets the keyword, or -1.
+func firstSettingBlock(contributions []domain.BlockContribution, key string, limit int) int {
+	for i := 0; i < limit && i < len(contributions); i++ {
+		for _, st := range contributions[i].Block.Settings {
+			if canonicalKeyword(st.Key) == key {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func describeBlock(b domain.ConfigBlock) string {
+	location := filepath.Base(b.SourceFile)
+	if b.Line > 0 {
+		location = fmt.Sprintf("%s:%d", location, b.Line)
+	}
+	return fmt.Sprintf("%s (%s)", b.Header(), location)
+}
+
+func canonicalKeyword(key string) string {
+	key = strings.ToLower(key)
+	if alias, ok := keywordAliases[key]; ok {
+		return alias
+	}
+	return key
+}
+
+func unquoteValue(v string) string {
+	v = strings.TrimSpace(v)
+	if len(v) >= 2 && v[0] == '"' && v[len(v)-1] == '"' {
+		return v[1 : len(v)-1]
+	}
+	return v
+}
+
+// runSSHG runs `ssh -G [args...]` and parses its output.
+func runSSHG(args ...string) ([]sshOption, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), sshResolveTimeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "ssh", append([]string{"-G"}, args...)...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("ssh -G: %s", msg)
+		}
+		return nil, fmt.Errorf("ssh -G: %w", err)
+	}
+	return parseSSHGOutput(out), nil
+}
+
+// parseSSHGOutput parses "keyword value" lines, keeping the order of first appearance.
+func parseSSHGOutput(out []byte) []sshOption {
+	options := make([]sshOption, 0, 96)
+	index := make(map[string]int, 96)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		if key == "" {
+			continue
+		}
+		key = strings.ToLower(key)
+		if i, ok := index[key]; ok {
+			options[i].values = append(options[i].values, value)
+			continue
+		}
+		index[key] = len(options)
+		options = append(options, sshOption{key: key, values: []string{value}})
+	}
+	return options
+}
+
+// lookupOption returns the first value of a keyword.
+func lookupOption(options []sshOption, key string) (string, bool) {
+	for _, o := range options {
+		if o.key == key && len(o.values) > 0 {
+			return o.values[0], true
+		}
+	}
+	return "", false
+}
+
+// optionValues returns every value of a keyword, such as the IdentityFiles.
+func optionValues(options []sshOption, key string) []string {
+	for _, o := range options {
+		if o.key == key {
+			return o.values
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestParseSSHGOutput(t *testing.T) {
+	out := []byte("user deploy\nhostname 10.0.0.1\nidentityfile ~/.ssh/a\nidentityfile ~/.ssh/b\nsendenv LANG\n\n")
+	got := parseSSHGOutput(out)
+	want := []sshOption{
+		{key: "user", values: []string{"deploy"}},
+		{key: "hostname", values: []string{"10.0.0.1"}},
+		{key: "identityfile", values: []string{"~/.ssh/a", "~/.ssh/b"}},
+		{key: "sendenv", values: []string{"LANG"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseSSHGOutput() = %+v, want %+v", got, want)
+	}
+	if v, ok := lookupOption(got, "hostname"); !ok || v != "10.0.0.1" {
+		t.Errorf("lookupOption(hostname) = %q, %v", v, ok)
+	}
+}
+
+func TestCompareOptions(t *testing.T) {
+	server := domain.Server{Alias: "web", SourceFile: "/c"}
+	early := domain.ConfigBlock{Kind: domain.BlockHost, Patterns: []string{"w*"}, SourceFile: "/c",
+		Settings: []domain.ConfigSetting{{Key: "User", Value: "admin"}}}
+	own := domain.ConfigBlock{Kind: domain.BlockHost, Patterns: []string{"web"}, SourceFile: "/c",
+		Settings: []domain.ConfigSetting{
+			{Key: "User", Value: "deploy"},
+			{Key: "HostName", Value: "10.0.0.1"},
+			{Key: "ControlPersist", Value: "10m"},
+		}}
+	all := domain.ConfigBlock{Kind: domain.BlockHost, Patterns: []string{"*"}, SourceFile: "/c",
+		Settings: []domain.ConfigSetting{{Key: "ServerAliveInterval", Value: "30"}}}
+	contributions := []domain.BlockContribution{
+		{Block: early, State: domain.MatchYes},
+		{Block: own, State: domain.MatchYes},
+		{Block: all, State: domain.MatchYes},
+	}
+	resolved := []sshOption{
+		{key: "user", values: []string{"admin"}},
+		{key: "hostname", values: []string{"10.0.0.1"}},
+		{key: "controlpersist", values: []string{"600"}},
+		{key: "serveraliveinterval", values: []string{"30"}},
+		{key: "port", values: []string{"22"}},
+		{key: "hashknownhosts", values: []string{"yes"}},
+	}
+	defaults := []sshOption{
+		{key: "port", values: []string{"22"}},
+		{key: "hashknownhosts", values: []string{"no"}},
+	}
+
+	want := map[string]domain.OptionSource{
+		"user":                domain.SourceOverridden,
+		"hostname":            domain.SourceHost,
+		"controlpersist":      domain.SourceHost,
+		"serveraliveinterval": domain.SourceInherited,
+		"port":                domain.SourceDefault,
+		"hashknownhosts":      domain.SourceInherited,
+	}
+	for _, o := range compareOptions(server, resolved, defaults, contributions) {
+		if o.Source != want[o.Key] {
+			t.Errorf("%s: source = %v, want %v", o.Key, o.Source, want[o.Key])
+		}
+		if o.Key == "user" && (o.Literal != "deploy" || o.From != "Host w* (c)") {
+			t.Errorf("user: literal=%q from=%q", o.Literal, o.From)
+		}
+	}
+}
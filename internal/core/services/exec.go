@@ -0,0 +1,216 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+const (
+	defaultExecConcurrency = 10
+	// execWaitDelay bounds how long a killed ssh may keep its output pipes open
+	// (e.g. through a ProxyCommand child).
+	execWaitDelay = 2 * time.Second
+	execMaxLine   = 1 << 20
+)
+
+// Exec runs req.Command on the selected servers with `ssh <alias> <command>`, at
+// most req.Concurrency at a time, and returns the results in server order. Output
+// is captured rather than attached to the terminal, so ssh runs in batch mode and
+// cannot prompt. onEvent receives progress one event at a time; canceling ctx
+// kills the running commands.
+func (s *serverService) Exec(ctx context.Context, req domain.ExecRequest, onEvent func(domain.ExecEvent)) ([]domain.ExecResult, error) {
+	if strings.TrimSpace(req.Command) == "" {
+		return nil, errors.New("command is required")
+	}
+	servers, err := s.listServers("")
+	if err != nil {
+		return nil, err
+	}
+	aliases, err := selectExecTargets(servers, req)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Infow("exec start", "servers", len(aliases), "command", req.Command, "concurrency", req.Concurrency, "timeout", req.Timeout)
+	results := s.runExec(ctx, aliases, req, onEvent)
+
+	counts := map[domain.ExecState]int{}
+	for _, r := range results {
+		counts[r.State]++
+	}
+	s.logger.Infow("exec end", "ok", counts[domain.ExecSucceeded], "failed", len(results)-counts[domain.ExecSucceeded])
+	return results, nil
+}
+
+// selectExecTargets returns the aliases of the servers listed in req or tagged
+// with one of its tags, in list order.
+func selectExecTargets(servers []domain.Server, req domain.ExecRequest) ([]string, error) {
+	found := make(map[string]bool, len(req.Aliases))
+	var out []string
+	for _, srv := range servers {
+		listed := false
+		for _, a := range append([]string{srv.Alias}, srv.Aliases...) {
+			if slices.Contains(req.Aliases, a) {
+				found[a] = true
+				listed = true
+			}
+		}
+		if listed || (len(req.Tags) > 0 && hasAnyTag(srv, req.Tags)) {
+			out = append(out, srv.Alias)
+		}
+	}
+	for _, a := range req.Aliases {
+		if !found[a] {
+			return nil, fmt.Errorf("server with alias '%s' not found", a)
+		}
+	}
+	if len(out) == 0 {
+		return nil, errors.New("no servers selected: give aliases or tags")
+	}
+	return out, nil
+}
+
+func (s *serverService) runExec(ctx context.Context, aliases []string, req domain.ExecRequest, onEvent func(domain.ExecEvent)) []domain.ExecResult {
+	var mu sync.Mutex
+	emit := func(e domain.ExecEvent) {
+		if onEvent == nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		onEvent(e)
+	}
+
+	results := make([]domain.ExecResult, len(aliases))
+	for i, alias := range aliases {
+		results[i] = domain.ExecResult{Alias: alias, State: domain.ExecPending, ExitCode: -1}
+		emit(domain.ExecEvent{Kind: domain.ExecQueued, Alias: alias, Result: results[i]})
+	}
+	concurrency := req.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultExecConcurrency
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, alias := range aliases {
+		select {
+		case <-ctx.Done():
+		case sem <- struct{}{}:
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				results[i] = s.execOne(ctx, alias, req, emit)
+			}()
+			continue
+		}
+		break
+	}
+	wg.Wait()
+
+	for i := range results {
+		if results[i].State == domain.ExecPending {
+			results[i].State = domain.ExecCanceled
+			emit(domain.ExecEvent{Kind: domain.ExecFinished, Alias: results[i].Alias, Result: results[i]})
+		}
+	}
+	return results
+}
+
+func (s *serverService) execOne(ctx context.Context, alias string, req domain.ExecRequest, emit func(domain.ExecEvent)) domain.ExecResult {
+	emit(domain.ExecEvent{Kind: domain.ExecStarted, Alias: alias})
+	runCtx, cancel := ctx, context.CancelFunc(func() {})
+	if req.Timeout > 0 {
+		runCtx, cancel = context.WithTimeout(ctx, req.Timeout)
+	}
+	defer cancel()
+
+	res := domain.ExecResult{Alias: alias, ExitCode: -1}
+	start := time.Now()
+	// #nosec G204 -- the command is what the user asked to run on their servers
+	cmd := exec.CommandContext(runCtx, "ssh", s.sshArgs("-n", "-T", "-o", "BatchMode=yes", alias, req.Command)...)
+	cmd.WaitDelay = execWaitDelay
+	stdout, err := cmd.StdoutPipe()
+	if err == nil {
+		var stderr io.ReadCloser
+		if stderr, err = cmd.StderrPipe(); err == nil {
+			err = s.waitExec(cmd, alias, stdout, stderr, emit, &res)
+		}
+	}
+	res.Duration = time.Since(start)
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		res.State, res.ExitCode = domain.ExecSucceeded, 0
+	case errors.Is(runCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil:
+		res.State, res.Error = domain.ExecTimedOut, fmt.Sprintf("no result after %s", req.Timeout)
+	case ctx.Err() != nil:
+		res.State, res.Error = domain.ExecCanceled, "canceled"
+	case errors.As(err, &exitErr) && exitErr.ExitCode() != 255:
+		// The command's own stderr is in the output; Error is for ssh failures.
+		res.State, res.ExitCode, res.Error = domain.ExecFailed, exitErr.ExitCode(), ""
+	default:
+		// ssh exits with 255 when it cannot connect or authenticate.
+		res.State = domain.ExecError
+		if errors.As(err, &exitErr) {
+			res.ExitCode = exitErr.ExitCode()
+		}
+		if res.Error == "" {
+			res.Error = err.Error()
+		}
+	}
+	emit(domain.ExecEvent{Kind: domain.ExecFinished, Alias: alias, Result: res})
+	return res
+}
+
+// waitExec runs cmd, streaming its output line by line, and keeps the last
+// stderr line in res.Error.
+func (s *serverService) waitExec(cmd *exec.Cmd, alias string, stdout, stderr io.Reader, emit func(domain.ExecEvent), res *domain.ExecResult) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	var wg sync.WaitGroup
+	stream := func(r io.Reader, isStderr bool) {
+		defer wg.Done()
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), execMaxLine)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if isStderr && strings.TrimSpace(line) != "" {
+				res.Error = strings.TrimSpace(line)
+			}
+			emit(domain.ExecEvent{Kind: domain.ExecOutput, Alias: alias, Line: line, Stderr: isStderr})
+		}
+		// Keep draining after an overlong line so the command does not block.
+		_, _ = io.Copy(io.Discard, r)
+	}
+	wg.Add(2)
+	go stream(stdout, false)
+	go stream(stderr, true)
+	wg.Wait()
+	return cmd.Wait()
+}
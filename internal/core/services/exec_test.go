@@ -0,0 +1,132 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"go.uber.org/zap"
+)
+
+func TestSelectExecTargets(t *testing.T) {
+	servers := []domain.Server{
+		{Alias: "db", Tags: []string{"prod"}},
+		{Alias: "web", Aliases: []string{"web", "www"}, Tags: []string{"Prod", "web"}},
+		{Alias: "dev"},
+	}
+	tests := []struct {
+		name    string
+		req     domain.ExecRequest
+		want    string
+		wantErr bool
+	}{
+		{"by tag", domain.ExecRequest{Tags: []string{"prod"}}, "db,web", false},
+		{"by alias", domain.ExecRequest{Aliases: []string{"dev", "www"}}, "web,dev", false},
+		{"alias and tag", domain.ExecRequest{Aliases: []string{"dev"}, Tags: []string{"web"}}, "web,dev", false},
+		{"unknown alias", domain.ExecRequest{Aliases: []string{"nope"}}, "", true},
+		{"nothing", domain.ExecRequest{Tags: []string{"staging"}}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := selectExecTargets(servers, tt.req)
+			if (err != nil) != tt.wantErr || strings.Join(got, ",") != tt.want {
+				t.Errorf("selectExecTargets() = %v, %v; want %q, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunExec(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as fake ssh")
+	}
+	// The fake ssh acts on the alias, which follows the options.
+	dir := t.TempDir()
+	script := `#!/bin/sh
+while [ "$1" != "${1#-}" ]; do
+	[ "$1" = "-o" ] && shift
+	shift
+done
+case "$1" in
+ok) echo "up 3 days"; echo "load 0.1" ;;
+fail) echo "unit not found" >&2; exit 3 ;;
+down) echo "ssh: connect to host down port 22: Connection refused" >&2; exit 255 ;;
+slow) exec sleep 30 ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(dir, "ssh"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	s := &serverService{logger: zap.NewNop().Sugar()}
+	var mu sync.Mutex
+	output := map[string][]string{}
+	finished := 0
+	onEvent := func(e domain.ExecEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch e.Kind {
+		case domain.ExecOutput:
+			output[e.Alias] = append(output[e.Alias], e.Line)
+		case domain.ExecFinished:
+			finished++
+		case domain.ExecQueued, domain.ExecStarted:
+		}
+	}
+	req := domain.ExecRequest{Command: "uptime", Concurrency: 2, Timeout: 500 * time.Millisecond}
+	results := s.runExec(context.Background(), []string{"ok", "fail", "down", "slow"}, req, onEvent)
+
+	want := []struct {
+		state domain.ExecState
+		code  int
+	}{
+		{domain.ExecSucceeded, 0},
+		{domain.ExecFailed, 3},
+		{domain.ExecError, 255},
+		{domain.ExecTimedOut, -1},
+	}
+	for i, w := range want {
+		if r := results[i]; r.State != w.state || r.ExitCode != w.code {
+			t.Errorf("%s: state %s, exit %d; want %s, %d", r.Alias, r.State, r.ExitCode, w.state, w.code)
+		}
+	}
+	if got := strings.Join(output["ok"], "|"); got != "up 3 days|load 0.1" {
+		t.Errorf("output of ok = %q", got)
+	}
+	if !strings.Contains(results[2].Error, "Connection refused") {
+		t.Errorf("ssh error = %q", results[2].Error)
+	}
+	if finished != 4 {
+		t.Errorf("got %d finished events, want 4", finished)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results = s.runExec(ctx, []string{"ok", "slow"}, req, nil)
+	for _, r := range results {
+		if r.State != domain.ExecCanceled {
+			t.Errorf("%s after cancel: %s", r.Alias, r.State)
+		}
+	}
+}
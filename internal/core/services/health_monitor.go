@@ -0,0 +1,239 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+const (
+	defaultMonitorInterval    = time.Minute
+	defaultMonitorConcurrency = 8
+	defaultMonitorHistory     = 30
+)
+
+// MonitorConfig configures the background health monitor.
+type MonitorConfig struct {
+	// Interval between two probe rounds; zero disables the monitor.
+	Interval time.Duration
+	// Concurrency bounds the servers probed at the same time.
+	Concurrency int
+	// Tags limits the monitor to servers with one of these tags; empty means all.
+	Tags []string
+	// History is the number of samples kept per server.
+	History int
+	// Banner probes with ProbeServer (banner and host key) instead of a TCP connect.
+	Banner bool
+}
+
+// DefaultMonitorConfig returns the monitor settings used when nothing overrides them.
+func DefaultMonitorConfig() MonitorConfig {
+	return MonitorConfig{
+		Interval:    defaultMonitorInterval,
+		Concurrency: defaultMonitorConcurrency,
+		History:     defaultMonitorHistory,
+	}
+}
+
+// WithMonitor configures the health monitor started by StartMonitor.
+func WithMonitor(cfg MonitorConfig) Option {
+	return func(s *serverService) {
+		if cfg.Concurrency <= 0 {
+			cfg.Concurrency = defaultMonitorConcurrency
+		}
+		if cfg.History <= 0 {
+			cfg.History = defaultMonitorHistory
+		}
+		s.health.cfg = cfg
+	}
+}
+
+// healthMonitor keeps the probe history of the servers, fed by Ping.
+type healthMonitor struct {
+	cfg MonitorConfig
+
+	mu      sync.Mutex
+	history map[string][]domain.HealthSample
+	stop    chan struct{}
+}
+
+func newHealthMonitor() *healthMonitor {
+	return &healthMonitor{
+		cfg:     DefaultMonitorConfig(),
+		history: make(map[string][]domain.HealthSample),
+	}
+}
+
+// StartMonitor probes the monitored servers now and then every interval,
+// calling onRound after each round. It does nothing when the monitor is
+// disabled or already running.
+func (s *serverService) StartMonitor(onRound func()) {
+	h := s.health
+	h.mu.Lock()
+	if h.stop != nil || h.cfg.Interval <= 0 {
+		h.mu.Unlock()
+		return
+	}
+	stop := make(chan struct{})
+	h.stop = stop
+	h.mu.Unlock()
+
+	s.logger.Infow("health monitor start", "interval", h.cfg.Interval, "concurrency", h.cfg.Concurrency, "tags", h.cfg.Tags)
+	go func() {
+		ticker := time.NewTicker(h.cfg.Interval)
+		defer ticker.Stop()
+		for {
+			s.probeServers(stop)
+			if onRound != nil {
+				onRound()
+			}
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// StopMonitor stops the background probes; the history is kept.
+func (s *serverService) StopMonitor() {
+	h := s.health
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.stop != nil {
+		close(h.stop)
+		h.stop = nil
+		s.logger.Infow("health monitor stop")
+	}
+}
+
+// ServerHealth returns the probe history of every server probed so far.
+func (s *serverService) ServerHealth() map[string]domain.Health {
+	h := s.health
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	out := make(map[string]domain.Health, len(h.history))
+	for alias, samples := range h.history {
+		out[alias] = domain.Health{Samples: slices.Clone(samples)}
+	}
+	return out
+}
+
+// probeServers pings or probes the monitored servers with bounded concurrency.
+func (s *serverService) probeServers(stop chan struct{}) {
+	servers, err := s.serverRepository.ListServers("")
+	if err != nil {
+		s.logger.Warnw("health monitor: failed to list servers", "error", err)
+		return
+	}
+	sem := make(chan struct{}, s.health.cfg.Concurrency)
+	var wg sync.WaitGroup
+	for _, srv := range servers {
+		if !monitored(srv, s.health.cfg.Tags) {
+			continue
+		}
+		// A TCP connect goes straight to HostName:Port and cannot reach a server
+		// behind a jump host; the banner probe goes through ssh instead.
+		if !s.health.cfg.Banner && proxied(srv) {
+			continue
+		}
+		select {
+		case <-stop:
+			wg.Wait()
+			return
+		case sem <- struct{}{}:
+		}
+		wg.Add(1)
+		go func(srv domain.Server) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if s.health.cfg.Banner {
+				_ = s.ProbeServer(srv, false)
+				return
+			}
+			_, _, _ = s.Ping(srv)
+		}(srv)
+	}
+	wg.Wait()
+}
+
+func monitored(srv domain.Server, tags []string) bool {
+	return len(tags) == 0 || hasAnyTag(srv, tags)
+}
+
+// proxied reports whether the server's own block routes it through a jump host
+// or a proxy command.
+func proxied(srv domain.Server) bool {
+	for _, v := range []string{srv.ProxyJump, srv.ProxyCommand} {
+		if v != "" && !strings.EqualFold(v, "none") {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAnyTag reports whether the server has one of the tags, ignoring case.
+func hasAnyTag(srv domain.Server, tags []string) bool {
+	for _, t := range srv.Tags {
+		if slices.ContainsFunc(tags, func(want string) bool { return strings.EqualFold(want, t) }) {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *healthMonitor) record(alias string, sample domain.HealthSample) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	samples := append(h.history[alias], sample)
+	if extra := len(samples) - h.cfg.History; extra > 0 {
+		samples = slices.Delete(samples, 0, extra)
+	}
+	h.history[alias] = samples
+}
+
+// attach copies the probe history into the servers.
+func (h *healthMonitor) attach(servers []domain.Server) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i := range servers {
+		if samples, ok := h.history[servers[i].Alias]; ok {
+			servers[i].Health = domain.Health{Samples: slices.Clone(samples)}
+		}
+	}
+}
+
+// splitHealthFilter extracts an "is:up", "is:down" or "is:unknown" term from a
+// search query and returns the rest of the query.
+func splitHealthFilter(query string) (string, domain.Reachability, bool) {
+	var rest []string
+	state, found := domain.ReachUnknown, false
+	for _, f := range strings.Fields(query) {
+		if v, ok := strings.CutPrefix(strings.ToLower(f), "is:"); ok {
+			if r, ok := domain.ParseReachability(v); ok {
+				state, found = r, true
+				continue
+			}
+		}
+		rest = append(rest, f)
+	}
+	return strings.Join(rest, " "), state, found
+}
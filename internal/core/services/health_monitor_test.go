@@ -0,0 +1,117 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestSplitHealthFilter(t *testing.T) {
+	tests := []struct {
+		query     string
+		wantRest  string
+		wantState domain.Reachability
+		wantOK    bool
+	}{
+		{"web", "web", domain.ReachUnknown, false},
+		{"is:down", "", domain.ReachDown, true},
+		{"prod IS:UP", "prod", domain.ReachUp, true},
+		{"is:unknown db", "db", domain.ReachUnknown, true},
+		{"is:sideways", "is:sideways", domain.ReachUnknown, false},
+	}
+	for _, tt := range tests {
+		rest, state, ok := splitHealthFilter(tt.query)
+		if rest != tt.wantRest || state != tt.wantState || ok != tt.wantOK {
+			t.Errorf("splitHealthFilter(%q) = %q, %v, %v; want %q, %v, %v",
+				tt.query, rest, state, ok, tt.wantRest, tt.wantState, tt.wantOK)
+		}
+	}
+}
+
+func TestHealthHistory(t *testing.T) {
+	h := newHealthMonitor()
+	h.cfg.History = 3
+	for i := range 5 {
+		h.record("web", domain.HealthSample{Up: i != 4, Latency: time.Duration(i) * time.Millisecond})
+	}
+
+	servers := []domain.Server{{Alias: "web"}, {Alias: "db"}}
+	h.attach(servers)
+	web := servers[0].Health
+	if len(web.Samples) != 3 || web.Samples[0].Latency != 2*time.Millisecond {
+		t.Errorf("history = %+v, want the last 3 samples", web.Samples)
+	}
+	if web.State() != domain.ReachDown {
+		t.Errorf("State() = %v, want down", web.State())
+	}
+	if up, total := web.Availability(); up != 2 || total != 3 {
+		t.Errorf("Availability() = %d/%d, want 2/3", up, total)
+	}
+	if avg := web.AverageLatency(); avg != 2500*time.Microsecond {
+		t.Errorf("AverageLatency() = %v", avg)
+	}
+	if servers[1].Health.State() != domain.ReachUnknown {
+		t.Errorf("db should be unknown, got %v", servers[1].Health.State())
+	}
+
+	if !monitored(domain.Server{Tags: []string{"Prod"}}, []string{"prod"}) || monitored(domain.Server{}, []string{"prod"}) {
+		t.Error("monitored() does not filter by tag")
+	}
+	if !proxied(domain.Server{ProxyJump: "bastion"}) || proxied(domain.Server{ProxyCommand: "none"}) || proxied(domain.Server{}) {
+		t.Error("proxied() does not detect jump hosts")
+	}
+}
+
+func TestPingRecordsHealth(t *testing.T) {
+	if _, err := exec.LookPath("ssh"); err != nil {
+		t.Skip("ssh not installed")
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	cfg := filepath.Join(t.TempDir(), "config")
+	content := fmt.Sprintf("Host up\n  HostName 127.0.0.1\n  Port %d\n\nHost down\n  HostName 127.0.0.1\n  Port 1\n", port)
+	if err := os.WriteFile(cfg, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s := &serverService{sshConfigFile: cfg, pingTimeout: time.Second, health: newHealthMonitor()}
+
+	if up, _, err := s.Ping(domain.Server{Alias: "up"}); !up || err != nil {
+		t.Fatalf("Ping(up) = %v, %v", up, err)
+	}
+	if up, _, _ := s.Ping(domain.Server{Alias: "down"}); up {
+		t.Fatal("Ping(down) succeeded")
+	}
+
+	health := s.ServerHealth()
+	if health["up"].State() != domain.ReachUp || health["down"].State() != domain.ReachDown {
+		t.Errorf("ServerHealth() = %+v", health)
+	}
+	if last, _ := health["down"].Last(); last.Error == "" {
+		t.Error("failed probe has no error")
+	}
+}
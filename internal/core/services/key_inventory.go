@@ -0,0 +1,245 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/pem"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+// defaultKeyNames are the identities ssh tries when a host has no IdentityFile.
+var defaultKeyNames = map[string]bool{
+	"id_rsa":        true,
+	"id_ecdsa":      true,
+	"id_ecdsa_sk":   true,
+	"id_ed25519":    true,
+	"id_ed25519_sk": true,
+	"id_dsa":        true,
+}
+
+// maxKeyFileSize bounds how much of a file is read to tell whether it is a private key.
+const maxKeyFileSize = 64 * 1024
+
+// ListKeys returns the private keys in ~/.ssh plus every IdentityFile the
+// servers reference, with their fingerprint, agent state and the servers using
+// them. A server uses the IdentityFiles of every block that applies to it, so
+// keys set in Host * or a wildcard profile count as used.
+func (s *serverService) ListKeys() ([]domain.SSHKey, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	sshDir := filepath.Join(home, ".ssh")
+
+	keys := make(map[string]*domain.SSHKey)
+	entries, err := os.ReadDir(sshDir)
+	if err != nil && !os.IsNotExist(err) {
+		s.logger.Errorw("failed to read ssh directory", "dir", sshDir, "error", err)
+		return nil, err
+	}
+	for _, e := range entries {
+		path := filepath.Join(sshDir, e.Name())
+		if e.IsDir() || strings.HasSuffix(e.Name(), ".pub") || !isPrivateKeyFile(path) {
+			continue
+		}
+		keys[path] = &domain.SSHKey{Path: path}
+	}
+
+	servers, err := s.serverRepository.ListServers("")
+	if err != nil {
+		s.logger.Errorw("failed to list servers", "error", err)
+		return nil, err
+	}
+	// Without the blocks (the error is logged) only the servers' own IdentityFiles count.
+	blocks, _ := s.ListConfigBlocks()
+	localUser := currentUsername()
+	for _, srv := range servers {
+		for _, idf := range effectiveIdentityFiles(srv, blocks, localUser) {
+			path, ok := resolveIdentityPath(idf, home, localUser)
+			if !ok {
+				continue
+			}
+			k, seen := keys[path]
+			if !seen {
+				k = &domain.SSHKey{Path: path}
+				keys[path] = k
+			}
+			if !slices.Contains(k.Servers, srv.Alias) {
+				k.Servers = append(k.Servers, srv.Alias)
+			}
+		}
+	}
+
+	inAgent := make(map[string]bool)
+	if agentKeys, err := s.ListAgentKeys(); err == nil {
+		for _, k := range agentKeys {
+			inAgent[k.Fingerprint] = true
+		}
+	}
+	out := make([]domain.SSHKey, 0, len(keys))
+	for _, k := range keys {
+		inspectKey(k)
+		k.Default = filepath.Dir(k.Path) == sshDir && defaultKeyNames[filepath.Base(k.Path)]
+		k.InAgent = k.Fingerprint != "" && inAgent[k.Fingerprint]
+		out = append(out, *k)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out, nil
+}
+
+// effectiveIdentityFiles returns the server's IdentityFiles followed by those of
+// the other blocks that apply or may apply to it. ssh tries all of them, since
+// IdentityFile adds to the list instead of being set once.
+func effectiveIdentityFiles(srv domain.Server, blocks []domain.ConfigBlock, localUser string) []string {
+	files := slices.Clone(srv.IdentityFiles)
+	for _, b := range blocks {
+		if state, _ := evaluateBlock(b, srv, localUser); state == domain.MatchNo {
+			continue
+		}
+		for _, st := range b.Settings {
+			if strings.EqualFold(st.Key, "IdentityFile") && !slices.Contains(files, st.Value) {
+				files = append(files, st.Value)
+			}
+		}
+	}
+	return files
+}
+
+// inspectKey fills in what can be learned from the key files without a passphrase.
+func inspectKey(k *domain.SSHKey) {
+	data, err := readSmallFile(k.Path)
+	if err != nil {
+		k.Missing = os.IsNotExist(err)
+		return
+	}
+	k.Encrypted = privateKeyEncrypted(data)
+
+	source := k.Path
+	if _, err := os.Stat(k.Path + ".pub"); err == nil {
+		k.HasPublicKey = true
+		source = k.Path + ".pub"
+	} else if k.Encrypted && !bytes.Contains(data, []byte("OPENSSH PRIVATE KEY")) {
+		// ssh-keygen would ask for the passphrase of a PEM key.
+		return
+	}
+	// #nosec G204 -- source is a local key file path
+	out, err := exec.Command("ssh-keygen", "-l", "-f", source).Output()
+	if err != nil {
+		return
+	}
+	if bits, fp, comment, keyType, ok := parseKeyFingerprint(lastLine(string(out))); ok {
+		k.Bits, k.Fingerprint, k.Comment, k.Type = bits, fp, comment, keyType
+	}
+}
+
+// parseKeyFingerprint parses a line of `ssh-keygen -l` or `ssh-add -l`:
+// "256 SHA256:abc… user@host (ED25519)".
+func parseKeyFingerprint(line string) (bits int, fingerprint, comment, keyType string, ok bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 {
+		return 0, "", "", "", false
+	}
+	bits, err := strconv.Atoi(fields[0])
+	if err != nil || !strings.Contains(fields[1], ":") {
+		return 0, "", "", "", false
+	}
+	last := fields[len(fields)-1]
+	if !strings.HasPrefix(last, "(") || !strings.HasSuffix(last, ")") {
+		return 0, "", "", "", false
+	}
+	comment = strings.Join(fields[2:len(fields)-1], " ")
+	if comment == "no comment" {
+		comment = ""
+	}
+	return bits, fields[1], comment, strings.Trim(last, "()"), true
+}
+
+// privateKeyEncrypted tells whether a PEM or OpenSSH private key needs a passphrase.
+func privateKeyEncrypted(data []byte) bool {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return false
+	}
+	switch block.Type {
+	case "OPENSSH PRIVATE KEY":
+		// "openssh-key-v1\0" followed by the length-prefixed cipher name.
+		const magic = "openssh-key-v1\x00"
+		b := block.Bytes
+		if !bytes.HasPrefix(b, []byte(magic)) || len(b) < len(magic)+4 {
+			return false
+		}
+		b = b[len(magic):]
+		n := binary.BigEndian.Uint32(b)
+		if uint64(n) > uint64(len(b)-4) {
+			return false
+		}
+		return string(b[4:4+n]) != "none"
+	case "ENCRYPTED PRIVATE KEY":
+		return true
+	default:
+		return strings.Contains(block.Headers["Proc-Type"], "ENCRYPTED")
+	}
+}
+
+func isPrivateKeyFile(path string) bool {
+	data, err := readSmallFile(path)
+	if err != nil {
+		return false
+	}
+	return bytes.Contains(data, []byte("-----BEGIN ")) && bytes.Contains(data, []byte("PRIVATE KEY-----"))
+}
+
+func readSmallFile(path string) ([]byte, error) {
+	// #nosec G304 -- reading the user's own key files
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	return io.ReadAll(io.LimitReader(f, maxKeyFileSize))
+}
+
+// resolveIdentityPath turns an IdentityFile value into an absolute path. Values
+// using tokens that depend on the connection (e.g. %h) cannot be resolved.
+func resolveIdentityPath(value, home, localUser string) (string, bool) {
+	p := strings.Trim(strings.TrimSpace(value), `"`)
+	if p == "" || strings.EqualFold(p, "none") {
+		return "", false
+	}
+	p = strings.NewReplacer("%d", home, "%u", localUser, "%%", "\x00").Replace(p)
+	if strings.Contains(p, "%") || strings.Contains(p, "${") {
+		return "", false
+	}
+	p = strings.ReplaceAll(p, "\x00", "%")
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		p = filepath.Join(home, p[1:])
+	}
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return "", false
+	}
+	return abs, true
+}
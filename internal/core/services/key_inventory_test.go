@@ -0,0 +1,113 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"encoding/binary"
+	"encoding/pem"
+	"reflect"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestParseKeyFingerprint(t *testing.T) {
+	tests := []struct {
+		line    string
+		ok      bool
+		bits    int
+		fp      string
+		comment string
+		keyType string
+	}{
+		{"256 SHA256:ropia7vJpQqYRL6paqUAQ164uPRuvQKCHjl5357dg6Y root@vm (ECDSA)", true, 256, "SHA256:ropia7vJpQqYRL6paqUAQ164uPRuvQKCHjl5357dg6Y", "root@vm", "ECDSA"},
+		{"4096 SHA256:abc work laptop key (RSA)", true, 4096, "SHA256:abc", "work laptop key", "RSA"},
+		{"256 SHA256:abc no comment (ED25519)", true, 256, "SHA256:abc", "", "ED25519"},
+		{"The agent has no identities.", false, 0, "", "", ""},
+		{"", false, 0, "", "", ""},
+	}
+	for _, tt := range tests {
+		bits, fp, comment, keyType, ok := parseKeyFingerprint(tt.line)
+		if ok != tt.ok || bits != tt.bits || fp != tt.fp || comment != tt.comment || keyType != tt.keyType {
+			t.Errorf("parseKeyFingerprint(%q) = %d, %q, %q, %q, %t", tt.line, bits, fp, comment, keyType, ok)
+		}
+	}
+}
+
+func TestPrivateKeyEncrypted(t *testing.T) {
+	openssh := func(cipher string) []byte {
+		b := []byte("openssh-key-v1\x00")
+		b = binary.BigEndian.AppendUint32(b, uint32(len(cipher)))
+		b = append(b, cipher...)
+		return pem.EncodeToMemory(&pem.Block{Type: "OPENSSH PRIVATE KEY", Bytes: b})
+	}
+	tests := []struct {
+		name string
+		data []byte
+		want bool
+	}{
+		{"openssh plain", openssh("none"), false},
+		{"openssh encrypted", openssh("aes256-ctr"), true},
+		{"pem plain", pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: []byte{1}}), false},
+		{"pem encrypted", pem.EncodeToMemory(&pem.Block{
+			Type:    "RSA PRIVATE KEY",
+			Headers: map[string]string{"Proc-Type": "4,ENCRYPTED", "DEK-Info": "AES-128-CBC,00"},
+			Bytes:   []byte{1},
+		}), true},
+		{"pkcs8 encrypted", pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: []byte{1}}), true},
+		{"not pem", []byte("ssh-ed25519 AAAA"), false},
+	}
+	for _, tt := range tests {
+		if got := privateKeyEncrypted(tt.data); got != tt.want {
+			t.Errorf("%s: privateKeyEncrypted() = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEffectiveIdentityFiles(t *testing.T) {
+	blocks := []domain.ConfigBlock{
+		{Kind: domain.BlockHost, Patterns: []string{"web"}, Settings: []domain.ConfigSetting{{Key: "IdentityFile", Value: "~/.ssh/web"}}},
+		{Kind: domain.BlockHost, Patterns: []string{"w*"}, Settings: []domain.ConfigSetting{{Key: "identityfile", Value: "~/.ssh/team"}}},
+		{Kind: domain.BlockHost, Patterns: []string{"db"}, Settings: []domain.ConfigSetting{{Key: "IdentityFile", Value: "~/.ssh/db"}}},
+		{Kind: domain.BlockHost, Patterns: []string{"*"}, Settings: []domain.ConfigSetting{{Key: "IdentityFile", Value: "~/.ssh/default"}}},
+	}
+	srv := domain.Server{Alias: "web", IdentityFiles: []string{"~/.ssh/web"}}
+	got := effectiveIdentityFiles(srv, blocks, "me")
+	want := []string{"~/.ssh/web", "~/.ssh/team", "~/.ssh/default"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("effectiveIdentityFiles() = %q, want %q", got, want)
+	}
+}
+
+func TestResolveIdentityPath(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+		ok    bool
+	}{
+		{"~/.ssh/id_ed25519", "/home/me/.ssh/id_ed25519", true},
+		{`"/keys/my key"`, "/keys/my key", true},
+		{"%d/.ssh/%u_key", "/home/me/.ssh/me_key", true},
+		{"~/.ssh/id_%h", "", false},
+		{"${KEYS}/id_rsa", "", false},
+		{"none", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := resolveIdentityPath(tt.value, "/home/me", "me")
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("resolveIdentityPath(%q) = %q, %t", tt.value, got, ok)
+		}
+	}
+}
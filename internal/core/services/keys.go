@@ -0,0 +1,236 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/creack/pty"
+)
+
+// defaultKeyBits are used when a KeySpec leaves Bits at zero.
+var defaultKeyBits = map[string]int{
+	domain.KeyTypeECDSA: 256,
+	domain.KeyTypeRSA:   4096,
+}
+
+// GenerateKey creates a new keypair with ssh-keygen and returns the path of its
+// public key. An existing key is never overwritten.
+func (s *serverService) GenerateKey(spec domain.KeySpec) (string, error) {
+	spec.Path = expandHome(spec.Path)
+	if err := validateKeySpec(spec); err != nil {
+		return "", err
+	}
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		return "", fmt.Errorf("ssh-keygen not found; install OpenSSH")
+	}
+	for _, p := range []string{spec.Path, spec.Path + ".pub"} {
+		if _, err := os.Stat(p); err == nil {
+			return "", fmt.Errorf("%s already exists", p)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(spec.Path), 0o700); err != nil {
+		return "", fmt.Errorf("failed to create key directory: %w", err)
+	}
+
+	s.logger.Infow("ssh-keygen start", "type", spec.Type, "bits", spec.Bits, "path", spec.Path)
+	out, err := runKeygen(keygenArgs(spec), spec.Passphrase)
+	if err != nil {
+		s.logger.Errorw("ssh-keygen failed", "path", spec.Path, "error", err, "output", out)
+		if msg := lastLine(out); msg != "" {
+			return "", fmt.Errorf("ssh-keygen failed: %s", msg)
+		}
+		return "", fmt.Errorf("ssh-keygen failed: %w", err)
+	}
+	s.logger.Infow("ssh-keygen end", "path", spec.Path)
+	return spec.Path + ".pub", nil
+}
+
+// CopyPublicKey installs a public key given as text (e.g. pasted from another
+// machine) into the server's authorized_keys.
+func (s *serverService) CopyPublicKey(alias, key string) error {
+	key, err := normalizePublicKey(key)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp("", "lazyssh-key-*.pub")
+	if err != nil {
+		return fmt.Errorf("failed to write key: %w", err)
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+	if _, err := f.WriteString(key + "\n"); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write key: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write key: %w", err)
+	}
+	// -f: there is no private key to check whether the key is already installed.
+	return s.runSSHCopyID(alias, "-f", "-i", f.Name())
+}
+
+func validateKeySpec(spec domain.KeySpec) error {
+	if strings.TrimSpace(spec.Path) == "" {
+		return fmt.Errorf("key file is required")
+	}
+	switch spec.Type {
+	case domain.KeyTypeEd25519:
+	case domain.KeyTypeECDSA:
+		if spec.Bits != 0 && spec.Bits != 256 && spec.Bits != 384 && spec.Bits != 521 {
+			return fmt.Errorf("ecdsa keys must be 256, 384 or 521 bits")
+		}
+	case domain.KeyTypeRSA:
+		if spec.Bits != 0 && (spec.Bits < 2048 || spec.Bits > 16384) {
+			return fmt.Errorf("rsa keys must be between 2048 and 16384 bits")
+		}
+	default:
+		return fmt.Errorf("unsupported key type %q", spec.Type)
+	}
+	if strings.ContainsAny(spec.Comment, "\n\r") {
+		return fmt.Errorf("key comment must be a single line")
+	}
+	return nil
+}
+
+// runKeygen runs ssh-keygen with args and returns its output. A passphrase is
+// typed at its prompts on a pseudo-terminal instead of being passed with -N,
+// where other users could read it in the process list.
+func runKeygen(args []string, passphrase string) (string, error) {
+	// #nosec G204 -- arguments are validated by validateKeySpec
+	cmd := exec.Command("ssh-keygen", args...)
+	if passphrase == "" {
+		out, err := cmd.CombinedOutput()
+		return string(out), err
+	}
+	f, err := pty.Start(cmd)
+	if err != nil {
+		return "", fmt.Errorf("a passphrase needs a pseudo-terminal: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	out, ok := answerPassphrasePrompts(f, f, passphrase)
+	if !ok {
+		_ = cmd.Process.Kill()
+	}
+	err = cmd.Wait()
+	if err == nil && !ok {
+		err = fmt.Errorf("unexpected prompt")
+	}
+	return out, err
+}
+
+// keygenPrompts is how many times ssh-keygen asks for a new key's passphrase.
+const keygenPrompts = 2
+
+// answerPassphrasePrompts writes passphrase to w at each passphrase prompt read
+// from r and returns the rest of the output. It reports false when ssh-keygen
+// asks for the passphrase more often than expected, e.g. because the two
+// answers did not match.
+func answerPassphrasePrompts(r io.Reader, w io.Writer, passphrase string) (string, bool) {
+	var out, pending strings.Builder
+	answered := 0
+	buf := make([]byte, 256)
+	for {
+		n, err := r.Read(buf)
+		pending.Write(buf[:n])
+		if text := pending.String(); isPassphrasePrompt(text) {
+			if answered == keygenPrompts {
+				return out.String(), false
+			}
+			if _, err := io.WriteString(w, passphrase+"\n"); err != nil {
+				return out.String(), false
+			}
+			answered++
+			pending.Reset()
+		} else if i := strings.LastIndexByte(text, '\n'); i >= 0 {
+			out.WriteString(text[:i+1])
+			pending.Reset()
+			pending.WriteString(text[i+1:])
+		}
+		if err != nil {
+			// Reading a pseudo-terminal fails with EIO once ssh-keygen exits.
+			out.WriteString(pending.String())
+			return out.String(), true
+		}
+	}
+}
+
+// isPassphrasePrompt tells whether text ends with a passphrase prompt such as
+// "Enter passphrase (empty for no passphrase): ".
+func isPassphrasePrompt(text string) bool {
+	line := text[strings.LastIndexByte(text, '\n')+1:]
+	return strings.Contains(strings.ToLower(line), "passphrase") && strings.HasSuffix(strings.TrimRight(line, " "), ":")
+}
+
+// keygenArgs builds the ssh-keygen command line for spec. An empty passphrase is
+// passed with -N so nothing is asked; otherwise ssh-keygen prompts for it.
+func keygenArgs(spec domain.KeySpec) []string {
+	args := []string{"-q", "-t", spec.Type}
+	if spec.Type != domain.KeyTypeEd25519 {
+		bits := spec.Bits
+		if bits == 0 {
+			bits = defaultKeyBits[spec.Type]
+		}
+		args = append(args, "-b", strconv.Itoa(bits))
+	}
+	if spec.Comment != "" {
+		args = append(args, "-C", spec.Comment)
+	}
+	if spec.Passphrase == "" {
+		args = append(args, "-N", "")
+	}
+	return append(args, "-f", spec.Path)
+}
+
+// normalizePublicKey checks that key is a single OpenSSH public key line
+// ("<type> <base64> [comment]") and returns it trimmed.
+func normalizePublicKey(key string) (string, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return "", fmt.Errorf("public key is empty")
+	}
+	if strings.ContainsAny(key, "\n\r") {
+		return "", fmt.Errorf("paste a single public key line")
+	}
+	fields := strings.Fields(key)
+	if len(fields) < 2 {
+		return "", fmt.Errorf("not an OpenSSH public key")
+	}
+	keyType := fields[0]
+	if !strings.HasPrefix(keyType, "ssh-") && !strings.HasPrefix(keyType, "ecdsa-") && !strings.HasPrefix(keyType, "sk-") {
+		return "", fmt.Errorf("unknown key type %q", keyType)
+	}
+	if _, err := base64.StdEncoding.DecodeString(fields[1]); err != nil {
+		return "", fmt.Errorf("key data is not valid base64")
+	}
+	return key, nil
+}
+
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
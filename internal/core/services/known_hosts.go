@@ -0,0 +1,439 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" // #nosec G505 -- known_hosts hashing is defined as HMAC-SHA1
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+const (
+	keyscanTimeout      = 15 * time.Second
+	knownHostsBackupExt = "lazyssh.backup"
+	defaultKnownHosts   = "~/.ssh/known_hosts ~/.ssh/known_hosts2"
+)
+
+// knownHostsTarget is where and under which name ssh looks up a server's host keys.
+type knownHostsTarget struct {
+	lookup string // name in known_hosts, "[host]:port" for non-default ports
+	host   string
+	port   int
+	files  []string
+	hash   bool
+}
+
+// KnownHosts returns the keys recorded for the server in its known_hosts files
+// without contacting it.
+func (s *serverService) KnownHosts(server domain.Server) (domain.HostKeyCheck, error) {
+	return s.recordedHostKeys(s.knownHostsTarget(server))
+}
+
+func (s *serverService) recordedHostKeys(t knownHostsTarget) (domain.HostKeyCheck, error) {
+	check := domain.HostKeyCheck{Host: t.lookup, Files: t.files}
+	for _, f := range t.files {
+		keys, err := readKnownHosts(f, t.lookup)
+		if err != nil {
+			s.logger.Errorw("failed to read known_hosts", "file", f, "error", err)
+			return check, err
+		}
+		check.Recorded = append(check.Recorded, keys...)
+	}
+	return check, nil
+}
+
+// CheckHostKeys fetches the server's host keys with ssh-keyscan and compares them
+// with the recorded ones.
+func (s *serverService) CheckHostKeys(server domain.Server) (domain.HostKeyCheck, error) {
+	t := s.knownHostsTarget(server)
+	check, err := s.recordedHostKeys(t)
+	if err != nil {
+		return check, err
+	}
+	scanned, err := scanHostKeys(t.host, t.port)
+	if err != nil {
+		s.logger.Warnw("ssh-keyscan failed", "alias", server.Alias, "error", err)
+		return check, err
+	}
+	check.Scanned = scanned
+	check.State = compareHostKeys(check.Recorded, scanned)
+	return check, nil
+}
+
+// RemoveKnownHost deletes the server's entries from its known_hosts files, backing
+// each changed file up first. @cert-authority and @revoked lines are kept.
+func (s *serverService) RemoveKnownHost(server domain.Server) (int, error) {
+	t := s.knownHostsTarget(server)
+	total := 0
+	for _, f := range t.files {
+		n, _, err := removeKnownHostLines(f, t.lookup)
+		if err != nil {
+			s.logger.Errorw("failed to remove known_hosts entries", "file", f, "error", err)
+			return total, err
+		}
+		total += n
+	}
+	s.logger.Infow("removed known_hosts entries", "alias", server.Alias, "host", t.lookup, "count", total)
+	return total, nil
+}
+
+// TrustHostKeys replaces the server's recorded keys with keys (typically from
+// CheckHostKeys). New entries go to the first known_hosts file and are hashed
+// when HashKnownHosts is set or the old entries were hashed.
+func (s *serverService) TrustHostKeys(server domain.Server, keys []domain.HostKey) error {
+	if len(keys) == 0 {
+		return fmt.Errorf("no host keys to record")
+	}
+	t := s.knownHostsTarget(server)
+	if len(t.files) == 0 {
+		return fmt.Errorf("no writable known_hosts file for %s", server.Alias)
+	}
+	hash := t.hash
+	for _, f := range t.files {
+		_, hashed, err := removeKnownHostLines(f, t.lookup)
+		if err != nil {
+			return err
+		}
+		hash = hash || hashed
+	}
+
+	var buf strings.Builder
+	for _, k := range keys {
+		line, err := formatKnownHostLine(t.lookup, k, hash)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(line + "\n")
+	}
+	if err := appendKnownHosts(t.files[0], buf.String()); err != nil {
+		s.logger.Errorw("failed to write known_hosts", "file", t.files[0], "error", err)
+		return err
+	}
+	s.logger.Infow("recorded host keys", "alias", server.Alias, "host", t.lookup, "count", len(keys))
+	return nil
+}
+
+// knownHostsTarget resolves the host name, port and known_hosts files through
+// ssh -G, so values inherited from other blocks are honored.
+func (s *serverService) knownHostsTarget(server domain.Server) knownHostsTarget {
+	t := knownHostsTarget{
+		host: server.Host,
+		port: server.Port,
+		hash: strings.EqualFold(server.HashKnownHosts, "yes"),
+	}
+	if t.host == "" {
+		t.host = server.Alias
+	}
+	files := server.UserKnownHostsFile
+	alias := ""
+	if opts, err := runSSHG(s.sshArgs(server.Alias)...); err == nil {
+		if v, ok := lookupOption(opts, "hostname"); ok {
+			t.host = v
+		}
+		if v, ok := lookupOption(opts, "port"); ok {
+			t.port, _ = strconv.Atoi(v)
+		}
+		if v, ok := lookupOption(opts, "userknownhostsfile"); ok {
+			files = v
+		}
+		if v, ok := lookupOption(opts, "hashknownhosts"); ok {
+			t.hash = v == "yes"
+		}
+		alias, _ = lookupOption(opts, "hostkeyalias")
+	} else {
+		s.logger.Warnw("ssh -G failed; using the Host block values", "alias", server.Alias, "error", err)
+	}
+	if files == "" {
+		files = defaultKnownHosts
+	}
+	home, _ := os.UserHomeDir()
+	localUser := currentUsername()
+	for _, f := range strings.Fields(files) {
+		if p, ok := resolveIdentityPath(f, home, localUser); ok {
+			t.files = append(t.files, p)
+		}
+	}
+	t.lookup = knownHostsName(t.host, t.port, alias)
+	return t
+}
+
+// knownHostsName is the name ssh records a host under.
+func knownHostsName(host string, port int, hostKeyAlias string) string {
+	if hostKeyAlias != "" {
+		host = hostKeyAlias
+	}
+	if port != 0 && port != 22 {
+		return fmt.Sprintf("[%s]:%d", host, port)
+	}
+	return host
+}
+
+// readKnownHosts returns the keys recorded for name in file. A missing file has no keys.
+func readKnownHosts(file, name string) ([]domain.HostKey, error) {
+	data, err := os.ReadFile(file) // #nosec G304 -- user's known_hosts file
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var keys []domain.HostKey
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		marker, hosts, key, ok := parseKnownHostsLine(sc.Text())
+		if !ok {
+			continue
+		}
+		if match, hashed := knownHostMatches(hosts, name); match {
+			key.File, key.Line, key.Hashed, key.Marker = file, line, hashed, marker
+			keys = append(keys, key)
+		}
+	}
+	return keys, sc.Err()
+}
+
+// removeKnownHostLines drops the unmarked entries for name from file and reports
+// how many were removed and whether any of them was hashed.
+func removeKnownHostLines(file, name string) (int, bool, error) {
+	data, err := os.ReadFile(file) // #nosec G304 -- user's known_hosts file
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	kept := make([]string, 0, len(lines))
+	removed, hashedAny := 0, false
+	for _, l := range lines {
+		if marker, hosts, _, ok := parseKnownHostsLine(l); ok && marker == "" {
+			if match, hashed := knownHostMatches(hosts, name); match {
+				removed++
+				hashedAny = hashedAny || hashed
+				continue
+			}
+		}
+		kept = append(kept, l)
+	}
+	if removed == 0 {
+		return 0, false, nil
+	}
+	if err := backupKnownHosts(file, data); err != nil {
+		return 0, false, err
+	}
+	if err := writeFileAtomic(file, []byte(strings.Join(kept, ""))); err != nil {
+		return 0, false, err
+	}
+	return removed, hashedAny, nil
+}
+
+// appendKnownHosts adds lines to file, creating it (and ~/.ssh) when needed.
+func appendKnownHosts(file, lines string) error {
+	data, err := os.ReadFile(file) // #nosec G304 -- user's known_hosts file
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(data) > 0 {
+		if err := backupKnownHosts(file, data); err != nil {
+			return err
+		}
+		if data[len(data)-1] != '\n' {
+			data = append(data, '\n')
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o700); err != nil {
+		return err
+	}
+	return writeFileAtomic(file, append(data, lines...))
+}
+
+// backupKnownHosts saves data beside file as "<name>-lazyssh.backup". There is
+// a single rolling backup, replaced by each change, so known_hosts backups do
+// not pile up in ~/.ssh.
+func backupKnownHosts(file string, data []byte) error {
+	if err := writeFileAtomic(knownHostsBackup(file), data); err != nil {
+		return fmt.Errorf("failed to back up %s: %w", file, err)
+	}
+	return nil
+}
+
+func knownHostsBackup(file string) string {
+	return file + "-" + knownHostsBackupExt
+}
+
+// writeFileAtomic replaces file through a temporary file, keeping its permissions.
+func writeFileAtomic(file string, data []byte) error {
+	mode := os.FileMode(0o600)
+	if info, err := os.Stat(file); err == nil {
+		mode = info.Mode().Perm()
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(file), "."+filepath.Base(file)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+// scanHostKeys asks the server for its host keys with ssh-keyscan. ssh-keyscan
+// connects directly and does not read ssh_config (no ProxyJump).
+func scanHostKeys(host string, port int) ([]domain.HostKey, error) {
+	if _, err := exec.LookPath("ssh-keyscan"); err != nil {
+		return nil, fmt.Errorf("ssh-keyscan not found; install OpenSSH")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), keyscanTimeout)
+	defer cancel()
+	args := []string{"-T", "5"}
+	if port != 0 && port != 22 {
+		args = append(args, "-p", strconv.Itoa(port))
+	}
+	var stderr bytes.Buffer
+	// #nosec G204 -- host and port come from the user's SSH config
+	cmd := exec.CommandContext(ctx, "ssh-keyscan", append(args, "--", host)...)
+	cmd.Stderr = &stderr
+	out, _ := cmd.Output()
+
+	var keys []domain.HostKey
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		if _, _, key, ok := parseKnownHostsLine(sc.Text()); ok {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		msg := ""
+		for _, l := range nonEmptyLines(stderr.String()) {
+			if !strings.HasPrefix(l, "#") {
+				msg = l
+			}
+		}
+		if msg == "" {
+			msg = "no host keys received"
+		}
+		return nil, fmt.Errorf("ssh-keyscan %s: %s", host, msg)
+	}
+	return keys, nil
+}
+
+// compareHostKeys decides whether the server still presents a recorded key.
+func compareHostKeys(recorded, scanned []domain.HostKey) domain.HostKeyState {
+	plain := 0
+	for _, r := range recorded {
+		if r.Marker != "" {
+			continue
+		}
+		plain++
+		for _, k := range scanned {
+			if k.Type == r.Type && k.Key == r.Key {
+				return domain.HostKeyMatch
+			}
+		}
+	}
+	if plain == 0 {
+		return domain.HostKeyUnknown
+	}
+	return domain.HostKeyMismatch
+}
+
+// parseKnownHostsLine parses "[@marker] hosts keytype base64 [comment]".
+func parseKnownHostsLine(line string) (marker, hosts string, key domain.HostKey, ok bool) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+		return "", "", domain.HostKey{}, false
+	}
+	if strings.HasPrefix(fields[0], "@") {
+		marker = fields[0]
+		fields = fields[1:]
+	}
+	if len(fields) < 3 {
+		return "", "", domain.HostKey{}, false
+	}
+	fp, err := hostKeyFingerprint(fields[2])
+	if err != nil {
+		return "", "", domain.HostKey{}, false
+	}
+	return marker, fields[0], domain.HostKey{Type: fields[1], Key: fields[2], Fingerprint: fp}, true
+}
+
+// knownHostMatches applies the known_hosts host field to name: either a hashed
+// "|1|salt|hash" entry or a comma-separated pattern list with negations.
+func knownHostMatches(hosts, name string) (match, hashed bool) {
+	if strings.HasPrefix(hosts, "|1|") {
+		parts := strings.Split(hosts, "|")
+		if len(parts) != 4 {
+			return false, true
+		}
+		salt, err1 := base64.StdEncoding.DecodeString(parts[2])
+		want, err2 := base64.StdEncoding.DecodeString(parts[3])
+		if err1 != nil || err2 != nil {
+			return false, true
+		}
+		mac := hmac.New(sha1.New, salt)
+		mac.Write([]byte(name))
+		return hmac.Equal(mac.Sum(nil), want), true
+	}
+	return hostPatternsMatch(strings.Split(hosts, ","), name), false
+}
+
+// formatKnownHostLine renders a known_hosts entry for name, hashed like ssh-keygen -H when asked.
+func formatKnownHostLine(name string, key domain.HostKey, hash bool) (string, error) {
+	host := name
+	if hash {
+		salt := make([]byte, sha1.Size)
+		if _, err := rand.Read(salt); err != nil {
+			return "", err
+		}
+		mac := hmac.New(sha1.New, salt)
+		mac.Write([]byte(name))
+		host = "|1|" + base64.StdEncoding.EncodeToString(salt) + "|" + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	}
+	return host + " " + key.Type + " " + key.Key, nil
+}
+
+// hostKeyFingerprint computes the SHA256 fingerprint ssh prints for a key blob.
+func hostKeyFingerprint(blob string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(blob)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(raw)
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,158 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+const testHostKey = "AAAAE2VjZHNhLXNoYTItbmlzdHAyNTYAAAAIbmlzdHAyNTYAAABBBFLbf/IraSUDLPCOAqJCjSjNjLLtha6vTQeCW1wH2dHUf21LPzjVA/+KAI+9e6rAgXn/8zwbNlzvvSeasFHXwhM="
+
+func TestHostKeyFingerprint(t *testing.T) {
+	got, err := hostKeyFingerprint(testHostKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// As printed by ssh-keygen -l for the same key.
+	if want := "SHA256:ropia7vJpQqYRL6paqUAQ164uPRuvQKCHjl5357dg6Y"; got != want {
+		t.Errorf("hostKeyFingerprint() = %q, want %q", got, want)
+	}
+}
+
+func TestKnownHostMatches(t *testing.T) {
+	key := domain.HostKey{Type: "ecdsa-sha2-nistp256", Key: testHostKey}
+	hashedLine, err := formatKnownHostLine("[10.0.0.5]:2222", key, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashed := strings.Fields(hashedLine)[0]
+
+	tests := []struct {
+		hosts      string
+		name       string
+		match      bool
+		wantHashed bool
+	}{
+		{"web.example.com,10.0.0.5", "10.0.0.5", true, false},
+		{"*.example.com,!db.example.com", "web.example.com", true, false},
+		{"*.example.com,!db.example.com", "db.example.com", false, false},
+		{"[10.0.0.5]:2222", "[10.0.0.5]:2222", true, false},
+		{"10.0.0.5", "[10.0.0.5]:2222", false, false},
+		{hashed, "[10.0.0.5]:2222", true, true},
+		{hashed, "10.0.0.5", false, true},
+	}
+	for _, tt := range tests {
+		match, h := knownHostMatches(tt.hosts, tt.name)
+		if match != tt.match || h != tt.wantHashed {
+			t.Errorf("knownHostMatches(%q, %q) = %t, %t", tt.hosts, tt.name, match, h)
+		}
+	}
+}
+
+func TestKnownHostsName(t *testing.T) {
+	tests := []struct {
+		host  string
+		port  int
+		alias string
+		want  string
+	}{
+		{"10.0.0.5", 22, "", "10.0.0.5"},
+		{"10.0.0.5", 0, "", "10.0.0.5"},
+		{"10.0.0.5", 2222, "", "[10.0.0.5]:2222"},
+		{"10.0.0.5", 22, "web", "web"},
+	}
+	for _, tt := range tests {
+		if got := knownHostsName(tt.host, tt.port, tt.alias); got != tt.want {
+			t.Errorf("knownHostsName(%q, %d, %q) = %q, want %q", tt.host, tt.port, tt.alias, got, tt.want)
+		}
+	}
+}
+
+func TestCompareHostKeys(t *testing.T) {
+	a := domain.HostKey{Type: "ssh-ed25519", Key: "AAAA"}
+	b := domain.HostKey{Type: "ssh-ed25519", Key: "BBBB"}
+	ca := domain.HostKey{Type: "ssh-ed25519", Key: "CCCC", Marker: "@cert-authority"}
+	tests := []struct {
+		name     string
+		recorded []domain.HostKey
+		scanned  []domain.HostKey
+		want     domain.HostKeyState
+	}{
+		{"nothing recorded", nil, []domain.HostKey{a}, domain.HostKeyUnknown},
+		{"only markers", []domain.HostKey{ca}, []domain.HostKey{a}, domain.HostKeyUnknown},
+		{"match", []domain.HostKey{b, a}, []domain.HostKey{a}, domain.HostKeyMatch},
+		{"rebuilt", []domain.HostKey{a}, []domain.HostKey{b}, domain.HostKeyMismatch},
+	}
+	for _, tt := range tests {
+		if got := compareHostKeys(tt.recorded, tt.scanned); got != tt.want {
+			t.Errorf("%s: compareHostKeys() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRemoveKnownHostLines(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "known_hosts")
+	content := strings.Join([]string{
+		"# managed by hand",
+		"web,10.0.0.5 ecdsa-sha2-nistp256 " + testHostKey,
+		"db ecdsa-sha2-nistp256 " + testHostKey,
+		"@cert-authority 10.0.0.5 ecdsa-sha2-nistp256 " + testHostKey,
+		"10.0.0.5 ssh-rsa " + testHostKey,
+		"",
+	}, "\n")
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, hashed, err := removeKnownHostLines(file, "10.0.0.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 2 || hashed {
+		t.Errorf("removed = %d, hashed = %t; want 2, false", removed, hashed)
+	}
+	data, _ := os.ReadFile(file)
+	want := "# managed by hand\ndb ecdsa-sha2-nistp256 " + testHostKey + "\n@cert-authority 10.0.0.5 ecdsa-sha2-nistp256 " + testHostKey + "\n"
+	if string(data) != want {
+		t.Errorf("known_hosts after removal:\n%s", data)
+	}
+	if info, _ := os.Stat(file); info.Mode().Perm() != 0o644 {
+		t.Errorf("mode = %v, want 0644 kept", info.Mode().Perm())
+	}
+	if b, _ := os.ReadFile(knownHostsBackup(file)); string(b) != content {
+		t.Errorf("backup content differs from the original")
+	}
+	if err := appendKnownHosts(file, "web ssh-ed25519 "+testHostKey+"\n"); err != nil {
+		t.Fatal(err)
+	}
+	backups, _ := filepath.Glob(filepath.Join(filepath.Dir(file), "*lazyssh.backup"))
+	if len(backups) != 1 {
+		t.Fatalf("backups = %v, want a single rolling one", backups)
+	}
+	if b, _ := os.ReadFile(backups[0]); string(b) != want {
+		t.Errorf("backup does not hold the content before the last change:\n%s", b)
+	}
+
+	recorded, err := readKnownHosts(file, "db")
+	if err != nil || len(recorded) != 1 || recorded[0].Line != 2 {
+		t.Errorf("readKnownHosts(db) = %+v, %v", recorded, err)
+	}
+}
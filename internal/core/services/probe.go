@@ -0,0 +1,382 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+const (
+	probeClientBanner = "SSH-2.0-lazyssh_probe"
+	// probeMaxBannerLines bounds the lines a server may send before its version (RFC 4253 4.2).
+	probeMaxBannerLines = 32
+	probeMaxPacket      = 256 * 1024
+	// probeSSHTimeout bounds the `ssh ... true` run, which may go through several jump hosts.
+	probeSSHTimeout = 30 * time.Second
+	sshMsgKexInit   = 20
+)
+
+// ProbeServer checks a server at the SSH level: it reads the server's banner and
+// the host key algorithms of its key exchange, and with auth it also runs
+// `ssh -o BatchMode=yes <alias> true`. Servers behind ProxyJump or ProxyCommand
+// are always probed with ssh, since a direct connection would bypass the proxy.
+// The result is added to the server's health history.
+func (s *serverService) ProbeServer(server domain.Server, auth bool) domain.ProbeResult {
+	start := time.Now()
+	r := domain.ProbeResult{Alias: server.Alias}
+
+	began := time.Now()
+	opts, err := runSSHG(s.sshArgs(server.Alias)...)
+	if err != nil {
+		r.Class, r.Error = domain.ProbeErrConfig, err.Error()
+		return s.finishProbe(r, start)
+	}
+	host, _ := lookupOption(opts, "hostname")
+	port, _ := lookupOption(opts, "port")
+	if host == "" {
+		host = server.Alias
+	}
+	if port == "" {
+		port = "22"
+	}
+	r.Address = net.JoinHostPort(host, port)
+	if v, ok := lookupOption(opts, "proxyjump"); ok && !strings.EqualFold(v, "none") {
+		r.Via = "ProxyJump " + v
+	} else if v, ok := lookupOption(opts, "proxycommand"); ok && !strings.EqualFold(v, "none") {
+		r.Via = "ProxyCommand " + v
+	}
+	algos, _ := lookupOption(opts, "hostkeyalgorithms")
+	probeMark(&r, domain.ProbeResolved, began)
+
+	if r.Via == "" {
+		s.probeDirect(&r, strings.Split(algos, ","))
+	}
+	if r.OK() && (auth || r.Via != "") {
+		s.probeWithSSH(&r, auth)
+	}
+	return s.finishProbe(r, start)
+}
+
+func (s *serverService) finishProbe(r domain.ProbeResult, start time.Time) domain.ProbeResult {
+	r.Total = time.Since(start)
+	sample := domain.HealthSample{At: start, Up: r.Stage >= domain.ProbeBanner, Latency: r.Total}
+	if !r.OK() {
+		sample.Error = fmt.Sprintf("%s: %s", r.Class, r.Error)
+	}
+	s.health.record(r.Alias, sample)
+	s.logger.Infow("probe", "alias", r.Alias, "stage", r.Stage.String(), "class", string(r.Class), "error", r.Error, "total", r.Total)
+	return r
+}
+
+func probeMark(r *domain.ProbeResult, stage domain.ProbeStage, began time.Time) {
+	r.Stage = stage
+	r.Timings = append(r.Timings, domain.ProbeTiming{Stage: stage, Duration: time.Since(began)})
+}
+
+// probeDirect connects to the server, exchanges version strings and reads the
+// server's KEXINIT, without authenticating.
+func (s *serverService) probeDirect(r *domain.ProbeResult, clientHostKeys []string) {
+	began := time.Now()
+	conn, err := net.DialTimeout("tcp", r.Address, s.pingTimeout)
+	if err != nil {
+		r.Class, r.Error = classifyDialError(err), err.Error()
+		return
+	}
+	defer func() { _ = conn.Close() }()
+	probeMark(r, domain.ProbeConnected, began)
+
+	_ = conn.SetDeadline(time.Now().Add(s.pingTimeout))
+	began = time.Now()
+	br := bufio.NewReader(conn)
+	if _, err := io.WriteString(conn, probeClientBanner+"\r\n"); err != nil {
+		r.Class, r.Error = domain.ProbeErrProtocol, err.Error()
+		return
+	}
+	banner, err := readSSHBanner(br)
+	if err != nil {
+		r.Class, r.Error = classifyProtocolError(err), err.Error()
+		return
+	}
+	r.Banner = banner
+	probeMark(r, domain.ProbeBanner, began)
+
+	began = time.Now()
+	hostKeys, err := readKexInitHostKeys(br)
+	if err != nil {
+		r.Class, r.Error = classifyProtocolError(err), err.Error()
+		return
+	}
+	r.ServerHostKeys = hostKeys
+	r.HostKey = negotiateHostKey(clientHostKeys, hostKeys)
+	probeMark(r, domain.ProbeKeyExchange, began)
+}
+
+// probeWithSSH runs ssh verbosely and follows its progress through the debug
+// output. Without auth, ssh only offers the "none" method, so reaching the
+// authentication step counts as success.
+func (s *serverService) probeWithSSH(r *domain.ProbeResult, auth bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), probeSSHTimeout)
+	defer cancel()
+
+	args := []string{
+		"-v", "-T",
+		"-o", "BatchMode=yes",
+		"-o", "ControlMaster=no",
+		"-o", "ControlPath=none",
+		"-o", "ConnectTimeout=" + strconv.Itoa(max(1, int(s.pingTimeout.Seconds()))),
+	}
+	if !auth {
+		args = append(args, "-o", "PreferredAuthentications=none")
+	}
+	// #nosec G204 -- alias comes from the user's SSH config
+	cmd := exec.CommandContext(ctx, "ssh", s.sshArgs(append(args, r.Alias, "true")...)...)
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		r.Class, r.Error = domain.ProbeErrOther, err.Error()
+		return
+	}
+	began := time.Now()
+	if err := cmd.Start(); err != nil {
+		r.Class, r.Error = domain.ProbeErrOther, err.Error()
+		return
+	}
+	last := ""
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if applySSHDebugLine(r, line, began) {
+			began = time.Now()
+		}
+		if isSSHErrorLine(line) {
+			last = line
+		}
+	}
+	err = cmd.Wait()
+
+	switch {
+	case err == nil:
+		if auth {
+			if r.Stage < domain.ProbeAuthenticated {
+				probeMark(r, domain.ProbeAuthenticated, began)
+			}
+			return
+		}
+	case ctx.Err() != nil:
+		r.Class, r.Error = domain.ProbeErrTimeout, fmt.Sprintf("ssh did not finish within %s", probeSSHTimeout)
+		return
+	}
+	class := classifySSHError(last)
+	if !auth && class == domain.ProbeErrAuth && r.Stage >= domain.ProbeKeyExchange {
+		return
+	}
+	if class == domain.ProbeErrOther && r.Via != "" && r.Stage < domain.ProbeBanner {
+		class = domain.ProbeErrProxy
+	}
+	if last == "" && err != nil {
+		last = err.Error()
+	}
+	r.Class, r.Error = class, last
+}
+
+// applySSHDebugLine records the stage an `ssh -v` debug line announces and
+// reports whether it did.
+func applySSHDebugLine(r *domain.ProbeResult, line string, began time.Time) bool {
+	msg, ok := strings.CutPrefix(line, "debug1: ")
+	if !ok {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(msg, "Connection established"):
+		probeMark(r, domain.ProbeConnected, began)
+	case strings.HasPrefix(msg, "Remote protocol version "):
+		// Remote protocol version 2.0, remote software version OpenSSH_9.6p1 Ubuntu-3
+		rest := strings.TrimPrefix(msg, "Remote protocol version ")
+		proto, software, _ := strings.Cut(rest, ", remote software version ")
+		r.Banner = "SSH-" + proto + "-" + software
+		if r.Stage < domain.ProbeConnected {
+			probeMark(r, domain.ProbeConnected, began)
+			began = time.Now()
+		}
+		probeMark(r, domain.ProbeBanner, began)
+	case strings.HasPrefix(msg, "kex: host key algorithm: "):
+		r.HostKey = strings.TrimPrefix(msg, "kex: host key algorithm: ")
+		probeMark(r, domain.ProbeKeyExchange, began)
+	case strings.HasPrefix(msg, "Authentication succeeded"), strings.HasPrefix(msg, "Authenticated to "):
+		if r.Stage < domain.ProbeAuthenticated {
+			probeMark(r, domain.ProbeAuthenticated, began)
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// isSSHErrorLine tells the messages ssh prints on failure from its debug chatter.
+func isSSHErrorLine(line string) bool {
+	if line == "" {
+		return false
+	}
+	for _, prefix := range []string{"debug", "OpenSSH_", "Pseudo-terminal", "Warning: Permanently added", "Transferred:", "Bytes per second"} {
+		if strings.HasPrefix(line, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// classifySSHError maps the last message of a failed ssh run to an error class.
+func classifySSHError(msg string) domain.ProbeErrorClass {
+	m := strings.ToLower(msg)
+	has := func(subs ...string) bool {
+		for _, s := range subs {
+			if strings.Contains(m, s) {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case has("could not resolve hostname", "name or service not known", "nodename nor servname"):
+		return domain.ProbeErrDNS
+	case has("connection refused"):
+		return domain.ProbeErrRefused
+	case has("timed out", "timeout"):
+		return domain.ProbeErrTimeout
+	case has("no route to host", "network is unreachable"):
+		return domain.ProbeErrNetwork
+	case has("host key verification failed", "remote host identification has changed", "host key is known"):
+		return domain.ProbeErrHostKey
+	case has("permission denied", "too many authentication failures", "no more authentication methods"):
+		return domain.ProbeErrAuth
+	case has("unknown port 65535", "stdio forwarding failed", "proxy", "channel 0: open failed"):
+		return domain.ProbeErrProxy
+	case has("kex_exchange_identification", "banner exchange", "connection closed", "connection reset", "protocol"):
+		return domain.ProbeErrProtocol
+	}
+	return domain.ProbeErrOther
+}
+
+func classifyDialError(err error) domain.ProbeErrorClass {
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &dnsErr):
+		return domain.ProbeErrDNS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return domain.ProbeErrRefused
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return domain.ProbeErrTimeout
+	case errors.Is(err, syscall.EHOSTUNREACH), errors.Is(err, syscall.ENETUNREACH):
+		return domain.ProbeErrNetwork
+	}
+	return domain.ProbeErrOther
+}
+
+func classifyProtocolError(err error) domain.ProbeErrorClass {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return domain.ProbeErrTimeout
+	}
+	return domain.ProbeErrProtocol
+}
+
+// readSSHBanner returns the server's version line, skipping the lines a server
+// may send before it.
+func readSSHBanner(br *bufio.Reader) (string, error) {
+	for range probeMaxBannerLines {
+		line, err := br.ReadSlice('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return "", errors.New("connection closed before the SSH banner")
+			}
+			if errors.Is(err, bufio.ErrBufferFull) {
+				return "", errors.New("not an SSH server: line too long")
+			}
+			return "", err
+		}
+		if text := strings.TrimRight(string(line), "\r\n"); strings.HasPrefix(text, "SSH-") {
+			return text, nil
+		}
+	}
+	return "", errors.New("not an SSH server: no banner")
+}
+
+// readKexInitHostKeys reads the server's first binary packet, which must be its
+// KEXINIT, and returns the host key algorithms it lists (RFC 4253 7.1).
+func readKexInitHostKeys(br *bufio.Reader) ([]string, error) {
+	var length uint32
+	if err := binary.Read(br, binary.BigEndian, &length); err != nil {
+		return nil, fmt.Errorf("reading KEXINIT: %w", err)
+	}
+	if length < 2 || length > probeMaxPacket {
+		return nil, fmt.Errorf("invalid packet length %d", length)
+	}
+	packet := make([]byte, length)
+	if _, err := io.ReadFull(br, packet); err != nil {
+		return nil, fmt.Errorf("reading KEXINIT: %w", err)
+	}
+	padding := int(packet[0])
+	if padding >= len(packet)-1 {
+		return nil, errors.New("invalid packet padding")
+	}
+	payload := packet[1 : len(packet)-padding]
+	if payload[0] != sshMsgKexInit {
+		return nil, fmt.Errorf("expected KEXINIT, got message %d", payload[0])
+	}
+	rest := payload[1:]
+	if len(rest) < 16 {
+		return nil, errors.New("short KEXINIT")
+	}
+	rest = rest[16:] // cookie
+	var lists [2][]string
+	for i := range lists {
+		if len(rest) < 4 {
+			return nil, errors.New("short KEXINIT")
+		}
+		n := binary.BigEndian.Uint32(rest)
+		if uint32(len(rest)-4) < n {
+			return nil, errors.New("short KEXINIT")
+		}
+		lists[i] = strings.Split(string(rest[4:4+n]), ",")
+		rest = rest[4+n:]
+	}
+	return lists[1], nil // kex_algorithms, then server_host_key_algorithms
+}
+
+// negotiateHostKey picks the first of the client's algorithms the server supports,
+// as ssh does.
+func negotiateHostKey(client, server []string) string {
+	for _, c := range client {
+		for _, s := range server {
+			if c == s {
+				return c
+			}
+		}
+	}
+	return ""
+}
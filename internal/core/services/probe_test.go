@@ -0,0 +1,195 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"go.uber.org/zap"
+)
+
+// kexInitPacket builds a KEXINIT binary packet offering the given host key algorithms.
+func kexInitPacket(hostKeys ...string) []byte {
+	payload := []byte{sshMsgKexInit}
+	payload = append(payload, make([]byte, 16)...)
+	for _, list := range []string{"curve25519-sha256", strings.Join(hostKeys, ",")} {
+		payload = binary.BigEndian.AppendUint32(payload, uint32(len(list)))
+		payload = append(payload, list...)
+	}
+	padding := 8 - (len(payload)+5)%8 + 4
+	packet := binary.BigEndian.AppendUint32(nil, uint32(1+len(payload)+padding))
+	packet = append(packet, byte(padding))
+	packet = append(packet, payload...)
+	return append(packet, make([]byte, padding)...)
+}
+
+func TestReadSSHBanner(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"plain", "SSH-2.0-OpenSSH_9.6\r\n", "SSH-2.0-OpenSSH_9.6", false},
+		{"preamble lines", "Welcome\r\nauthorized use only\r\nSSH-2.0-dropbear\r\n", "SSH-2.0-dropbear", false},
+		{"closed", "", "", true},
+		{"not ssh", strings.Repeat("HTTP/1.1 400 Bad Request\r\n", probeMaxBannerLines+1), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readSSHBanner(bufio.NewReader(strings.NewReader(tt.input)))
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("readSSHBanner() = %q, %v; want %q, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadKexInitHostKeys(t *testing.T) {
+	packet := kexInitPacket("rsa-sha2-512", "ssh-ed25519")
+	got, err := readKexInitHostKeys(bufio.NewReader(strings.NewReader(string(packet))))
+	if err != nil || strings.Join(got, ",") != "rsa-sha2-512,ssh-ed25519" {
+		t.Fatalf("readKexInitHostKeys() = %v, %v", got, err)
+	}
+
+	for name, input := range map[string][]byte{
+		"truncated":   packet[:len(packet)-10],
+		"too large":   {0xff, 0xff, 0xff, 0xff, 0},
+		"not kexinit": append([]byte{0, 0, 0, 12, 4, 21}, make([]byte, 10)...),
+	} {
+		if _, err := readKexInitHostKeys(bufio.NewReader(strings.NewReader(string(input)))); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestNegotiateHostKey(t *testing.T) {
+	client := []string{"ssh-ed25519", "ecdsa-sha2-nistp256", "rsa-sha2-512"}
+	if got := negotiateHostKey(client, []string{"rsa-sha2-512", "ecdsa-sha2-nistp256"}); got != "ecdsa-sha2-nistp256" {
+		t.Errorf("negotiateHostKey() = %q, want the client's first supported choice", got)
+	}
+	if got := negotiateHostKey(client, []string{"ssh-dss"}); got != "" {
+		t.Errorf("negotiateHostKey() = %q, want no match", got)
+	}
+}
+
+func TestClassifySSHError(t *testing.T) {
+	tests := map[string]domain.ProbeErrorClass{
+		"ssh: Could not resolve hostname nope: Name or service not known": domain.ProbeErrDNS,
+		"ssh: connect to host 127.0.0.1 port 1: Connection refused":       domain.ProbeErrRefused,
+		"ssh: connect to host 10.1.2.3 port 22: Connection timed out":     domain.ProbeErrTimeout,
+		"ssh: connect to host 10.1.2.3 port 22: No route to host":         domain.ProbeErrNetwork,
+		"Host key verification failed.":                                   domain.ProbeErrHostKey,
+		"user@web: Permission denied (publickey,password).":               domain.ProbeErrAuth,
+		"Connection closed by UNKNOWN port 65535":                         domain.ProbeErrProxy,
+		"kex_exchange_identification: read: Connection reset by peer":     domain.ProbeErrProtocol,
+		"something unexpected":                                            domain.ProbeErrOther,
+	}
+	for msg, want := range tests {
+		if got := classifySSHError(msg); got != want {
+			t.Errorf("classifySSHError(%q) = %q, want %q", msg, got, want)
+		}
+	}
+}
+
+func TestApplySSHDebugLine(t *testing.T) {
+	var r domain.ProbeResult
+	lines := []string{
+		"OpenSSH_9.2p1 Debian-2+deb12u7, OpenSSL 3.0.17 1 Jul 2025",
+		"debug1: Connection established.",
+		"debug1: Remote protocol version 2.0, remote software version OpenSSH_9.6p1 Ubuntu-3",
+		"debug1: kex: algorithm: curve25519-sha256",
+		"debug1: kex: host key algorithm: ssh-ed25519",
+		"debug1: Authentication succeeded (publickey).",
+	}
+	for _, line := range lines {
+		applySSHDebugLine(&r, line, time.Now())
+	}
+	if r.Stage != domain.ProbeAuthenticated || r.Banner != "SSH-2.0-OpenSSH_9.6p1 Ubuntu-3" || r.HostKey != "ssh-ed25519" {
+		t.Errorf("result = %+v", r)
+	}
+	if len(r.Timings) != 4 {
+		t.Errorf("got %d timings, want one per stage", len(r.Timings))
+	}
+}
+
+func TestProbeServer(t *testing.T) {
+	if _, err := exec.LookPath("ssh"); err != nil {
+		t.Skip("ssh not installed")
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = conn.Write([]byte("SSH-2.0-FakeSSH_1.0\r\n"))
+			_, _ = conn.Write(kexInitPacket("ssh-ed25519", "rsa-sha2-512"))
+			_, _ = bufio.NewReader(conn).ReadString('\n')
+			_ = conn.Close()
+		}
+	}()
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	cfg := filepath.Join(t.TempDir(), "config")
+	content := fmt.Sprintf("Host fake\n  HostName 127.0.0.1\n  Port %d\n  HostKeyAlgorithms rsa-sha2-512,ssh-ed25519\n\n"+
+		"Host closed\n  HostName 127.0.0.1\n  Port 1\n\n"+
+		"Host proxied\n  HostName 127.0.0.1\n  ProxyCommand false\n", port)
+	if err := os.WriteFile(cfg, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s := &serverService{logger: zap.NewNop().Sugar(), sshConfigFile: cfg, pingTimeout: 2 * time.Second, health: newHealthMonitor()}
+
+	r := s.ProbeServer(domain.Server{Alias: "fake"}, false)
+	if !r.OK() || r.Stage != domain.ProbeKeyExchange {
+		t.Fatalf("ProbeServer(fake) = %+v", r)
+	}
+	if r.Banner != "SSH-2.0-FakeSSH_1.0" || r.HostKey != "rsa-sha2-512" {
+		t.Errorf("banner %q, host key %q", r.Banner, r.HostKey)
+	}
+	if len(r.Timings) != 4 {
+		t.Errorf("got %d timings, want 4", len(r.Timings))
+	}
+
+	r = s.ProbeServer(domain.Server{Alias: "closed"}, false)
+	if r.OK() || r.Class != domain.ProbeErrRefused || r.Stage != domain.ProbeResolved {
+		t.Errorf("ProbeServer(closed) = %+v", r)
+	}
+
+	r = s.ProbeServer(domain.Server{Alias: "proxied"}, false)
+	if r.OK() || r.Class != domain.ProbeErrProxy || r.Via != "ProxyCommand false" {
+		t.Errorf("ProbeServer(proxied) = %+v", r)
+	}
+
+	health := s.ServerHealth()
+	if health["fake"].State() != domain.ReachUp || health["closed"].State() != domain.ReachDown {
+		t.Errorf("ServerHealth() = %+v", health)
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+// cumulativeKeywords are ssh_config keywords whose values add up across blocks
+// instead of the first one winning.
+var cumulativeKeywords = map[string]bool{
+	"identityfile":    true,
+	"certificatefile": true,
+	"localforward":    true,
+	"remoteforward":   true,
+	"dynamicforward":  true,
+	"sendenv":         true,
+}
+
+// ListProfiles returns the pattern Host blocks (e.g. "Host *.prod") as servers
+// whose Alias is the pattern list.
+func (s *serverService) ListProfiles() ([]domain.Server, error) {
+	profiles, err := s.serverRepository.ListProfiles()
+	if err != nil {
+		s.logger.Errorw("failed to list profiles", "error", err)
+	}
+	return profiles, err
+}
+
+// AddProfile adds a new pattern Host block.
+func (s *serverService) AddProfile(profile domain.Server) error {
+	if err := validateProfile(profile); err != nil {
+		s.logger.Warnw("validation failed on profile add", "error", err, "profile", profile)
+		return err
+	}
+	err := s.serverRepository.AddProfile(profile)
+	if err != nil {
+		s.logger.Errorw("failed to add profile", "error", err, "profile", profile)
+	}
+	return err
+}
+
+// UpdateProfile updates the patterns and settings of a profile.
+func (s *serverService) UpdateProfile(profile domain.Server, newProfile domain.Server) error {
+	if err := validateProfile(newProfile); err != nil {
+		s.logger.Warnw("validation failed on profile update", "error", err, "profile", newProfile)
+		return err
+	}
+	err := s.serverRepository.UpdateProfile(profile, newProfile)
+	if err != nil {
+		s.logger.Errorw("failed to update profile", "error", err, "profile", profile)
+	}
+	return err
+}
+
+// DeleteProfile removes a profile.
+func (s *serverService) DeleteProfile(profile domain.Server) error {
+	err := s.serverRepository.DeleteProfile(profile)
+	if err != nil {
+		s.logger.Errorw("failed to delete profile", "error", err, "profile", profile)
+	}
+	return err
+}
+
+// ExplainInheritance lists the settings the server receives from matching profiles
+// and whether each one is used, overridden, or wins over the server's own value.
+// contributions are the blocks ExplainServer returned for the server.
+func (s *serverService) ExplainInheritance(server domain.Server, contributions []domain.BlockContribution) []domain.InheritedSetting {
+	return inheritedSettings(server, contributions)
+}
+
+// inheritedSettings walks the contributing blocks in order, remembering the first
+// value of each keyword, and classifies the settings of every profile block.
+func inheritedSettings(server domain.Server, contributions []domain.BlockContribution) []domain.InheritedSetting {
+	type firstValue struct {
+		value string
+		block int
+	}
+	first := make(map[string]firstValue)
+	own := -1
+	for i, c := range contributions {
+		if c.State != domain.MatchYes {
+			continue
+		}
+		if own == -1 && isOwnBlock(c.Block, server) {
+			own = i
+		}
+		for _, st := range c.Block.Settings {
+			key := strings.ToLower(st.Key)
+			if _, ok := first[key]; !ok {
+				first[key] = firstValue{value: st.Value, block: i}
+			}
+		}
+	}
+
+	ownKeys := make(map[string]bool)
+	if own >= 0 {
+		for _, st := range contributions[own].Block.Settings {
+			ownKeys[strings.ToLower(st.Key)] = true
+		}
+	}
+
+	out := make([]domain.InheritedSetting, 0, 8)
+	for i, c := range contributions {
+		if i == own || c.State != domain.MatchYes || !isProfileBlock(c.Block) {
+			continue
+		}
+		for _, st := range c.Block.Settings {
+			key := strings.ToLower(st.Key)
+			setting := domain.InheritedSetting{Profile: c.Block, Key: st.Key, Value: st.Value}
+			switch {
+			case cumulativeKeywords[key]:
+				setting.State = domain.Appended
+			case first[key].block != i || first[key].value != st.Value:
+				setting.State = domain.Overridden
+				setting.Winner = first[key].value
+			case ownKeys[key]:
+				setting.State = domain.Shadows
+			default:
+				setting.State = domain.Inherited
+			}
+			out = append(out, setting)
+		}
+	}
+	return out
+}
+
+// isOwnBlock reports whether b is the Host block that defines the server itself.
+func isOwnBlock(b domain.ConfigBlock, server domain.Server) bool {
+	if b.Kind != domain.BlockHost || (server.SourceFile != "" && b.SourceFile != server.SourceFile) {
+		return false
+	}
+	for _, p := range b.Patterns {
+		if p == server.Alias {
+			return true
+		}
+	}
+	return false
+}
+
+// isProfileBlock reports whether b is a Host block with wildcard or negated patterns.
+func isProfileBlock(b domain.ConfigBlock) bool {
+	if b.Kind != domain.BlockHost {
+		return false
+	}
+	for _, p := range b.Patterns {
+		if strings.ContainsAny(p, "!*?[]") {
+			return true
+		}
+	}
+	return false
+}
+
+// validateProfile checks the pattern list of a profile. Unlike servers, profiles
+// need no HostName and may use wildcards, negation and %-tokens.
+func validateProfile(profile domain.Server) error {
+	patterns := strings.Fields(profile.Alias)
+	if len(patterns) == 0 {
+		return fmt.Errorf("at least one host pattern is required")
+	}
+	for _, p := range patterns {
+		if strings.ContainsAny(p, "\"#") {
+			return fmt.Errorf("pattern %q must not contain quotes or '#'", p)
+		}
+		if strings.TrimPrefix(p, "!") == "" {
+			return fmt.Errorf("pattern %q is empty", p)
+		}
+	}
+	if profile.Port != 0 && (profile.Port < 1 || profile.Port > 65535) {
+		return fmt.Errorf("port must be a number between 1 and 65535")
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestInheritedSettings(t *testing.T) {
+	server := domain.Server{Alias: "web", SourceFile: "/c"}
+	early := domain.ConfigBlock{Kind: domain.BlockHost, Patterns: []string{"w*"}, SourceFile: "/c",
+		Settings: []domain.ConfigSetting{{Key: "User", Value: "admin"}}}
+	own := domain.ConfigBlock{Kind: domain.BlockHost, Patterns: []string{"web"}, SourceFile: "/c",
+		Settings: []domain.ConfigSetting{{Key: "User", Value: "root"}, {Key: "Port", Value: "2222"}}}
+	all := domain.ConfigBlock{Kind: domain.BlockHost, Patterns: []string{"*"}, SourceFile: "/c",
+		Settings: []domain.ConfigSetting{
+			{Key: "Port", Value: "22"},
+			{Key: "ProxyJump", Value: "bastion"},
+			{Key: "IdentityFile", Value: "~/.ssh/team"},
+		}}
+	contributions := []domain.BlockContribution{
+		{Block: early, State: domain.MatchYes},
+		{Block: own, State: domain.MatchYes},
+		{Block: all, State: domain.MatchYes},
+	}
+
+	got := inheritedSettings(server, contributions)
+	want := map[string]domain.InheritState{
+		"User":         domain.Shadows,
+		"Port":         domain.Overridden,
+		"ProxyJump":    domain.Inherited,
+		"IdentityFile": domain.Appended,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d settings, want %d: %+v", len(got), len(want), got)
+	}
+	for _, s := range got {
+		if s.State != want[s.Key] {
+			t.Errorf("%s: state = %v, want %v", s.Key, s.State, want[s.Key])
+		}
+	}
+	if got[1].Winner != "2222" {
+		t.Errorf("Port winner = %q, want 2222", got[1].Winner)
+	}
+}
+
+func TestValidateProfile(t *testing.T) {
+	tests := []struct {
+		alias   string
+		wantErr bool
+	}{
+		{"*.prod.example.com", false},
+		{"* !bastion", false},
+		{"", true},
+		{"!", true},
+		{`"quoted"`, true},
+	}
+	for _, tt := range tests {
+		if err := validateProfile(domain.Server{Alias: tt.alias}); (err != nil) != tt.wantErr {
+			t.Errorf("validateProfile(%q) error = %v, wantErr %v", tt.alias, err, tt.wantErr)
+		}
+	}
+}
@@ -15,12 +15,12 @@
 package services
 
 import (
-	"bufio"
 	"fmt"
 	"net"
 	"os"
 	"os/exec"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -32,22 +32,84 @@ import (
 	"go.uber.org/zap"
 )
 
+// defaultPingTimeout bounds the TCP dial of Ping.
+const defaultPingTimeout = 3 * time.Second
+
 type serverService struct {
 	serverRepository ports.ServerRepository
 	logger           *zap.SugaredLogger
+	pingTimeout      time.Duration
+	sshConfigFile    string
+	transfers        *transferQueue
+	tunnels          *tunnelSupervisor
+	health           *healthMonitor
+	watch            configWatch
+}
+
+// Option configures the server service.
+type Option func(*serverService)
+
+// WithPingTimeout sets how long Ping waits for the SSH port to accept a connection.
+func WithPingTimeout(d time.Duration) Option {
+	return func(s *serverService) {
+		if d > 0 {
+			s.pingTimeout = d
+		}
+	}
+}
+
+// WithSSHConfigFile makes ssh and ssh-copy-id read path (-F) instead of ~/.ssh/config.
+// Note that -F also skips the system-wide /etc/ssh/ssh_config.
+func WithSSHConfigFile(path string) Option {
+	return func(s *serverService) {
+		s.sshConfigFile = path
+	}
 }
 
 // NewServerService creates a new instance of serverService.
-func NewServerService(logger *zap.SugaredLogger, sr ports.ServerRepository) ports.ServerService {
-	return &serverService{
+func NewServerService(logger *zap.SugaredLogger, sr ports.ServerRepository, opts ...Option) ports.ServerService {
+	s := &serverService{
 		logger:           logger,
 		serverRepository: sr,
+		pingTimeout:      defaultPingTimeout,
+		transfers:        &transferQueue{},
+		tunnels:          newTunnelSupervisor(),
+		health:           newHealthMonitor(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// sshArgs prefixes args with the -F option when a custom SSH config is used.
+func (s *serverService) sshArgs(args ...string) []string {
+	if s.sshConfigFile == "" {
+		return args
 	}
+	return append([]string{"-F", s.sshConfigFile}, args...)
 }
 
 // ListServers returns servers. With empty query, keep pinned-first default ordering.
 // With non-empty query, perform fuzzy subsequence matching and rank by relevance.
+// An "is:up", "is:down" or "is:unknown" term keeps the servers whose last probe
+// had that result.
 func (s *serverService) ListServers(query string) ([]domain.Server, error) {
+	q, state, filter := splitHealthFilter(query)
+	servers, err := s.listServers(q)
+	if err != nil {
+		return nil, err
+	}
+	s.health.attach(servers)
+	if filter {
+		servers = slices.DeleteFunc(servers, func(srv domain.Server) bool {
+			return srv.Health.State() != state
+		})
+	}
+	return servers, nil
+}
+
+func (s *serverService) listServers(query string) ([]domain.Server, error) {
 	q := strings.TrimSpace(query)
 	if q == "" {
 		servers, err := s.serverRepository.ListServers("")
@@ -327,10 +389,42 @@ func (s *serverService) SetPinned(alias string, pinned bool) error {
 	return err
 }
 
+// Undo reverts the last Add/Update/Delete, tag or pin change and returns its description.
+func (s *serverService) Undo() (string, error) {
+	summary, err := s.serverRepository.Undo()
+	if err != nil {
+		s.logger.Warnw("undo failed", "error", err)
+		return "", err
+	}
+	s.logger.Infow("undo", "change", summary)
+	return summary, nil
+}
+
+// Redo applies the last undone change again and returns its description.
+func (s *serverService) Redo() (string, error) {
+	summary, err := s.serverRepository.Redo()
+	if err != nil {
+		s.logger.Warnw("redo failed", "error", err)
+		return "", err
+	}
+	s.logger.Infow("redo", "change", summary)
+	return summary, nil
+}
+
+// ServerLog returns the git commits that changed the Host block of server.
+func (s *serverService) ServerLog(server domain.Server) ([]domain.ConfigCommit, error) {
+	commits, err := s.serverRepository.ServerLog(server)
+	if err != nil {
+		s.logger.Warnw("failed to read server log", "alias", server.Alias, "error", err)
+		return nil, err
+	}
+	return commits, nil
+}
+
 // SSH starts an interactive SSH session to the given alias using the system's ssh client.
 func (s *serverService) SSH(alias string) error {
 	s.logger.Infow("ssh start", "alias", alias)
-	cmd := exec.Command("ssh", alias)
+	cmd := exec.Command("ssh", s.sshArgs(alias)...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -347,7 +441,17 @@ func (s *serverService) SSH(alias string) error {
 	return nil
 }
 
-func (s *serverService) CopySSHKey(alias string) error {
+// CopySSHKey installs a public key into the server's authorized_keys with
+// ssh-copy-id. An empty publicKey lets ssh-copy-id pick the default key.
+func (s *serverService) CopySSHKey(alias, publicKey string) error {
+	if publicKey == "" {
+		return s.runSSHCopyID(alias)
+	}
+	return s.runSSHCopyID(alias, "-i", expandHome(publicKey))
+}
+
+// runSSHCopyID runs ssh-copy-id on the terminal so it can prompt for a password.
+func (s *serverService) runSSHCopyID(alias string, args ...string) error {
 	s.logger.Infow("ssh-copy-id start", "alias", alias)
 
 	// Ensure ssh-copy-id exists on PATH
@@ -356,7 +460,7 @@ func (s *serverService) CopySSHKey(alias string) error {
 		return fmt.Errorf("ssh-copy-id not found; install OpenSSH (e.g., brew install openssh)")
 	}
 
-	cmd := exec.Command("ssh-copy-id", alias)
+	cmd := exec.Command("ssh-copy-id", s.sshArgs(append(args, alias)...)...)
 
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
@@ -370,11 +474,10 @@ func (s *serverService) CopySSHKey(alias string) error {
 	return nil
 }
 
-// Ping checks if the server is reachable on its SSH port.
+// Ping checks if the server is reachable on its SSH port. The result is added
+// to the server's health history.
 func (s *serverService) Ping(server domain.Server) (bool, time.Duration, error) {
-	start := time.Now()
-
-	host, port, ok := resolveSSHDestination(server.Alias)
+	host, port, ok := s.resolveSSHDestination(server.Alias)
 	if !ok {
 
 		host = strings.TrimSpace(server.Host)
@@ -389,45 +492,38 @@ func (s *serverService) Ping(server domain.Server) (bool, time.Duration, error)
 	}
 	addr := net.JoinHostPort(host, fmt.Sprintf("%d", port))
 
-	dialer := net.Dialer{Timeout: 3 * time.Second}
+	start := time.Now()
+	dialer := net.Dialer{Timeout: s.pingTimeout}
 	conn, err := dialer.Dial("tcp", addr)
+	latency := time.Since(start)
+	sample := domain.HealthSample{At: start, Up: err == nil, Latency: latency}
 	if err != nil {
-		return false, time.Since(start), err
+		sample.Error = err.Error()
+	}
+	s.health.record(server.Alias, sample)
+	if err != nil {
+		return false, latency, err
 	}
 	_ = conn.Close()
-	return true, time.Since(start), nil
+	return true, latency, nil
 }
 
 // resolveSSHDestination uses `ssh -G <alias>` to extract HostName and Port from the user's SSH config.
 // Returns host, port, ok where ok=false if resolution failed.
-func resolveSSHDestination(alias string) (string, int, bool) {
+func (s *serverService) resolveSSHDestination(alias string) (string, int, bool) {
 	alias = strings.TrimSpace(alias)
 	if alias == "" {
 		return "", 0, false
 	}
-	cmd := exec.Command("ssh", "-G", alias)
-	out, err := cmd.Output()
+	options, err := runSSHG(s.sshArgs(alias)...)
 	if err != nil {
 		return "", 0, false
 	}
-	host := ""
+	host, _ := lookupOption(options, "hostname")
 	port := 0
-	scanner := bufio.NewScanner(strings.NewReader(string(out)))
-	for scanner.Scan() {
-		line := scanner.Text()
-		if strings.HasPrefix(line, "hostname ") {
-			parts := strings.Fields(line)
-			if len(parts) >= 2 {
-				host = parts[1]
-			}
-		}
-		if strings.HasPrefix(line, "port ") {
-			parts := strings.Fields(line)
-			if len(parts) >= 2 {
-				if p, err := strconv.Atoi(parts[1]); err == nil {
-					port = p
-				}
-			}
+	if v, ok := lookupOption(options, "port"); ok {
+		if p, err := strconv.Atoi(v); err == nil {
+			port = p
 		}
 	}
 	if host == "" {
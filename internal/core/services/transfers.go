@@ -0,0 +1,492 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/creack/pty"
+)
+
+const (
+	remoteListTimeout = 20 * time.Second
+	// maxFinishedTransfers bounds how many completed transfers the queue remembers.
+	maxFinishedTransfers = 50
+	// progressColumns is the width of the terminal scp renders its progress meter for.
+	progressColumns = 160
+)
+
+// transferQueue runs scp transfers one at a time in the background.
+type transferQueue struct {
+	mu        sync.Mutex
+	transfers []domain.Transfer
+	nextID    int
+	running   bool
+	cancel    context.CancelFunc // cancels the running transfer
+}
+
+var (
+	// sftpListLine matches a long listing line of sftp's `ls -l`, e.g.
+	// "drwxr-xr-x    5 deploy   deploy       4096 Oct 17 02:00 releases".
+	sftpListLine = regexp.MustCompile(`^([-dlbcps][-rwxsStT]{9}\S*)\s+\d+\s+\S+\s+\S+\s+(\d+)\s+(\S+\s+\S+\s+\S+) (.+)$`)
+	// scpProgress matches scp's progress meter, e.g. "app.tar.gz   45%   12MB  3.1MB/s   00:07 ETA".
+	scpProgress = regexp.MustCompile(`^(.+?)\s+(\d{1,3})%\s+(.*)$`)
+	etaPattern  = regexp.MustCompile(`^(\d+:)?\d+:\d+$|^--:--$`)
+	// scpUsageFlags matches the single-letter flags of scp's usage message, e.g.
+	// "usage: scp [-346ABCOpqRrsTv] [-c cipher] ...".
+	scpUsageFlags = regexp.MustCompile(`usage: scp \[-([0-9A-Za-z]+)\]`)
+)
+
+// scpHasSFTPMode reports whether the local scp takes -s to copy over the SFTP
+// protocol (OpenSSH 8.7 and later), going by the flags its usage message lists.
+var scpHasSFTPMode = sync.OnceValue(func() bool {
+	out, _ := exec.Command("scp").CombinedOutput()
+	m := scpUsageFlags.FindSubmatch(out)
+	return m != nil && bytes.ContainsRune(m[1], 's')
+})
+
+// ListLocalDir lists a local directory, directories first. An empty path means the home directory.
+func (s *serverService) ListLocalDir(path string) ([]domain.FileEntry, error) {
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = home
+	}
+	dirEntries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]domain.FileEntry, 0, len(dirEntries))
+	for _, de := range dirEntries {
+		info, err := de.Info()
+		if err != nil {
+			continue
+		}
+		e := domain.FileEntry{
+			Name:     de.Name(),
+			Dir:      info.IsDir(),
+			Link:     info.Mode()&os.ModeSymlink != 0,
+			Size:     info.Size(),
+			Mode:     info.Mode().String(),
+			Modified: formatModTime(info.ModTime()),
+		}
+		if e.Link {
+			if target, err := os.Stat(filepath.Join(path, de.Name())); err == nil && target.IsDir() {
+				e.Dir = true
+			}
+		}
+		entries = append(entries, e)
+	}
+	sortFileEntries(entries)
+	return entries, nil
+}
+
+// ListRemoteDir lists a directory of the server with `sftp -b`, which goes through
+// the same SSH config alias as ssh. An empty path means the remote home directory.
+// It returns the absolute remote path that was listed.
+func (s *serverService) ListRemoteDir(alias, path string) (string, []domain.FileEntry, error) {
+	var script strings.Builder
+	if path != "" {
+		script.WriteString("cd " + quoteSFTPPath(path) + "\n")
+	}
+	script.WriteString("pwd\nls -la\n")
+
+	ctx, cancel := context.WithTimeout(context.Background(), remoteListTimeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	args := append(s.sshArgs("-o", "BatchMode=yes", "-b", "-"), alias)
+	cmd := exec.CommandContext(ctx, "sftp", args...)
+	cmd.Stdin = strings.NewReader(script.String())
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		s.logger.Errorw("sftp listing failed", "alias", alias, "path", path, "error", err)
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", nil, fmt.Errorf("sftp: %s", lastLine(msg))
+		}
+		return "", nil, fmt.Errorf("sftp: %w", err)
+	}
+	dir, entries := parseSFTPListing(out)
+	if dir == "" {
+		dir = path
+	}
+	sortFileEntries(entries)
+	return dir, entries, nil
+}
+
+// EnqueueTransfer adds a transfer to the queue and returns its ID. Transfers run
+// one after another with the system scp.
+func (s *serverService) EnqueueTransfer(t domain.Transfer) (int, error) {
+	if t.Alias == "" || t.Source == "" || t.Target == "" {
+		return 0, fmt.Errorf("transfer needs a server, a source and a target")
+	}
+	if _, err := exec.LookPath("scp"); err != nil {
+		return 0, fmt.Errorf("scp not found; install OpenSSH")
+	}
+
+	q := s.transfers
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.nextID++
+	t.ID = q.nextID
+	t.State = domain.TransferQueued
+	q.transfers = append(q.transfers, t)
+	q.trimFinished()
+	if !q.running {
+		q.running = true
+		go s.runTransfers()
+	}
+	s.logger.Infow("transfer queued", "id", t.ID, "alias", t.Alias, "source", t.Source, "target", t.Target)
+	return t.ID, nil
+}
+
+// ListTransfers returns the queued, running and recently finished transfers.
+func (s *serverService) ListTransfers() []domain.Transfer {
+	q := s.transfers
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]domain.Transfer(nil), q.transfers...)
+}
+
+// CancelTransfer removes a queued transfer from the queue or stops the running one.
+func (s *serverService) CancelTransfer(id int) error {
+	q := s.transfers
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i := range q.transfers {
+		t := &q.transfers[i]
+		if t.ID != id {
+			continue
+		}
+		switch t.State {
+		case domain.TransferQueued:
+			t.State = domain.TransferCanceled
+			t.FinishedAt = time.Now()
+			return nil
+		case domain.TransferRunning:
+			if q.cancel != nil {
+				q.cancel()
+			}
+			return nil
+		default:
+			return fmt.Errorf("transfer %d is already %s", id, t.State)
+		}
+	}
+	return fmt.Errorf("transfer %d not found", id)
+}
+
+// runTransfers is the queue worker; it exits when no transfer is left.
+func (s *serverService) runTransfers() {
+	q := s.transfers
+	for {
+		q.mu.Lock()
+		idx := -1
+		for i, t := range q.transfers {
+			if t.State == domain.TransferQueued {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			q.running = false
+			q.cancel = nil
+			q.mu.Unlock()
+			return
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		q.cancel = cancel
+		q.transfers[idx].State = domain.TransferRunning
+		q.transfers[idx].StartedAt = time.Now()
+		t := q.transfers[idx]
+		q.mu.Unlock()
+
+		err := s.runSCP(ctx, t, func(file string, percent int, rate, eta string) {
+			q.update(t.ID, func(t *domain.Transfer) {
+				t.File, t.Percent, t.Rate, t.ETA = file, percent, rate, eta
+			})
+		})
+		canceled := ctx.Err() != nil
+		cancel()
+
+		q.update(t.ID, func(t *domain.Transfer) {
+			t.FinishedAt = time.Now()
+			t.ETA = ""
+			switch {
+			case canceled:
+				t.State = domain.TransferCanceled
+			case err != nil:
+				t.State = domain.TransferFailed
+				t.Error = err.Error()
+			default:
+				t.State = domain.TransferDone
+				t.Percent = 100
+			}
+		})
+		if err != nil && !canceled {
+			s.logger.Errorw("transfer failed", "id", t.ID, "alias", t.Alias, "error", err)
+		} else {
+			s.logger.Infow("transfer finished", "id", t.ID, "alias", t.Alias, "canceled", canceled)
+		}
+	}
+}
+
+func (q *transferQueue) update(id int, fn func(*domain.Transfer)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i := range q.transfers {
+		if q.transfers[i].ID == id {
+			fn(&q.transfers[i])
+			return
+		}
+	}
+}
+
+// trimFinished forgets the oldest finished transfers beyond maxFinishedTransfers.
+func (q *transferQueue) trimFinished() {
+	finished := 0
+	for _, t := range q.transfers {
+		if isFinished(t.State) {
+			finished++
+		}
+	}
+	if finished <= maxFinishedTransfers {
+		return
+	}
+	kept := q.transfers[:0]
+	for _, t := range q.transfers {
+		if finished > maxFinishedTransfers && isFinished(t.State) {
+			finished--
+			continue
+		}
+		kept = append(kept, t)
+	}
+	q.transfers = kept
+}
+
+func isFinished(s domain.TransferState) bool {
+	return s == domain.TransferDone || s == domain.TransferFailed || s == domain.TransferCanceled
+}
+
+// runSCP copies one transfer. scp only draws its progress meter on a terminal, so
+// it runs on a pseudo-terminal when the platform has one. BatchMode keeps it from
+// prompting for passwords or host keys, since the TUI owns the real terminal.
+func (s *serverService) runSCP(ctx context.Context, t domain.Transfer, progress func(file string, percent int, rate, eta string)) error {
+	args := append(s.sshArgs("-o", "BatchMode=yes"), scpArgs(t, scpHasSFTPMode())...)
+	cmd := exec.CommandContext(ctx, "scp", args...)
+	f, err := pty.StartWithSize(cmd, &pty.Winsize{Cols: progressColumns, Rows: 24})
+	if err != nil {
+		// No pseudo-terminal (e.g. on Windows): copy without progress.
+		var stderr bytes.Buffer
+		cmd = exec.CommandContext(ctx, "scp", args...)
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return scpError(lastLine(stderr.String()), err)
+		}
+		return nil
+	}
+	defer func() { _ = f.Close() }()
+
+	message := scanSCPOutput(f, progress)
+	if err := cmd.Wait(); err != nil {
+		return scpError(message, err)
+	}
+	return nil
+}
+
+// scpArgs builds the flags and paths of an scp command line for a transfer.
+// Relative local paths get a "./" prefix so a colon in them is not read as a
+// host. With sftpMode scp copies over SFTP and takes remote paths as they are;
+// the legacy protocol hands them to the remote shell, so they are quoted.
+func scpArgs(t domain.Transfer, sftpMode bool) []string {
+	var args []string
+	if sftpMode {
+		args = append(args, "-s")
+	}
+	if t.Recursive {
+		args = append(args, "-r")
+	}
+	local := func(p string) string {
+		if filepath.IsAbs(p) {
+			return p
+		}
+		return "." + string(filepath.Separator) + p
+	}
+	remote := func(p string) string {
+		if sftpMode {
+			return t.Alias + ":" + p
+		}
+		return t.Alias + ":" + quoteShellPath(p)
+	}
+	if t.Direction == domain.Upload {
+		return append(args, local(t.Source), remote(t.Target))
+	}
+	return append(args, remote(t.Source), local(t.Target))
+}
+
+// quoteShellPath quotes a path for a POSIX shell, leaving a leading "~/" outside
+// the quotes so the shell still expands it.
+func quoteShellPath(p string) string {
+	prefix := ""
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		prefix, p = "~/", strings.TrimPrefix(strings.TrimPrefix(p, "~"), "/")
+	}
+	if p == "" {
+		return prefix
+	}
+	return prefix + "'" + strings.ReplaceAll(p, "'", `'\''`) + "'"
+}
+
+func scpError(message string, err error) error {
+	if message != "" {
+		return errors.New(message)
+	}
+	return fmt.Errorf("scp: %w", err)
+}
+
+// scanSCPOutput reports progress meter updates and returns the last other line,
+// which holds the error message when scp fails.
+func scanSCPOutput(r io.Reader, progress func(file string, percent int, rate, eta string)) string {
+	var message string
+	scanner := bufio.NewScanner(r)
+	scanner.Split(scanTerminalLines)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if file, percent, rate, eta, ok := parseSCPProgress(line); ok {
+			progress(file, percent, rate, eta)
+			continue
+		}
+		message = line
+	}
+	// Reading a pseudo-terminal fails with EIO once scp exits; that is the normal end.
+	return message
+}
+
+// scanTerminalLines splits on '\r' as well as '\n', since the progress meter
+// redraws its line with carriage returns.
+func scanTerminalLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// parseSCPProgress parses a progress meter line into the file name, percentage,
+// transfer rate and remaining time.
+func parseSCPProgress(line string) (file string, percent int, rate, eta string, ok bool) {
+	m := scpProgress.FindStringSubmatch(line)
+	if m == nil {
+		return "", 0, "", "", false
+	}
+	percent, err := strconv.Atoi(m[2])
+	if err != nil || percent > 100 {
+		return "", 0, "", "", false
+	}
+	for _, f := range strings.Fields(m[3]) {
+		switch {
+		case strings.HasSuffix(f, "/s"):
+			rate = f
+		case etaPattern.MatchString(f):
+			eta = f
+		}
+	}
+	return strings.TrimSpace(m[1]), percent, rate, eta, true
+}
+
+// parseSFTPListing parses the output of the "pwd" and "ls -la" batch commands.
+func parseSFTPListing(out []byte) (string, []domain.FileEntry) {
+	var dir string
+	entries := make([]domain.FileEntry, 0, 32)
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.HasPrefix(line, "sftp>") {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(line, "Remote working directory: "); ok {
+			dir = strings.TrimSpace(rest)
+			continue
+		}
+		m := sftpListLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		name := m[4]
+		if name == "." || name == ".." {
+			continue
+		}
+		size, _ := strconv.ParseInt(m[2], 10, 64)
+		entries = append(entries, domain.FileEntry{
+			Name:     name,
+			Dir:      m[1][0] == 'd',
+			Link:     m[1][0] == 'l',
+			Size:     size,
+			Mode:     m[1],
+			Modified: strings.Join(strings.Fields(m[3]), " "),
+		})
+	}
+	return dir, entries
+}
+
+// quoteSFTPPath quotes a path for an sftp batch command.
+func quoteSFTPPath(p string) string {
+	p = strings.ReplaceAll(p, `\`, `\\`)
+	return `"` + strings.ReplaceAll(p, `"`, `\"`) + `"`
+}
+
+func sortFileEntries(entries []domain.FileEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Dir != entries[j].Dir {
+			return entries[i].Dir
+		}
+		return strings.ToLower(entries[i].Name) < strings.ToLower(entries[j].Name)
+	})
+}
+
+// formatModTime formats like ls: the time for recent files, the year for older ones.
+func formatModTime(t time.Time) string {
+	if time.Since(t) > 180*24*time.Hour || t.After(time.Now().Add(time.Hour)) {
+		return t.Format("Jan _2 2006")
+	}
+	return t.Format("Jan _2 15:04")
+}
+
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,142 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+func TestParseSCPProgress(t *testing.T) {
+	tests := []struct {
+		line    string
+		ok      bool
+		file    string
+		percent int
+		rate    string
+		eta     string
+	}{
+		{"app.tar.gz                                     45%   12MB   3.1MB/s   00:07 ETA", true, "app.tar.gz", 45, "3.1MB/s", "00:07"},
+		{"my report.pdf                                 100%  512KB 498.2KB/s   00:01    ", true, "my report.pdf", 100, "498.2KB/s", "00:01"},
+		{"big.iso                                         3%   30MB   0.0KB/s - stalled -", true, "big.iso", 3, "0.0KB/s", ""},
+		{"scp: /srv/missing: No such file or directory", false, "", 0, "", ""},
+		{"Permission denied (publickey).", false, "", 0, "", ""},
+	}
+	for _, tt := range tests {
+		file, percent, rate, eta, ok := parseSCPProgress(tt.line)
+		if ok != tt.ok || file != tt.file || percent != tt.percent || rate != tt.rate || eta != tt.eta {
+			t.Errorf("parseSCPProgress(%q) = %q, %d, %q, %q, %t", tt.line, file, percent, rate, eta, ok)
+		}
+	}
+}
+
+func TestScanSCPOutput(t *testing.T) {
+	out := "a.txt   10%  1KB  1.0KB/s   00:09 ETA\ra.txt  100%   10KB  9.8KB/s   00:00    \r\nscp: b.txt: Permission denied\r\n"
+	var percents []int
+	msg := scanSCPOutput(strings.NewReader(out), func(_ string, percent int, _, _ string) {
+		percents = append(percents, percent)
+	})
+	if len(percents) != 2 || percents[1] != 100 {
+		t.Errorf("progress updates = %v", percents)
+	}
+	if msg != "scp: b.txt: Permission denied" {
+		t.Errorf("message = %q", msg)
+	}
+}
+
+func TestParseSFTPListing(t *testing.T) {
+	out := `sftp> pwd
+Remote working directory: /home/deploy
+sftp> ls -la
+drwxr-xr-x    5 deploy   deploy       4096 Oct 17 02:00 .
+drwxr-xr-x    3 root     root         4096 Jan  2  2024 ..
+-rw-r--r--    1 deploy   deploy        220 Jan  2  2024 .profile
+drwxr-xr-x    2 deploy   deploy       4096 Oct 17 02:00 releases
+-rw-r--r--    1 deploy   deploy    1048576 Oct  7 11:30 my notes.txt
+lrwxrwxrwx    1 deploy   deploy         11 Oct  7 11:30 current
+`
+	dir, entries := parseSFTPListing([]byte(out))
+	if dir != "/home/deploy" {
+		t.Errorf("dir = %q", dir)
+	}
+	want := []domain.FileEntry{
+		{Name: ".profile", Size: 220, Mode: "-rw-r--r--", Modified: "Jan 2 2024"},
+		{Name: "releases", Dir: true, Size: 4096, Mode: "drwxr-xr-x", Modified: "Oct 17 02:00"},
+		{Name: "my notes.txt", Size: 1048576, Mode: "-rw-r--r--", Modified: "Oct 7 11:30"},
+		{Name: "current", Link: true, Size: 11, Mode: "lrwxrwxrwx", Modified: "Oct 7 11:30"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("entries = %+v", entries)
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, entries[i], want[i])
+		}
+	}
+}
+
+func TestQuoteSFTPPath(t *testing.T) {
+	if got := quoteSFTPPath(`/srv/my "app"\x`); got != `"/srv/my \"app\"\\x"` {
+		t.Errorf("quoteSFTPPath() = %s", got)
+	}
+}
+
+func TestTrimFinishedTransfers(t *testing.T) {
+	q := &transferQueue{}
+	for i := 0; i < maxFinishedTransfers+5; i++ {
+		q.transfers = append(q.transfers, domain.Transfer{ID: i + 1, State: domain.TransferDone})
+	}
+	q.transfers = append(q.transfers, domain.Transfer{ID: 100, State: domain.TransferQueued})
+	q.trimFinished()
+	if len(q.transfers) != maxFinishedTransfers+1 || q.transfers[0].ID != 6 {
+		t.Errorf("kept %d transfers, first %d", len(q.transfers), q.transfers[0].ID)
+	}
+}
+
+func TestSCPArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		transfer domain.Transfer
+		sftpMode bool
+		want     []string
+	}{
+		{
+			name:     "upload of a relative path with a colon",
+			transfer: domain.Transfer{Alias: "web", Direction: domain.Upload, Source: "backup:2025.tar", Target: "/srv/my backups"},
+			sftpMode: true,
+			want:     []string{"-s", "./backup:2025.tar", "web:/srv/my backups"},
+		},
+		{
+			name:     "legacy download quotes the remote path",
+			transfer: domain.Transfer{Alias: "web", Direction: domain.Download, Source: "/srv/it's $HOME", Target: "/tmp", Recursive: true},
+			want:     []string{"-r", `web:'/srv/it'\''s $HOME'`, "/tmp"},
+		},
+		{
+			name:     "legacy keeps the home directory expandable",
+			transfer: domain.Transfer{Alias: "web", Direction: domain.Upload, Source: "/tmp/a b", Target: "~/in box"},
+			want:     []string{"/tmp/a b", "web:~/'in box'"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scpArgs(tt.transfer, tt.sftpMode); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("scpArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
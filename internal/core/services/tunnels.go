@@ -0,0 +1,397 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+)
+
+const (
+	// tunnelStartupGrace is how long ssh must stay up before a tunnel counts as running;
+	// ExitOnForwardFailure makes it exit sooner when a port cannot be bound.
+	tunnelStartupGrace = 2 * time.Second
+	// tunnelStableAfter resets the restart backoff once a process ran that long.
+	tunnelStableAfter  = 30 * time.Second
+	tunnelMaxBackoff   = 30 * time.Second
+	tunnelMaxFailures  = 5
+	tunnelStopTimeout  = 5 * time.Second
+	tunnelAliveSeconds = "15"
+)
+
+// tunnelSupervisor keeps background `ssh -N` processes alive.
+type tunnelSupervisor struct {
+	mu      sync.Mutex
+	tunnels []*domain.Tunnel
+	stops   map[int]chan struct{}
+	done    map[int]chan struct{}
+	nextID  int
+}
+
+func newTunnelSupervisor() *tunnelSupervisor {
+	return &tunnelSupervisor{
+		stops: make(map[int]chan struct{}),
+		done:  make(map[int]chan struct{}),
+	}
+}
+
+// StartTunnel starts a supervised `ssh -N` for spec and returns its id. Without
+// forwards in spec, the ones configured for the server are used.
+func (s *serverService) StartTunnel(spec domain.TunnelSpec) (int, error) {
+	if _, err := exec.LookPath("ssh"); err != nil {
+		return 0, fmt.Errorf("ssh not found; install OpenSSH")
+	}
+	forwards, err := s.tunnelForwards(spec)
+	if err != nil {
+		return 0, err
+	}
+
+	t := s.tunnels
+	t.mu.Lock()
+	t.nextID++
+	tunnel := &domain.Tunnel{ID: t.nextID, Spec: spec, Forwards: forwards, State: domain.TunnelStarting}
+	t.tunnels = append(t.tunnels, tunnel)
+	t.mu.Unlock()
+
+	s.logger.Infow("tunnel start", "id", tunnel.ID, "alias", spec.Alias, "forwards", forwards)
+	s.runTunnel(tunnel.ID, spec)
+	return tunnel.ID, nil
+}
+
+// StopTunnel stops a tunnel and forgets it.
+func (s *serverService) StopTunnel(id int) error {
+	t := s.tunnels
+	if err := t.halt(id); err != nil {
+		return err
+	}
+	t.mu.Lock()
+	for i, tunnel := range t.tunnels {
+		if tunnel.ID == id {
+			t.tunnels = append(t.tunnels[:i], t.tunnels[i+1:]...)
+			break
+		}
+	}
+	t.mu.Unlock()
+	s.logger.Infow("tunnel stopped", "id", id)
+	return nil
+}
+
+// RestartTunnel stops the tunnel's process and starts it again with fresh counters.
+func (s *serverService) RestartTunnel(id int) error {
+	t := s.tunnels
+	if err := t.halt(id); err != nil {
+		return err
+	}
+	var spec domain.TunnelSpec
+	t.update(id, func(tunnel *domain.Tunnel) {
+		spec = tunnel.Spec
+		tunnel.State = domain.TunnelStarting
+		tunnel.Restarts = 0
+		tunnel.LastError = ""
+	})
+	s.logger.Infow("tunnel restart", "id", id)
+	s.runTunnel(id, spec)
+	return nil
+}
+
+// ListTunnels returns a snapshot of the tunnels in start order.
+func (s *serverService) ListTunnels() []domain.Tunnel {
+	t := s.tunnels
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	out := make([]domain.Tunnel, 0, len(t.tunnels))
+	for _, tunnel := range t.tunnels {
+		out = append(out, *tunnel)
+	}
+	return out
+}
+
+// StopAllTunnels tears every tunnel down; lazyssh calls it on exit.
+func (s *serverService) StopAllTunnels() {
+	for _, tunnel := range s.ListTunnels() {
+		if err := s.StopTunnel(tunnel.ID); err != nil {
+			s.logger.Warnw("failed to stop tunnel", "id", tunnel.ID, "error", err)
+		}
+	}
+}
+
+// tunnelForwards validates the forwards of spec, falling back to the server's
+// configured ones, and describes them for display.
+func (s *serverService) tunnelForwards(spec domain.TunnelSpec) ([]string, error) {
+	if !spec.AdHoc() {
+		servers, err := s.serverRepository.ListServers("")
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for _, srv := range servers {
+			if srv.Alias == spec.Alias {
+				spec.LocalForwards, spec.RemoteForwards, spec.DynamicForwards = srv.LocalForward, srv.RemoteForward, srv.DynamicForward
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("server '%s' not found", spec.Alias)
+		}
+		if !spec.AdHoc() {
+			return nil, fmt.Errorf("%s has no LocalForward, RemoteForward or DynamicForward; add one or open an ad-hoc tunnel", spec.Alias)
+		}
+	}
+
+	var out []string
+	for _, f := range spec.LocalForwards {
+		listen, dest, ok := splitForward(f)
+		if !ok || dest == "" {
+			return nil, fmt.Errorf("invalid local forward %q: want [bind:]port:host:hostport", f)
+		}
+		out = append(out, "L "+listen+" → "+dest)
+	}
+	for _, f := range spec.RemoteForwards {
+		listen, dest, ok := splitForward(f)
+		if !ok {
+			return nil, fmt.Errorf("invalid remote forward %q: want [bind:]port[:host:hostport]", f)
+		}
+		if dest == "" {
+			out = append(out, "R "+listen+" (SOCKS)")
+			continue
+		}
+		out = append(out, "R "+listen+" ← "+dest)
+	}
+	for _, f := range spec.DynamicForwards {
+		listen, dest, ok := splitForward(f)
+		if !ok || dest != "" {
+			return nil, fmt.Errorf("invalid dynamic forward %q: want [bind:]port", f)
+		}
+		out = append(out, "D "+listen+" (SOCKS)")
+	}
+	return out, nil
+}
+
+// splitForward splits a forward in CLI form ("[bind:]port:host:hostport") or
+// ssh_config form ("[bind:]port host:hostport") into its listen and destination
+// parts. dest is empty for a bare "[bind:]port".
+func splitForward(f string) (listen, dest string, ok bool) {
+	f = cliForward(f)
+	if f == "" {
+		return "", "", false
+	}
+	var parts []string
+	for rest := f; rest != ""; {
+		// Bracketed IPv6 addresses contain colons of their own.
+		if strings.HasPrefix(rest, "[") {
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return "", "", false
+			}
+			parts = append(parts, rest[:end+1])
+			rest = strings.TrimPrefix(rest[end+1:], ":")
+			continue
+		}
+		part, after, found := strings.Cut(rest, ":")
+		parts = append(parts, part)
+		if !found {
+			break
+		}
+		rest = after
+	}
+	for _, p := range parts {
+		if p == "" {
+			return "", "", false
+		}
+	}
+	switch len(parts) {
+	case 1:
+		return parts[0], "", true
+	case 2:
+		return parts[0] + ":" + parts[1], "", true
+	case 3:
+		return parts[0], parts[1] + ":" + parts[2], true
+	case 4:
+		return parts[0] + ":" + parts[1], parts[2] + ":" + parts[3], true
+	}
+	return "", "", false
+}
+
+// cliForward converts a forward from ssh_config syntax to the syntax of -L/-R/-D.
+func cliForward(f string) string {
+	return strings.Join(strings.Fields(f), ":")
+}
+
+// tunnelArgs builds the ssh command line. Ad-hoc forwards are added on the
+// command line, on top of any forwards configured for the server. ControlPath
+// none keeps each tunnel on its own connection so it can be supervised.
+func (s *serverService) tunnelArgs(spec domain.TunnelSpec) []string {
+	args := []string{
+		"-N",
+		"-o", "ExitOnForwardFailure=yes",
+		"-o", "BatchMode=yes",
+		"-o", "ControlPath=none",
+		"-o", "ServerAliveInterval=" + tunnelAliveSeconds,
+		"-o", "ServerAliveCountMax=3",
+	}
+	for _, f := range spec.LocalForwards {
+		args = append(args, "-L", cliForward(f))
+	}
+	for _, f := range spec.RemoteForwards {
+		args = append(args, "-R", cliForward(f))
+	}
+	for _, f := range spec.DynamicForwards {
+		args = append(args, "-D", cliForward(f))
+	}
+	return s.sshArgs(append(args, spec.Alias)...)
+}
+
+// runTunnel starts the supervising goroutine of a tunnel.
+func (s *serverService) runTunnel(id int, spec domain.TunnelSpec) {
+	t := s.tunnels
+	stop, done := make(chan struct{}), make(chan struct{})
+	t.mu.Lock()
+	t.stops[id], t.done[id] = stop, done
+	t.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		s.superviseTunnel(id, spec, stop)
+	}()
+}
+
+// superviseTunnel runs ssh until stop is closed, restarting it with exponential
+// backoff. After tunnelMaxFailures failures in a row the tunnel is marked failed.
+func (s *serverService) superviseTunnel(id int, spec domain.TunnelSpec, stop chan struct{}) {
+	t := s.tunnels
+	backoff := time.Second
+	failures := 0
+	for {
+		var stderr bytes.Buffer
+		// #nosec G204 -- arguments come from the user's SSH config or input
+		cmd := exec.Command("ssh", s.tunnelArgs(spec)...)
+		cmd.Stderr = &stderr
+		setTunnelProcAttr(cmd)
+		if err := cmd.Start(); err != nil {
+			t.update(id, func(tunnel *domain.Tunnel) {
+				tunnel.State, tunnel.LastError = domain.TunnelFailed, err.Error()
+			})
+			return
+		}
+		started := time.Now()
+		t.update(id, func(tunnel *domain.Tunnel) {
+			tunnel.PID, tunnel.StartedAt = cmd.Process.Pid, started
+		})
+
+		exited := make(chan error, 1)
+		go func() { exited <- cmd.Wait() }()
+		grace := time.NewTimer(tunnelStartupGrace)
+		var err error
+	wait:
+		for {
+			select {
+			case <-stop:
+				grace.Stop()
+				_ = cmd.Process.Kill()
+				<-exited
+				t.update(id, func(tunnel *domain.Tunnel) {
+					tunnel.State, tunnel.PID = domain.TunnelStopped, 0
+				})
+				return
+			case <-grace.C:
+				t.update(id, func(tunnel *domain.Tunnel) { tunnel.State = domain.TunnelRunning })
+			case err = <-exited:
+				grace.Stop()
+				break wait
+			}
+		}
+
+		msg := lastLine(stderr.String())
+		if msg == "" && err != nil {
+			msg = err.Error()
+		}
+		if msg == "" {
+			msg = "ssh exited"
+		}
+		if time.Since(started) >= tunnelStableAfter {
+			backoff, failures = time.Second, 0
+		}
+		failures++
+		s.logger.Warnw("tunnel exited", "id", id, "alias", spec.Alias, "error", msg, "failures", failures)
+		if failures >= tunnelMaxFailures {
+			t.update(id, func(tunnel *domain.Tunnel) {
+				tunnel.State, tunnel.PID, tunnel.LastError = domain.TunnelFailed, 0, msg
+			})
+			return
+		}
+		t.update(id, func(tunnel *domain.Tunnel) {
+			tunnel.State, tunnel.PID, tunnel.LastError = domain.TunnelRestarting, 0, msg
+			tunnel.Restarts++
+		})
+
+		select {
+		case <-stop:
+			t.update(id, func(tunnel *domain.Tunnel) { tunnel.State = domain.TunnelStopped })
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, tunnelMaxBackoff)
+		t.update(id, func(tunnel *domain.Tunnel) { tunnel.State = domain.TunnelStarting })
+	}
+}
+
+// halt stops the supervising goroutine of a tunnel and waits for its process to exit.
+func (t *tunnelSupervisor) halt(id int) error {
+	t.mu.Lock()
+	stop, ok := t.stops[id]
+	done := t.done[id]
+	known := false
+	for _, tunnel := range t.tunnels {
+		if tunnel.ID == id {
+			known = true
+			break
+		}
+	}
+	delete(t.stops, id)
+	delete(t.done, id)
+	t.mu.Unlock()
+
+	if !known {
+		return fmt.Errorf("tunnel %d not found", id)
+	}
+	if !ok {
+		return nil
+	}
+	close(stop)
+	select {
+	case <-done:
+		return nil
+	case <-time.After(tunnelStopTimeout):
+		return fmt.Errorf("tunnel %d did not stop in time", id)
+	}
+}
+
+func (t *tunnelSupervisor) update(id int, fn func(*domain.Tunnel)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, tunnel := range t.tunnels {
+		if tunnel.ID == id {
+			fn(tunnel)
+			return
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"go.uber.org/zap"
+)
+
+func TestTunnelForwardsAndArgs(t *testing.T) {
+	s := &serverService{sshConfigFile: "/tmp/cfg"}
+	spec := domain.TunnelSpec{
+		Alias:           "web",
+		LocalForwards:   []string{"8080:localhost:80", "127.0.0.1:5432 db:5432", "[::1]:8443:[2001:db8::1]:443"},
+		RemoteForwards:  []string{"9000:localhost:3000", "1081"},
+		DynamicForwards: []string{"1080"},
+	}
+	forwards, err := s.tunnelForwards(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantForwards := []string{
+		"L 8080 → localhost:80", "L 127.0.0.1:5432 → db:5432", "L [::1]:8443 → [2001:db8::1]:443",
+		"R 9000 ← localhost:3000", "R 1081 (SOCKS)", "D 1080 (SOCKS)",
+	}
+	if !reflect.DeepEqual(forwards, wantForwards) {
+		t.Errorf("tunnelForwards() = %q, want %q", forwards, wantForwards)
+	}
+
+	args := s.tunnelArgs(spec)
+	wantTail := []string{
+		"-L", "8080:localhost:80", "-L", "127.0.0.1:5432:db:5432", "-L", "[::1]:8443:[2001:db8::1]:443",
+		"-R", "9000:localhost:3000", "-R", "1081", "-D", "1080", "web",
+	}
+	if args[0] != "-F" || args[1] != "/tmp/cfg" || args[2] != "-N" {
+		t.Errorf("tunnelArgs() starts with %q", args[:3])
+	}
+	if got := args[len(args)-len(wantTail):]; !reflect.DeepEqual(got, wantTail) {
+		t.Errorf("tunnelArgs() ends with %q, want %q", got, wantTail)
+	}
+
+	for _, bad := range []domain.TunnelSpec{
+		{Alias: "web", LocalForwards: []string{"8080"}},
+		{Alias: "web", LocalForwards: []string{"8080::80"}},
+		{Alias: "web", DynamicForwards: []string{"1080:localhost:22"}},
+	} {
+		if _, err := s.tunnelForwards(bad); err == nil {
+			t.Errorf("tunnelForwards(%+v) should fail", bad)
+		}
+	}
+}
+
+func TestTunnelSupervision(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as fake ssh")
+	}
+	dir := t.TempDir()
+	script := "#!/bin/sh\nexec sleep 30\n"
+	if err := os.WriteFile(filepath.Join(dir, "ssh"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	s := &serverService{logger: zap.NewNop().Sugar(), tunnels: newTunnelSupervisor()}
+	id, err := s.StartTunnel(domain.TunnelSpec{Alias: "web", DynamicForwards: []string{"1080"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(tunnelStartupGrace + 3*time.Second)
+	for {
+		tunnels := s.ListTunnels()
+		if len(tunnels) == 1 && tunnels[0].State == domain.TunnelRunning {
+			if tunnels[0].PID == 0 {
+				t.Error("running tunnel has no PID")
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("tunnel did not reach running: %+v", tunnels)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	if err := s.StopTunnel(id); err != nil {
+		t.Fatal(err)
+	}
+	if tunnels := s.ListTunnels(); len(tunnels) != 0 {
+		t.Errorf("tunnels after stop = %+v", tunnels)
+	}
+	if err := s.StopTunnel(id); err == nil {
+		t.Error("stopping an unknown tunnel should fail")
+	}
+}
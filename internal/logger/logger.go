@@ -23,31 +23,42 @@ import (
 )
 
 // New constructs a Sugared Logger that writes to a file and
-// provides human-readable timestamps.
-func New(service string, outputPaths ...string) (*zap.SugaredLogger, error) {
+// provides human-readable timestamps. An empty level means debug, and
+// without output paths it logs to ~/.lazyssh/lazyssh.log.
+func New(service, level string, outputPaths ...string) (*zap.SugaredLogger, error) {
 	config := zap.NewProductionConfig()
 
 	config.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
 	config.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
+	if level != "" {
+		lvl, err := zapcore.ParseLevel(level)
+		if err != nil {
+			return nil, err
+		}
+		config.Level = zap.NewAtomicLevelAt(lvl)
+	}
 
 	config.DisableStacktrace = true
 	config.InitialFields = map[string]any{
 		"service": service,
 	}
 
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return nil, err
+	if len(outputPaths) == 0 {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		outputPaths = []string{filepath.Join(home, ".lazyssh", "lazyssh.log")}
 	}
-	logDir := filepath.Join(home, ".lazyssh")
-	if err := os.MkdirAll(logDir, 0o750); err != nil {
-		return nil, err
-	}
-	config.OutputPaths = []string{filepath.Join(logDir, "lazyssh.log")}
-
-	if outputPaths != nil {
-		config.OutputPaths = outputPaths
+	for _, p := range outputPaths {
+		if p == "stdout" || p == "stderr" {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+			return nil, err
+		}
 	}
+	config.OutputPaths = outputPaths
 
 	log, err := config.Build(zap.WithCaller(true))
 	if err != nil {
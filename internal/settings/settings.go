@@ -0,0 +1,418 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package settings
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Adembc/lazyssh/internal/core/domain"
+	"github.com/spf13/pflag"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix prefixes the environment variables that override the settings file.
+const EnvPrefix = "LAZYSSH_"
+
+// Sort modes accepted by the sort setting.
+var SortModes = []string{"alias", "alias-desc", "last-seen", "last-seen-asc", "reachability", "reachability-desc"}
+
+// Settings holds the user preferences. They come from the settings file
+// (~/.lazyssh/config.yaml by default), then LAZYSSH_* environment variables,
+// then command-line flags, each overriding the previous one.
+type Settings struct {
+	SSHConfig   string          `yaml:"ssh_config"`
+	Metadata    string          `yaml:"metadata"`
+	Log         LogSettings     `yaml:"log"`
+	Sort        string          `yaml:"sort"`
+	PingTimeout time.Duration   `yaml:"ping_timeout"`
+	MaxBackups  int             `yaml:"max_backups"`
+	Backups     BackupSettings  `yaml:"backups"`
+	Git         GitSettings     `yaml:"git"`
+	Monitor     MonitorSettings `yaml:"monitor"`
+	Files       FileSettings    `yaml:"files"`
+	Workspaces  []Workspace     `yaml:"workspaces"`
+	// Workspace names the workspace to start in; empty means the default one.
+	Workspace string `yaml:"workspace"`
+}
+
+// DefaultWorkspace is the name of the workspace made of the top-level ssh_config and metadata.
+const DefaultWorkspace = "default"
+
+// Workspace is a named SSH config tree with its own metadata (tags, pins, history).
+// Empty fields are filled in by Resolve: the metadata lives in
+// ~/.lazyssh/workspaces/<name>/metadata.json and the sort mode is the top-level one.
+type Workspace struct {
+	Name      string `yaml:"name"`
+	SSHConfig string `yaml:"ssh_config"`
+	Metadata  string `yaml:"metadata"`
+	Sort      string `yaml:"sort"`
+}
+
+// LogSettings configures the log file.
+type LogSettings struct {
+	File  string `yaml:"file"`
+	Level string `yaml:"level"`
+}
+
+// BackupSettings configures where the backups of the SSH config files are kept
+// and how long; max_backups limits their number.
+type BackupSettings struct {
+	// Dir keeps the backups of all config files in one directory, e.g.
+	// ~/.lazyssh/backups; empty means next to the SSH config.
+	Dir string `yaml:"dir"`
+	// MaxAge drops rolling backups older than this; 0 keeps them.
+	MaxAge time.Duration `yaml:"max_age"`
+	// MaxSize caps the total size of the rolling backups of each file; 0 means no cap.
+	MaxSize ByteSize `yaml:"max_size"`
+	// Compress gzips the rolling backups.
+	Compress bool `yaml:"compress"`
+}
+
+// GitSettings configures versioning of the SSH config files with git.
+type GitSettings struct {
+	// Enabled commits every change lazyssh writes to a config file to the local
+	// git repository holding it; the main config's directory becomes one if
+	// needed. Nothing is pushed.
+	Enabled bool `yaml:"enabled"`
+}
+
+// ByteSize is a size in bytes, written as a number or with a K, M or G suffix
+// (powers of 1024), e.g. "512K" or "10MB".
+type ByteSize int64
+
+// UnmarshalYAML parses a size such as 1048576, "512K" or "10MB".
+func (b *ByteSize) UnmarshalYAML(value *yaml.Node) error {
+	n, err := ParseByteSize(value.Value)
+	if err != nil {
+		return err
+	}
+	*b = n
+	return nil
+}
+
+// ParseByteSize parses a size such as "1048576", "512K", "10MB" or "1GiB".
+func ParseByteSize(s string) (ByteSize, error) {
+	num := strings.ToUpper(strings.TrimSpace(s))
+	num = strings.TrimSuffix(strings.TrimSuffix(num, "IB"), "B")
+	unit := int64(1)
+	if num != "" {
+		switch num[len(num)-1] {
+		case 'K':
+			unit = 1 << 10
+		case 'M':
+			unit = 1 << 20
+		case 'G':
+			unit = 1 << 30
+		}
+		if unit > 1 {
+			num = num[:len(num)-1]
+		}
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(num), 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q, use e.g. 512K or 10MB", s)
+	}
+	return ByteSize(n * unit), nil
+}
+
+// MonitorSettings configures the background health monitor of the TUI, which
+// probes the SSH port of every server (or of the servers with one of Tags).
+// It is off unless Enabled is set.
+type MonitorSettings struct {
+	Enabled     bool          `yaml:"enabled"`
+	Interval    time.Duration `yaml:"interval"`
+	Concurrency int           `yaml:"concurrency"`
+	Tags        []string      `yaml:"tags"`
+	// Probe is "tcp" (default) to only connect, or "banner" to also read the SSH
+	// banner and host key algorithms.
+	Probe string `yaml:"probe"`
+	// History is the number of probe results kept per server.
+	History int `yaml:"history"`
+}
+
+// FileSettings declares by glob which SSH config files lazyssh may write.
+// Patterns may be absolute, start with ~, or be relative to the main config's directory.
+type FileSettings struct {
+	// Includes is the access of included files matching no pattern: "writable" (default) or "readonly".
+	Includes string   `yaml:"includes"`
+	Writable []string `yaml:"writable"`
+	Readonly []string `yaml:"readonly"`
+	Hidden   []string `yaml:"hidden"`
+}
+
+// Flags are the command-line overrides. Empty values leave the setting unchanged.
+type Flags struct {
+	Settings  string
+	SSHConfig string
+	Metadata  string
+	LogLevel  string
+	Workspace string
+}
+
+// Register adds the flags to fs.
+func (f *Flags) Register(fs *pflag.FlagSet) {
+	fs.StringVar(&f.Settings, "settings", "", "lazyssh settings file (default ~/.lazyssh/config.yaml)")
+	fs.StringVar(&f.SSHConfig, "config", "", "SSH config file (default ~/.ssh/config)")
+	fs.StringVar(&f.Metadata, "metadata", "", "lazyssh metadata file (default ~/.lazyssh/metadata.json)")
+	fs.StringVar(&f.LogLevel, "log-level", "", "log level: debug, info, warn or error")
+	fs.StringVarP(&f.Workspace, "workspace", "w", "", "workspace to use (see workspaces in the settings file)")
+}
+
+// Defaults returns the settings used when nothing overrides them.
+func Defaults(home string) Settings {
+	return Settings{
+		SSHConfig: filepath.Join(home, ".ssh", "config"),
+		Metadata:  filepath.Join(home, ".lazyssh", "metadata.json"),
+		Log: LogSettings{
+			File:  filepath.Join(home, ".lazyssh", "lazyssh.log"),
+			Level: "debug",
+		},
+		Sort:        "alias",
+		PingTimeout: 3 * time.Second,
+		MaxBackups:  10,
+		Monitor: MonitorSettings{
+			Interval:    time.Minute,
+			Concurrency: 8,
+			History:     30,
+		},
+	}
+}
+
+// DefaultPath returns the location of the settings file under the given home directory.
+func DefaultPath(home string) string {
+	return filepath.Join(home, ".lazyssh", "config.yaml")
+}
+
+// Resolve builds the settings from the defaults, the settings file, the
+// environment (looked up with getenv) and the flags.
+func Resolve(home string, flags Flags, getenv func(string) string) (Settings, error) {
+	path := DefaultPath(home)
+	if v := getenv(EnvPrefix + "SETTINGS"); v != "" {
+		path = v
+	}
+	if flags.Settings != "" {
+		path = flags.Settings
+	}
+
+	s, err := Load(expandTilde(path, home), Defaults(home))
+	if err != nil {
+		return s, err
+	}
+	if err := s.applyEnv(getenv); err != nil {
+		return s, err
+	}
+	s.applyFlags(flags)
+
+	s.SSHConfig = expandTilde(s.SSHConfig, home)
+	s.Metadata = expandTilde(s.Metadata, home)
+	s.Log.File = expandTilde(s.Log.File, home)
+	s.Backups.Dir = expandTilde(s.Backups.Dir, home)
+	for i := range s.Workspaces {
+		w := &s.Workspaces[i]
+		w.SSHConfig = expandTilde(w.SSHConfig, home)
+		if w.Metadata == "" {
+			w.Metadata = filepath.Join(home, ".lazyssh", "workspaces", w.Name, "metadata.json")
+		}
+		w.Metadata = expandTilde(w.Metadata, home)
+		if w.Sort == "" {
+			w.Sort = s.Sort
+		}
+	}
+	if err := s.validate(); err != nil {
+		return s, err
+	}
+	return s, nil
+}
+
+// AllWorkspaces returns the default workspace followed by the configured ones.
+func (s Settings) AllWorkspaces() []Workspace {
+	all := make([]Workspace, 0, len(s.Workspaces)+1)
+	all = append(all, Workspace{
+		Name:      DefaultWorkspace,
+		SSHConfig: s.SSHConfig,
+		Metadata:  s.Metadata,
+		Sort:      s.Sort,
+	})
+	return append(all, s.Workspaces...)
+}
+
+// CurrentWorkspace returns the index in AllWorkspaces of the workspace to start in.
+func (s Settings) CurrentWorkspace() int {
+	for i, w := range s.AllWorkspaces() {
+		if w.Name == s.Workspace {
+			return i
+		}
+	}
+	return 0
+}
+
+// Load reads the settings file at path on top of base. A missing file yields base.
+func Load(path string, base Settings) (Settings, error) {
+	s := base
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, fmt.Errorf("failed to read settings: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("failed to parse settings %s: %w", path, err)
+	}
+	if err := s.validate(); err != nil {
+		return s, fmt.Errorf("invalid settings %s: %w", path, err)
+	}
+	return s, nil
+}
+
+func (s *Settings) applyEnv(getenv func(string) string) error {
+	strs := map[string]*string{
+		"SSH_CONFIG": &s.SSHConfig,
+		"METADATA":   &s.Metadata,
+		"LOG_FILE":   &s.Log.File,
+		"LOG_LEVEL":  &s.Log.Level,
+		"SORT":       &s.Sort,
+		"WORKSPACE":  &s.Workspace,
+		"BACKUP_DIR": &s.Backups.Dir,
+	}
+	for name, target := range strs {
+		if v := getenv(EnvPrefix + name); v != "" {
+			*target = v
+		}
+	}
+	if v := getenv(EnvPrefix + "PING_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid %sPING_TIMEOUT: %w", EnvPrefix, err)
+		}
+		s.PingTimeout = d
+	}
+	if v := getenv(EnvPrefix + "MAX_BACKUPS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid %sMAX_BACKUPS: %w", EnvPrefix, err)
+		}
+		s.MaxBackups = n
+	}
+	return nil
+}
+
+func (s *Settings) applyFlags(f Flags) {
+	if f.SSHConfig != "" {
+		s.SSHConfig = f.SSHConfig
+	}
+	if f.Metadata != "" {
+		s.Metadata = f.Metadata
+	}
+	if f.LogLevel != "" {
+		s.Log.Level = f.LogLevel
+	}
+	if f.Workspace != "" {
+		s.Workspace = f.Workspace
+	}
+}
+
+func (s Settings) validate() error {
+	if s.Log.Level != "" {
+		if _, err := zapcore.ParseLevel(s.Log.Level); err != nil {
+			return fmt.Errorf("log level: %w", err)
+		}
+	}
+	if s.Sort != "" && !slices.Contains(SortModes, s.Sort) {
+		return fmt.Errorf("sort must be one of %s, got %q", strings.Join(SortModes, ", "), s.Sort)
+	}
+	if s.PingTimeout < 0 {
+		return fmt.Errorf("ping_timeout must not be negative")
+	}
+	if s.MaxBackups < 0 {
+		return fmt.Errorf("max_backups must not be negative")
+	}
+	if s.Backups.MaxAge < 0 {
+		return fmt.Errorf("backups.max_age must not be negative")
+	}
+	if s.Monitor.Enabled && s.Monitor.Interval < time.Second {
+		return fmt.Errorf("monitor.interval must be at least 1s, got %s", s.Monitor.Interval)
+	}
+	if s.Monitor.Concurrency < 0 || s.Monitor.History < 0 {
+		return fmt.Errorf("monitor.concurrency and monitor.history must not be negative")
+	}
+	switch s.Monitor.Probe {
+	case "", "tcp", "banner":
+	default:
+		return fmt.Errorf("monitor.probe must be \"tcp\" or \"banner\", got %q", s.Monitor.Probe)
+	}
+	switch s.Files.Includes {
+	case "", "writable", "readonly":
+	default:
+		return fmt.Errorf("files.includes must be \"writable\" or \"readonly\", got %q", s.Files.Includes)
+	}
+	for _, list := range [][]string{s.Files.Writable, s.Files.Readonly, s.Files.Hidden} {
+		for _, pattern := range list {
+			if _, err := filepath.Match(pattern, ""); err != nil {
+				return fmt.Errorf("bad file pattern %q: %w", pattern, err)
+			}
+		}
+	}
+	return s.validateWorkspaces()
+}
+
+func (s Settings) validateWorkspaces() error {
+	names := map[string]bool{DefaultWorkspace: true}
+	for _, w := range s.Workspaces {
+		switch {
+		case w.Name == "":
+			return fmt.Errorf("every workspace needs a name")
+		case names[w.Name]:
+			return fmt.Errorf("duplicate workspace name %q", w.Name)
+		case w.SSHConfig == "":
+			return fmt.Errorf("workspace %q needs an ssh_config", w.Name)
+		case w.Sort != "" && !slices.Contains(SortModes, w.Sort):
+			return fmt.Errorf("workspace %q: unknown sort mode %q", w.Name, w.Sort)
+		}
+		names[w.Name] = true
+	}
+	if s.Workspace != "" && !names[s.Workspace] {
+		return fmt.Errorf("unknown workspace %q", s.Workspace)
+	}
+	return nil
+}
+
+// FilePolicy converts the file settings for the SSH config repository.
+func (s Settings) FilePolicy() domain.FilePolicy {
+	return domain.FilePolicy{
+		Writable:         s.Files.Writable,
+		Readonly:         s.Files.Readonly,
+		Hidden:           s.Files.Hidden,
+		IncludesReadonly: s.Files.Includes == "readonly",
+	}
+}
+
+func expandTilde(p, home string) string {
+	if p == "~" {
+		return home
+	}
+	if strings.HasPrefix(p, "~/") {
+		return filepath.Join(home, p[2:])
+	}
+	return p
+}
@@ -0,0 +1,218 @@
+// Copyright 2025.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	s, err := Load(filepath.Join(dir, "missing.yaml"), Settings{})
+	if err != nil {
+		t.Fatalf("Load() of a missing file error = %v", err)
+	}
+	if p := s.FilePolicy(); p.IncludesReadonly || len(p.Readonly) != 0 {
+		t.Errorf("unexpected default policy: %+v", p)
+	}
+
+	path := filepath.Join(dir, "config.yaml")
+	content := `files:
+  includes: readonly
+  writable:
+    - config.d/personal*
+  hidden:
+    - ~/.ssh/legacy
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s, err = Load(path, Settings{})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	p := s.FilePolicy()
+	if !p.IncludesReadonly || len(p.Writable) != 1 || p.Writable[0] != "config.d/personal*" || len(p.Hidden) != 1 {
+		t.Errorf("unexpected policy: %+v", p)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown includes":         "files:\n  includes: sometimes\n",
+		"bad pattern":              "files:\n  readonly:\n    - \"[\"\n",
+		"not yaml":                 "files: [",
+		"unknown sort":             "sort: random\n",
+		"bad log level":            "log:\n  level: loud\n",
+		"bad duration":             "ping_timeout: soon\n",
+		"monitor interval too low": "monitor:\n  enabled: true\n  interval: 10ms\n",
+		"unknown monitor probe":    "monitor:\n  probe: ssh\n",
+		"bad backup size":          "backups:\n  max_size: lots\n",
+		"negative backup age":      "backups:\n  max_age: -1h\n",
+		"unnamed workspace":        "workspaces:\n  - ssh_config: /tmp/config\n",
+		"duplicate workspace":      "workspaces:\n  - name: default\n    ssh_config: /tmp/config\n",
+		"workspace without config": "workspaces:\n  - name: acme\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Load(path, Settings{}); err == nil {
+				t.Error("Load() accepted invalid settings")
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	home := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(home, ".lazyssh"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	content := `ssh_config: ~/work/ssh_config
+metadata: /srv/lazyssh/metadata.json
+sort: last-seen
+ping_timeout: 5s
+max_backups: 3
+backups:
+  dir: ~/.lazyssh/backups
+  max_age: 720h
+  max_size: 5MB
+  compress: true
+git:
+  enabled: true
+log:
+  level: info
+monitor:
+  enabled: true
+  interval: 30s
+  tags: [prod]
+`
+	if err := os.WriteFile(DefaultPath(home), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{
+		"LAZYSSH_METADATA":    "/tmp/env-metadata.json",
+		"LAZYSSH_MAX_BACKUPS": "0",
+		"LAZYSSH_LOG_LEVEL":   "warn",
+	}
+	flags := Flags{LogLevel: "error"}
+
+	s, err := Resolve(home, flags, func(k string) string { return env[k] })
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	want := Defaults(home)
+	want.SSHConfig = filepath.Join(home, "work", "ssh_config") // file, with ~ expanded
+	want.Metadata = "/tmp/env-metadata.json"                   // env over file
+	want.Sort = "last-seen"
+	want.PingTimeout = 5 * time.Second
+	want.MaxBackups = 0
+	want.Log.Level = "error" // flag over env over file
+	if s.SSHConfig != want.SSHConfig || s.Metadata != want.Metadata || s.Sort != want.Sort ||
+		s.PingTimeout != want.PingTimeout || s.MaxBackups != want.MaxBackups ||
+		s.Log != want.Log {
+		t.Errorf("Resolve() = %+v, want %+v", s, want)
+	}
+
+	wantBackups := BackupSettings{Dir: filepath.Join(home, ".lazyssh", "backups"), MaxAge: 720 * time.Hour, MaxSize: 5 << 20, Compress: true}
+	if s.Backups != wantBackups {
+		t.Errorf("Resolve().Backups = %+v, want %+v", s.Backups, wantBackups)
+	}
+	if !s.Git.Enabled {
+		t.Error("Resolve().Git.Enabled = false, want true")
+	}
+
+	if m := s.Monitor; !m.Enabled || m.Interval != 30*time.Second || m.Concurrency != want.Monitor.Concurrency ||
+		len(m.Tags) != 1 || m.Tags[0] != "prod" {
+		t.Errorf("Resolve().Monitor = %+v, want defaults merged with the file", m)
+	}
+
+	if _, err := Resolve(home, Flags{Settings: filepath.Join(home, "missing.yaml"), LogLevel: "chatty"}, func(string) string { return "" }); err == nil {
+		t.Error("Resolve() accepted an invalid log level flag")
+	}
+}
+
+func TestResolveWorkspaces(t *testing.T) {
+	home := t.TempDir()
+	path := filepath.Join(home, "settings.yaml")
+	content := `sort: last-seen
+workspaces:
+  - name: acme
+    ssh_config: ~/clients/acme/config
+  - name: globex
+    ssh_config: /srv/globex/ssh_config
+    metadata: ~/globex.json
+    sort: alias-desc
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Resolve(home, Flags{Settings: path, Workspace: "globex"}, func(string) string { return "" })
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	all := s.AllWorkspaces()
+	if len(all) != 3 || all[0].Name != DefaultWorkspace || all[0].SSHConfig != filepath.Join(home, ".ssh", "config") {
+		t.Fatalf("unexpected workspaces: %+v", all)
+	}
+	acme := all[1]
+	if acme.SSHConfig != filepath.Join(home, "clients", "acme", "config") ||
+		acme.Metadata != filepath.Join(home, ".lazyssh", "workspaces", "acme", "metadata.json") ||
+		acme.Sort != "last-seen" {
+		t.Errorf("unexpected defaults for acme: %+v", acme)
+	}
+	if globex := all[2]; globex.Metadata != filepath.Join(home, "globex.json") || globex.Sort != "alias-desc" {
+		t.Errorf("unexpected globex: %+v", globex)
+	}
+	if got := s.CurrentWorkspace(); got != 2 {
+		t.Errorf("CurrentWorkspace() = %d, want 2", got)
+	}
+
+	if _, err := Resolve(home, Flags{Settings: path, Workspace: "initech"}, func(string) string { return "" }); err == nil {
+		t.Error("Resolve() accepted an unknown workspace")
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ByteSize
+		ok   bool
+	}{
+		{"1048576", 1 << 20, true},
+		{"512K", 512 << 10, true},
+		{"10MB", 10 << 20, true},
+		{"1GiB", 1 << 30, true},
+		{" 2 mb ", 2 << 20, true},
+		{"0", 0, true},
+		{"lots", 0, false},
+		{"-5M", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseByteSize(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseByteSize(%q) = %d, %v; want %d, ok %t", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}